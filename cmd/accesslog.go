@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// probeRoutes are polled by Kubernetes and Prometheus every few seconds and are left out of the access
+// log unless ACCESS_LOG_PROBES=true
+var probeRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// withAccessLog logs a line per request with its method, route template, status code, body size and
+// duration. Like the metrics, lines name the route template rather than the path, so namespaces and
+// object names stay out of them. The line is logged with the request context and carries its
+// requestId; server errors are logged as warnings. Routes in skip are not logged.
+func withAccessLog(skip map[string]bool) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := routeTemplate(r)
+			if skip[route] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+			next.ServeHTTP(recorder, r)
+
+			level := slog.LevelInfo
+			if recorder.status >= http.StatusInternalServerError {
+				level = slog.LevelWarn
+			}
+			slog.Log(r.Context(), level, "HTTP request", "method", r.Method, "route", route,
+				"status", recorder.status, "bytes", recorder.bytes, "duration", time.Since(start))
+		})
+	}
+}
+
+// accessLogSkip returns the routes left out of the access log: the probes and /metrics, unless
+// ACCESS_LOG_PROBES=true
+func accessLogSkip() map[string]bool {
+	if strings.ToLower(os.Getenv("ACCESS_LOG_PROBES")) == "true" {
+		return nil
+	}
+	return probeRoutes
+}
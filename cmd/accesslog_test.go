@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s-object-explorer/internal/logging"
+
+	"github.com/gorilla/mux"
+)
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(&buf, "json", slog.LevelInfo)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	router := mux.NewRouter()
+	router.Use(withRequestID)
+	router.Use(withAccessLog(probeRoutes))
+	router.HandleFunc("/api/resources/{namespace}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+	})
+	router.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	req := httptest.NewRequest(http.MethodGet, "/api/resources/team-a", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one access log line with /healthz skipped, got:\n%s", buf.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log output is not a JSON line: %v\n%s", err, lines[0])
+	}
+
+	expected := map[string]interface{}{
+		"level":     "WARN",
+		"method":    "GET",
+		"route":     "/api/resources/{namespace}",
+		"status":    float64(http.StatusServiceUnavailable),
+		"bytes":     float64(len("No Kubernetes connection\n")),
+		"requestId": "abc-123",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("log %s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("expected a duration in the log line")
+	}
+	if strings.Contains(lines[0], "team-a") {
+		t.Error("expected the route template, not the namespace, in the log line")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// unauthenticatedPaths are the probe routes, which the kubelet calls without credentials
+var unauthenticatedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// withBasicAuth requires the AUTH_USERNAME/AUTH_PASSWORD credentials on every route except the
+// liveness and readiness probes. Without a configured username and password requests pass through
+// unchanged.
+func (s *Server) withBasicAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.authUsername == "" && s.authPassword == "" || unauthenticatedPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		username, password, ok := r.BasicAuth()
+		if !ok || !credentialsMatch(username, s.authUsername) || !credentialsMatch(password, s.authPassword) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="k8s-object-explorer", charset="UTF-8"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// credentialsMatch compares in constant time. Hashing first keeps the comparison from leaking the
+// length of the configured value.
+func credentialsMatch(given, want string) bool {
+	givenHash := sha256.Sum256([]byte(given))
+	wantHash := sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(givenHash[:], wantHash[:]) == 1
+}
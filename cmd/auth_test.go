@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func newAuthTestRouter(server *Server) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(server.withBasicAuth)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/healthz", ok)
+	router.HandleFunc("/readyz", ok)
+	router.HandleFunc("/api/namespaces", ok)
+	router.PathPrefix("/").HandlerFunc(ok)
+	return router
+}
+
+func TestBasicAuth(t *testing.T) {
+	router := newAuthTestRouter(&Server{authUsername: "admin", authPassword: "s3cret"})
+
+	tests := []struct {
+		name      string
+		path      string
+		user      string
+		password  string
+		setAuth   bool
+		want      int
+		challenge bool
+	}{
+		{"correct credentials", "/api/namespaces", "admin", "s3cret", true, http.StatusOK, false},
+		{"wrong password", "/api/namespaces", "admin", "wrong", true, http.StatusUnauthorized, true},
+		{"wrong username", "/api/namespaces", "root", "s3cret", true, http.StatusUnauthorized, true},
+		{"missing credentials", "/api/namespaces", "", "", false, http.StatusUnauthorized, true},
+		{"static files", "/index.html", "", "", false, http.StatusUnauthorized, true},
+		{"liveness probe", "/healthz", "", "", false, http.StatusOK, false},
+		{"readiness probe", "/readyz", "", "", false, http.StatusOK, false},
+		{"probe prefix", "/healthz/extra", "", "", false, http.StatusUnauthorized, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.setAuth {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := rec.Header().Get("WWW-Authenticate") != ""; got != tt.challenge {
+				t.Errorf("WWW-Authenticate set = %t, want %t", got, tt.challenge)
+			}
+		})
+	}
+}
+
+func TestBasicAuthDisabled(t *testing.T) {
+	router := newAuthTestRouter(&Server{})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/namespaces", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d without AUTH_USERNAME/AUTH_PASSWORD", rec.Code, http.StatusOK)
+	}
+}
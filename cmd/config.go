@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// resolveDefaultNamespace returns the namespace the UI opens on: DEFAULT_NAMESPACE, else the namespace of
+// the request's kubeconfig context, else "default"
+func (s *Server) resolveDefaultNamespace(r *http.Request) string {
+	if s.defaultNamespace != "" {
+		return s.defaultNamespace
+	}
+	if client := s.client(r); client != nil && client.CurrentNamespace() != "" {
+		return client.CurrentNamespace()
+	}
+	return "default"
+}
+
+func (s *Server) getConfig(w http.ResponseWriter, r *http.Request) {
+	// Several contexts let the UI offer a cluster picker; in-cluster there is no kubeconfig at all
+	contexts, _, err := k8s.ListContexts(s.kubeconfig)
+	multiCluster := err == nil && len(contexts) > 1
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"defaultNamespace": s.resolveDefaultNamespace(r),
+		"debug":            s.debug,
+		"multiCluster":     multiCluster,
+	})
+}
+
+// withDefaultNamespace redirects the bare root to ?namespace=<default namespace> so the UI opens on
+// a namespace instead of an empty picker. Other query parameters, such as ?context=, are kept.
+func (s *Server) withDefaultNamespace(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/" || r.Method != http.MethodGet || query.Has("namespace") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		query.Set("namespace", s.resolveDefaultNamespace(r))
+		target := url.URL{Path: "/", RawQuery: query.Encode()}
+		http.Redirect(w, r, target.String(), http.StatusFound)
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolveDefaultNamespace(t *testing.T) {
+	server := newContextTestServer(t)
+	resolve := func(target string) string {
+		var namespace string
+		handler := server.withKubeContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			namespace = server.resolveDefaultNamespace(r)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+		return namespace
+	}
+
+	// Without a client, or with a context that sets no namespace, fall back to "default"
+	if namespace := resolve("/api/config"); namespace != "default" {
+		t.Errorf("expected default without a client, got %q", namespace)
+	}
+	if namespace := resolve("/api/config?context=staging"); namespace != "default" {
+		t.Errorf("expected default for a context without a namespace, got %q", namespace)
+	}
+
+	// The kubeconfig context's namespace comes next
+	if namespace := resolve("/api/config?context=production"); namespace != "payments" {
+		t.Errorf("expected the context namespace payments, got %q", namespace)
+	}
+
+	// DEFAULT_NAMESPACE wins over both
+	server.defaultNamespace = "platform"
+	if namespace := resolve("/api/config?context=production"); namespace != "platform" {
+		t.Errorf("expected DEFAULT_NAMESPACE platform, got %q", namespace)
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	server := newContextTestServer(t)
+	server.debug = true
+
+	rec := httptest.NewRecorder()
+	server.getConfig(rec, httptest.NewRequest(http.MethodGet, "/api/config", nil))
+
+	var body struct {
+		DefaultNamespace string `json:"defaultNamespace"`
+		Debug            bool   `json:"debug"`
+		MultiCluster     bool   `json:"multiCluster"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.DefaultNamespace != "default" || !body.Debug || !body.MultiCluster {
+		t.Errorf("unexpected config %+v", body)
+	}
+
+	// In-cluster there is no kubeconfig, and so a single cluster
+	server.kubeconfig = "/nonexistent/config"
+	rec = httptest.NewRecorder()
+	server.getConfig(rec, httptest.NewRequest(http.MethodGet, "/api/config", nil))
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.MultiCluster {
+		t.Error("expected multiCluster false without a kubeconfig")
+	}
+}
+
+func TestWithDefaultNamespace(t *testing.T) {
+	server := newContextTestServer(t)
+	server.defaultNamespace = "platform"
+	handler := server.withDefaultNamespace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tc := range []struct {
+		target   string
+		location string
+	}{
+		{"/", "/?namespace=platform"},
+		{"/?context=production", "/?context=production&namespace=platform"},
+		{"/?namespace=team-a", ""},
+		{"/index.html", ""},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.target, nil))
+		if tc.location == "" {
+			if rec.Code != http.StatusNoContent {
+				t.Errorf("%s: expected to be served, got %d", tc.target, rec.Code)
+			}
+			continue
+		}
+		if rec.Code != http.StatusFound || rec.Header().Get("Location") != tc.location {
+			t.Errorf("%s: expected a redirect to %s, got %d %q", tc.target, tc.location, rec.Code, rec.Header().Get("Location"))
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// clientContextKey is the request context key of the client selected with ?context=
+type clientContextKey struct{}
+
+// client returns the Kubernetes client for the request: the one selected with ?context=, or the
+// client of the current kubeconfig context
+func (s *Server) client(r *http.Request) *k8s.Client {
+	if client, ok := r.Context().Value(clientContextKey{}).(*k8s.Client); ok {
+		return client
+	}
+	return s.k8sClient
+}
+
+// withKubeContext resolves the ?context= query parameter to a client for that kubeconfig context
+func (s *Server) withKubeContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contextName := r.URL.Query().Get("context")
+		if contextName == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client, status, err := s.clientForContext(contextName)
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientContextKey{}, client)))
+	})
+}
+
+// clientForContext returns the cached client for a kubeconfig context, creating it on first use.
+// On failure it also returns the HTTP status to report.
+func (s *Server) clientForContext(contextName string) (*k8s.Client, int, error) {
+	s.contextMu.Lock()
+	defer s.contextMu.Unlock()
+
+	if client, exists := s.contextClients[contextName]; exists {
+		return client, http.StatusOK, nil
+	}
+
+	contexts, _, err := k8s.ListContexts(s.kubeconfig)
+	if err != nil {
+		return nil, http.StatusServiceUnavailable, err
+	}
+	if !contains(contexts, contextName) {
+		return nil, http.StatusNotFound, fmt.Errorf("unknown kubeconfig context %q", contextName)
+	}
+
+	client, err := k8s.NewClientForContext(s.kubeconfig, contextName)
+	if err != nil {
+		return nil, http.StatusServiceUnavailable, err
+	}
+	slog.Info("🔗 Created client for kubeconfig context", "context", contextName)
+
+	s.contextClients[contextName] = client
+	return client, http.StatusOK, nil
+}
+
+func (s *Server) getContexts(w http.ResponseWriter, r *http.Request) {
+	contexts, current, err := k8s.ListContexts(s.kubeconfig)
+	if err != nil {
+		// In-cluster deployments have no kubeconfig and only their own cluster
+		slog.DebugContext(r.Context(), "No kubeconfig contexts", "error", err)
+		contexts, current = []string{}, ""
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"contexts": contexts,
+		"current":  current,
+		"count":    len(contexts),
+	})
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
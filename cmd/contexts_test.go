@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com:6443
+- name: production
+  cluster:
+    server: https://production.example.com:6443
+contexts:
+- name: staging
+  context:
+    cluster: staging
+- name: production
+  context:
+    cluster: production
+    namespace: payments
+`
+
+func newContextTestServer(t *testing.T) *Server {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+	return &Server{kubeconfig: path, contextClients: make(map[string]*k8s.Client)}
+}
+
+func TestGetContexts(t *testing.T) {
+	server := newContextTestServer(t)
+
+	rec := httptest.NewRecorder()
+	server.getContexts(rec, httptest.NewRequest(http.MethodGet, "/api/contexts", nil))
+
+	var body struct {
+		Contexts []string `json:"contexts"`
+		Current  string   `json:"current"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Contexts) != 2 || body.Contexts[0] != "production" || body.Contexts[1] != "staging" {
+		t.Errorf("expected [production staging], got %v", body.Contexts)
+	}
+	if body.Current != "staging" {
+		t.Errorf("expected current context staging, got %q", body.Current)
+	}
+}
+
+func TestContextSelection(t *testing.T) {
+	server := newContextTestServer(t)
+
+	var selected *k8s.Client
+	handler := server.withKubeContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		selected = server.client(r)
+	}))
+
+	// Without ?context= the default client is used
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/namespaces", nil))
+	if selected != nil {
+		t.Errorf("expected the default (nil) client without a context, got %p", selected)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/namespaces?context=production", nil))
+	if rec.Code != http.StatusOK || selected == nil {
+		t.Fatalf("expected a client for context production, got status %d", rec.Code)
+	}
+	production := selected
+
+	// Clients are created once per context and reused
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/namespaces?context=production", nil))
+	if selected != production {
+		t.Error("expected the production client to be reused")
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/namespaces?context=staging", nil))
+	if selected == production {
+		t.Error("expected a different client for context staging")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/namespaces?context=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown context: expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s-object-explorer/internal/k8s"
+
+	"github.com/gorilla/mux"
+)
+
+// newJSONRequest returns a request with body declared as application/json, like the UI sends
+func newJSONRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestRequireJSON(t *testing.T) {
+	for contentType, want := range map[string]bool{
+		"application/json":                  true,
+		"application/json; charset=utf-8":   true,
+		"":                                  false,
+		"text/plain":                        false,
+		"application/x-www-form-urlencoded": false,
+		"multipart/form-data; boundary=x":   false,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/warm-cache", strings.NewReader("{}"))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		rec := httptest.NewRecorder()
+		if got := requireJSON(rec, req); got != want {
+			t.Errorf("Content-Type %q: requireJSON = %t, want %t", contentType, got, want)
+		}
+		if !want && rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("Content-Type %q: expected 415, got %d", contentType, rec.Code)
+		}
+	}
+}
+
+// A form on another site can post text/plain with a JSON-looking body; state-changing handlers must
+// refuse it before doing anything
+func TestStateChangingHandlersRejectFormPosts(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+	vars := map[string]string{"namespace": "default", "resource": "deployments", "name": "web"}
+
+	for name, handle := range map[string]func(http.ResponseWriter, *http.Request){
+		"deleteCollection": server.deleteCollection,
+		"scaleObject":      server.scaleObject,
+		"warmCache":        server.warmCache,
+	} {
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"labelSelector": "", "replicas": 0, "namespaces": []}`)), vars)
+		req.Header.Set("Content-Type", "text/plain")
+		rec := httptest.NewRecorder()
+		handle(rec, req)
+		if rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("%s: expected 415 for a text/plain body, got %d", name, rec.Code)
+		}
+	}
+}
+
+func TestClearCacheRejectsCrossSite(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	req := httptest.NewRequest(http.MethodPost, "http://explorer.example.com/api/clear-cache", nil)
+	req.Header.Set("Origin", "https://evil.example.org")
+	rec := httptest.NewRecorder()
+	server.clearCache(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a cross-site request, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "http://explorer.example.com/api/clear-cache", nil)
+	req.Header.Set("Origin", "http://explorer.example.com")
+	rec = httptest.NewRecorder()
+	server.clearCache(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a same-origin request, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCrossSiteRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{"API client", nil, false},
+		{"same origin", map[string]string{"Origin": "http://explorer.example.com", "Sec-Fetch-Site": "same-origin"}, false},
+		{"other origin", map[string]string{"Origin": "https://evil.example.org"}, true},
+		{"cross-site fetch", map[string]string{"Sec-Fetch-Site": "cross-site"}, true},
+		{"opaque origin", map[string]string{"Origin": "null"}, true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://explorer.example.com/api/object-raw/default/secrets/db", nil)
+		for key, value := range tt.headers {
+			req.Header.Set(key, value)
+		}
+		if got := crossSiteRequest(req); got != tt.want {
+			t.Errorf("%s: crossSiteRequest = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
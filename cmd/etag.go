@@ -0,0 +1,49 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// etagFor hashes a response payload together with the time its data was cached, so the tag changes
+// both when the data changes and when the cache is refreshed
+func etagFor(cacheTime time.Time, payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	hash.Write(data)
+	hash.Write([]byte(cacheTime.UTC().Format(time.RFC3339Nano)))
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`, nil
+}
+
+// notModified sets the ETag of a response and answers 304 Not Modified when the request's
+// If-None-Match already carries it, in which case the caller must not write a body
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	// Make browsers revalidate every poll instead of reusing the response heuristically
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly as RFC 9110
+// requires for If-None-Match
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+	cached := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	resources := []string{"pods", "configmaps"}
+	etag, err := etagFor(cached, resources)
+	if err != nil {
+		t.Fatalf("etagFor returned error: %v", err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if notModified(w, r, etag) {
+			return
+		}
+		w.Write([]byte(`{"resources":["pods","configmaps"]}`))
+	})
+
+	// The first request gets the body and its ETag
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/namespace/default/resources", nil))
+	if rec.Code != http.StatusOK || rec.Body.Len() == 0 || rec.Header().Get("ETag") != etag {
+		t.Fatalf("expected 200 with ETag %s, got %d %q", etag, rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// Revalidating with that ETag gets an empty 304
+	for _, header := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/namespace/default/resources", nil)
+		req.Header.Set("If-None-Match", header)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: expected an empty 304, got %d", header, rec.Code)
+		}
+	}
+
+	// A refreshed cache or changed resources give a new ETag
+	refreshed, _ := etagFor(cached.Add(time.Minute), resources)
+	changed, _ := etagFor(cached, []string{"pods"})
+	if refreshed == etag || changed == etag {
+		t.Error("expected the ETag to change with the cache time and the resources")
+	}
+	req := httptest.NewRequest(http.MethodGet, "/api/namespace/default/resources", nil)
+	req.Header.Set("If-None-Match", refreshed)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("stale If-None-Match: expected 200, got %d", rec.Code)
+	}
+}
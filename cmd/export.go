@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// exportConcurrency returns the number of namespaces /api/export-all counts at once: as many scans
+// of COUNT_CONCURRENCY counts as fit in half of GLOBAL_COUNT_LIMIT, two with the defaults, so a
+// cluster-wide export leaves the other half of the count slots to page loads. It is at least one,
+// which a GLOBAL_COUNT_LIMIT below twice COUNT_CONCURRENCY still lets exceed that half.
+func exportConcurrency(client *k8s.Client) int {
+	_, limit := client.CountsInFlight()
+	if perScan := client.CountConcurrency(); perScan > 0 && limit/2/perScan > 1 {
+		return limit / 2 / perScan
+	}
+	return 1
+}
+
+// exportAllResourcesCSV serves the resources of every namespace as one CSV with a leading Namespace
+// column. Namespaces are counted concurrently, from the cache where their counts are fresh, and each
+// namespace's rows are flushed as soon as it and the namespaces before it are done, so the download
+// starts before the whole cluster has been scanned.
+func (s *Server) exportAllResourcesCSV(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	namespaces, err := client.GetNamespaces(r.Context())
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Exporting resources of all namespaces", "namespaces", len(namespaces))
+	start := time.Now()
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"k8s-resources-all-namespaces.csv\"")
+
+	rows, err := writeAllNamespacesCSV(r.Context(), w, namespaces, exportConcurrency(client), client.GetResourcesInNamespace)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Failed to write CSV export", "error", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Exported resources of all namespaces", "namespaces", len(namespaces),
+		"rows", rows, "duration", time.Since(start))
+}
+
+// namespaceResources is the outcome of counting the resources of one namespace
+type namespaceResources struct {
+	resources []k8s.ResourceInfo
+	err       error
+}
+
+// writeAllNamespacesCSV writes the header and then the rows of each namespace in order, counting up
+// to workers namespaces ahead with count. Namespaces that fail to count are logged and
+// left out, since the response has already started. It returns the number of rows written.
+func writeAllNamespacesCSV(ctx context.Context, w io.Writer, namespaces []string, workers int,
+	count func(context.Context, string) ([]k8s.ResourceInfo, error)) (int, error) {
+	results := make([]chan namespaceResources, len(namespaces))
+	for i := range results {
+		results[i] = make(chan namespaceResources, 1)
+	}
+
+	indexes := make(chan int)
+	go func() {
+		defer close(indexes)
+		for i := range namespaces {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for worker := 0; worker < workers; worker++ {
+		go func() {
+			for i := range indexes {
+				resources, err := count(ctx, namespaces[i])
+				results[i] <- namespaceResources{resources, err}
+			}
+		}()
+	}
+
+	flusher, _ := w.(http.Flusher)
+	writer := csv.NewWriter(w)
+	writer.Write(append([]string{"Namespace"}, resourceCSVHeader...))
+
+	rows := 0
+	for i, namespace := range namespaces {
+		var result namespaceResources
+		select {
+		case result = <-results[i]:
+		case <-ctx.Done():
+			writer.Flush()
+			return rows, ctx.Err()
+		}
+		if result.err != nil {
+			slog.WarnContext(ctx, "Leaving namespace out of the export", "namespace", namespace, "error", result.err)
+			continue
+		}
+
+		for _, resource := range result.resources {
+			writer.Write(append([]string{namespace}, resourceCSVRow(resource)...))
+			rows++
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return rows, err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	// Namespaces that failed because the request was cancelled make the export incomplete
+	return rows, ctx.Err()
+}
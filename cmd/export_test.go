@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+func TestWriteAllNamespacesCSV(t *testing.T) {
+	counts := map[string][]k8s.ResourceInfo{
+		"default": {
+			{Name: "pods", Kind: "Pod", APIVersion: "v1", Namespaced: true, Count: 3},
+			{Name: "deployments", Kind: "Deployment", APIGroup: "apps", APIVersion: "v1", Namespaced: true, Count: 1},
+		},
+		"payments": {
+			{Name: "pods", Kind: "Pod", APIVersion: "v1", Namespaced: true, Count: 7},
+		},
+	}
+	count := func(ctx context.Context, namespace string) ([]k8s.ResourceInfo, error) {
+		// The first namespace finishes last, yet its rows must still come first
+		if namespace == "default" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if namespace == "forbidden" {
+			return nil, k8s.ErrForbidden
+		}
+		return counts[namespace], nil
+	}
+
+	var buf bytes.Buffer
+	rows, err := writeAllNamespacesCSV(context.Background(), &buf, []string{"default", "forbidden", "payments"}, 2, count)
+	if err != nil {
+		t.Fatalf("writeAllNamespacesCSV returned error: %v", err)
+	}
+	if rows != 3 {
+		t.Errorf("expected 3 rows, got %d", rows)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export does not parse as CSV: %v\n%s", err, buf.String())
+	}
+	expected := [][]string{
+		{"Namespace", "Resource Name", "Kind", "API Group", "API Version", "Namespaced", "Count"},
+		{"default", "pods", "Pod", "core", "v1", "true", "3"},
+		{"default", "deployments", "Deployment", "apps", "v1", "true", "1"},
+		{"payments", "pods", "Pod", "core", "v1", "true", "7"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %q", len(expected), records)
+	}
+	for i := range expected {
+		if strings.Join(records[i], ",") != strings.Join(expected[i], ",") {
+			t.Errorf("record %d: expected %q, got %q", i, expected[i], records[i])
+		}
+	}
+}
+
+func TestWriteAllNamespacesCSVCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	count := func(ctx context.Context, namespace string) ([]k8s.ResourceInfo, error) {
+		return nil, ctx.Err()
+	}
+
+	var buf bytes.Buffer
+	if _, err := writeAllNamespacesCSV(ctx, &buf, []string{"default", "payments"}, 2, count); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestExportConcurrency(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	kubeconfig := `apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: test
+  context:
+    cluster: test
+`
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	cases := map[string]struct {
+		globalLimit, countConcurrency string
+		expected                      int
+	}{
+		"defaults":            {"", "", 2},
+		"larger limit":        {"64", "", 4},
+		"limit below 2 scans": {"8", "", 1},
+		"fewer per scan":      {"", "4", 4},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("GLOBAL_COUNT_LIMIT", c.globalLimit)
+			t.Setenv("COUNT_CONCURRENCY", c.countConcurrency)
+			client, err := k8s.NewClient(path)
+			if err != nil {
+				t.Fatalf("NewClient returned error: %v", err)
+			}
+			if got := exportConcurrency(client); got != c.expected {
+				t.Errorf("expected %d, got %d", c.expected, got)
+			}
+		})
+	}
+
+	if got := exportConcurrency(&k8s.Client{}); got != 1 {
+		t.Errorf("expected 1 without a count limit, got %d", got)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// objectSections are the sections of an object ?fields= may select. The name, namespace, kind and
+// apiVersion identify the object and are always included.
+var objectSections = []string{"metadata", "spec", "status", "labels", "annotations"}
+
+// parseFields parses a comma-separated ?fields= list into the set of sections to include. An empty
+// list selects every section, which is reported as nil.
+func parseFields(raw string) (map[string]bool, error) {
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		if !contains(objectSections, field) {
+			return nil, fmt.Errorf("unknown field %q: must be one of %s", field, strings.Join(objectSections, ", "))
+		}
+		fields[field] = true
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// projectObjects keeps only the selected sections of objects. metadata covers the creation time,
+// age and owner references, status also covers live usage.
+func projectObjects(objects []k8s.ObjectInfo, fields map[string]bool) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(objects))
+	for i, object := range objects {
+		p := map[string]interface{}{
+			"name":       object.Name,
+			"kind":       object.Kind,
+			"apiVersion": object.APIVersion,
+		}
+		if object.Namespace != "" {
+			p["namespace"] = object.Namespace
+		}
+		if fields["metadata"] {
+			p["creationTimestamp"] = object.CreationTimestamp
+			p["creationTimestampUnix"] = object.CreationTimestampUnix
+			p["age"] = object.Age
+			if len(object.OwnerReferences) > 0 {
+				p["ownerReferences"] = object.OwnerReferences
+			}
+		}
+		if fields["labels"] && len(object.Labels) > 0 {
+			p["labels"] = object.Labels
+		}
+		if fields["annotations"] && len(object.Annotations) > 0 {
+			p["annotations"] = object.Annotations
+		}
+		if fields["spec"] && len(object.Spec) > 0 {
+			p["spec"] = object.Spec
+		}
+		if fields["status"] {
+			if len(object.Status) > 0 {
+				p["status"] = object.Status
+			}
+			if object.Usage != nil {
+				p["usage"] = object.Usage
+			}
+		}
+		projected[i] = p
+	}
+	return projected
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+func TestParseFields(t *testing.T) {
+	for raw, want := range map[string]map[string]bool{
+		"":                       nil,
+		" , ":                    nil,
+		"metadata":               {"metadata": true},
+		"labels, Annotations,,":  {"labels": true, "annotations": true},
+		"spec,status,spec":       {"spec": true, "status": true},
+		"metadata,labels,status": {"metadata": true, "labels": true, "status": true},
+	} {
+		fields, err := parseFields(raw)
+		if err != nil {
+			t.Errorf("parseFields(%q) returned error: %v", raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(fields, want) {
+			t.Errorf("parseFields(%q) = %v, want %v", raw, fields, want)
+		}
+	}
+
+	if _, err := parseFields("metadata,secrets"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestProjectObjects(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	objects := []k8s.ObjectInfo{{
+		Name:              "web",
+		Namespace:         "default",
+		Kind:              "Deployment",
+		APIVersion:        "apps/v1",
+		CreationTimestamp: created,
+		Age:               "5d",
+		Labels:            map[string]string{"app": "web"},
+		Annotations:       map[string]string{"owner": "team-a"},
+		Spec:              map[string]interface{}{"replicas": int64(3)},
+		Status:            map[string]interface{}{"readyReplicas": int64(3)},
+	}}
+
+	// Only metadata: identity and metadata, no labels, spec or status
+	lean := projectObjects(objects, map[string]bool{"metadata": true})[0]
+	for _, key := range []string{"name", "namespace", "kind", "apiVersion", "creationTimestamp", "age"} {
+		if _, ok := lean[key]; !ok {
+			t.Errorf("metadata projection: expected %s", key)
+		}
+	}
+	for _, key := range []string{"labels", "annotations", "spec", "status"} {
+		if _, ok := lean[key]; ok {
+			t.Errorf("metadata projection: expected no %s", key)
+		}
+	}
+
+	// Every section gives the full object
+	full := projectObjects(objects, map[string]bool{"metadata": true, "spec": true, "status": true, "labels": true, "annotations": true})[0]
+	if !reflect.DeepEqual(full["spec"], objects[0].Spec) || !reflect.DeepEqual(full["status"], objects[0].Status) {
+		t.Errorf("full projection: expected spec and status, got %v", full)
+	}
+	if !reflect.DeepEqual(full["labels"], objects[0].Labels) || !reflect.DeepEqual(full["annotations"], objects[0].Annotations) {
+		t.Errorf("full projection: expected labels and annotations, got %v", full)
+	}
+	if full["creationTimestamp"] != created {
+		t.Errorf("full projection: expected creation time %v, got %v", created, full["creationTimestamp"])
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// maxImpersonationClients is the number of impersonating clients kept between requests. Every
+// distinct user and group set the headers name creates one, so the least recently used is dropped
+// beyond this and recreated if it is asked for again.
+const maxImpersonationClients = 32
+
+// impersonationKey identifies a cached impersonating client by the client it was derived from and
+// the impersonated identity
+type impersonationKey struct {
+	base   *k8s.Client
+	user   string
+	groups string
+}
+
+type impersonationEntry struct {
+	key    impersonationKey
+	client *k8s.Client
+}
+
+// withImpersonation switches the request to a client impersonating the X-Impersonate-User and
+// X-Impersonate-Group headers, so admins can preview what another identity can see. It applies on
+// top of ?context= and is refused unless ALLOW_IMPERSONATION is enabled. The headers are trusted as
+// sent: any caller may assert any user and group, system:masters included, as far as the server's
+// own credentials are allowed to impersonate them.
+func (s *Server) withImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := r.Header.Get("X-Impersonate-User")
+		groups := impersonationGroups(r.Header.Values("X-Impersonate-Group"))
+		if user == "" && len(groups) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !s.allowImpersonation {
+			http.Error(w, "Impersonation is disabled (set ALLOW_IMPERSONATION=true)", http.StatusForbidden)
+			return
+		}
+		if user == "" {
+			http.Error(w, "X-Impersonate-Group requires X-Impersonate-User", http.StatusBadRequest)
+			return
+		}
+
+		base := s.client(r)
+		if base == nil {
+			http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+			return
+		}
+
+		client, err := s.impersonatingClient(base, user, groups)
+		if err != nil {
+			writeClientError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientContextKey{}, client)))
+	})
+}
+
+// impersonationGroups splits repeated and comma-separated group headers into a sorted list
+func impersonationGroups(values []string) []string {
+	var groups []string
+	for _, value := range values {
+		for _, group := range strings.Split(value, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// impersonatingClient returns the cached client impersonating user and groups on top of base,
+// creating it on first use so its caches survive between requests. Beyond maxImpersonationClients the
+// least recently used client is evicted and its watches stopped.
+func (s *Server) impersonatingClient(base *k8s.Client, user string, groups []string) (*k8s.Client, error) {
+	key := impersonationKey{base: base, user: user, groups: strings.Join(groups, ",")}
+
+	s.impersonationMu.Lock()
+	defer s.impersonationMu.Unlock()
+
+	if element, exists := s.impersonationClients[key]; exists {
+		s.impersonationOrder.MoveToFront(element)
+		return element.Value.(*impersonationEntry).client, nil
+	}
+
+	client, err := base.Impersonate(user, groups)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("🎭 Created client impersonating user", "user", user, "groups", groups)
+
+	if s.impersonationClients == nil {
+		s.impersonationClients = make(map[impersonationKey]*list.Element)
+		s.impersonationOrder = list.New()
+	}
+	s.impersonationClients[key] = s.impersonationOrder.PushFront(&impersonationEntry{key: key, client: client})
+	for s.impersonationOrder.Len() > maxImpersonationClients {
+		oldest := s.impersonationOrder.Remove(s.impersonationOrder.Back()).(*impersonationEntry)
+		delete(s.impersonationClients, oldest.key)
+		oldest.client.StopWatches()
+		slog.Debug("Evicted client impersonating user", "user", oldest.key.user)
+	}
+	return client, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// newImpersonationTestServer returns a Server whose client talks to a fake API server that records
+// the Impersonate-User header of every namespace list
+func newImpersonationTestServer(t *testing.T, allow bool) (*Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var users []string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		users = append(users, r.Header.Get("Impersonate-User"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"apiVersion":"v1","kind":"NamespaceList","items":[{"metadata":{"name":"default"}}]}`)
+	}))
+	t.Cleanup(apiServer.Close)
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+`, apiServer.URL)
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	client, err := k8s.NewClient(path)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	return &Server{k8sClient: client, allowImpersonation: allow}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), users...)
+	}
+}
+
+func TestImpersonationHeaders(t *testing.T) {
+	t.Setenv("IMPERSONATE_USER", "")
+	server, users := newImpersonationTestServer(t, true)
+	handler := server.withImpersonation(http.HandlerFunc(server.getNamespaces))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/namespaces", nil)
+		req.Header.Set("X-Impersonate-User", "system:serviceaccount:team-a:viewer")
+		req.Header.Add("X-Impersonate-Group", "team-a")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/namespaces", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 without impersonation, got %d", rec.Code)
+	}
+
+	got := users()
+	want := []string{"system:serviceaccount:team-a:viewer", "system:serviceaccount:team-a:viewer", ""}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d API requests, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d: expected Impersonate-User %q, got %q", i, want[i], got[i])
+		}
+	}
+	if len(server.impersonationClients) != 1 {
+		t.Errorf("expected the impersonating client to be reused, got %d clients", len(server.impersonationClients))
+	}
+}
+
+func TestImpersonationRefused(t *testing.T) {
+	server, users := newImpersonationTestServer(t, false)
+	handler := server.withImpersonation(http.HandlerFunc(server.getNamespaces))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/namespaces", nil)
+	req.Header.Set("X-Impersonate-User", "admin")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without ALLOW_IMPERSONATION, got %d", rec.Code)
+	}
+
+	server.allowImpersonation = true
+	req = httptest.NewRequest(http.MethodGet, "/api/namespaces", nil)
+	req.Header.Set("X-Impersonate-Group", "system:masters")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for groups without a user, got %d", rec.Code)
+	}
+
+	if len(users()) != 0 {
+		t.Errorf("expected no API requests, got %v", users())
+	}
+}
+
+func TestImpersonatingClientsEvicted(t *testing.T) {
+	server, _ := newImpersonationTestServer(t, true)
+	base := server.k8sClient
+
+	first, err := server.impersonatingClient(base, "user-0", nil)
+	if err != nil {
+		t.Fatalf("impersonatingClient returned error: %v", err)
+	}
+	for i := 1; i <= maxImpersonationClients; i++ {
+		if i == maxImpersonationClients/2 {
+			// Using user-0 again keeps it from being the least recently used
+			if again, _ := server.impersonatingClient(base, "user-0", nil); again != first {
+				t.Fatalf("expected the cached client for user-0")
+			}
+		}
+		if _, err := server.impersonatingClient(base, fmt.Sprintf("user-%d", i), nil); err != nil {
+			t.Fatalf("impersonatingClient returned error: %v", err)
+		}
+	}
+
+	if len(server.impersonationClients) != maxImpersonationClients {
+		t.Errorf("expected %d cached clients, got %d", maxImpersonationClients, len(server.impersonationClients))
+	}
+	if _, cached := server.impersonationClients[impersonationKey{base: base, user: "user-1"}]; cached {
+		t.Errorf("expected the least recently used client, user-1, to be evicted")
+	}
+	if again, _ := server.impersonatingClient(base, "user-0", nil); again != first {
+		t.Errorf("expected the recently used client for user-0 to be kept")
+	}
+}
@@ -1,19 +1,33 @@
 package main
 
 import (
+	"container/list"
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"k8s-object-explorer/internal/k8s"
+	"k8s-object-explorer/internal/logging"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // Version information - set via ldflags at build time
@@ -24,79 +38,247 @@ var (
 )
 
 type Server struct {
-	k8sClient *k8s.Client
+	k8sClient *k8s.Client // client for the current kubeconfig context
+	k8sErr    error       // why k8sClient could not be created, reported by /api/status
 	debug     bool
+
+	// Clients for other kubeconfig contexts, selected with ?context= and created on first use
+	kubeconfig     string
+	contextMu      sync.Mutex
+	contextClients map[string]*k8s.Client
+
+	// Clients impersonating the X-Impersonate-User/Group headers, only used with ALLOW_IMPERSONATION
+	allowImpersonation   bool
+	impersonationMu      sync.Mutex
+	impersonationClients map[impersonationKey]*list.Element // -> element of impersonationOrder
+	impersonationOrder   *list.List                         // most recently used first
+
+	// Namespace the UI opens on, overriding the kubeconfig context's (DEFAULT_NAMESPACE)
+	defaultNamespace string
+
+	// Honor ?reveal=true on object endpoints, returning Secret values unredacted
+	allowSecretReveal bool
+
+	// HTTP Basic Auth credentials required on every route but /healthz and /readyz, disabled when both are empty
+	authUsername string
+	authPassword string
 }
 
 func main() {
+	kubeconfig := flag.String("kubeconfig", "", "path to the kubeconfig file (default: $KUBECONFIG, then ~/.kube/config)")
+	flag.Parse()
+
+	logging.Setup()
+
 	// Print version information
-	log.Printf("🚀 Kubernetes Object Explorer")
-	log.Printf("   Version: %s", Version)
-	log.Printf("   Build Date: %s", BuildDate)
-	log.Printf("   Git Commit: %s", GitCommit)
-	log.Println()
+	slog.Info("🚀 Kubernetes Object Explorer", "version", Version, "buildDate", BuildDate, "gitCommit", GitCommit)
 
 	// Initialize Kubernetes client
-	k8sClient, err := k8s.NewClient("")
-	if err != nil {
-		log.Printf("Warning: Failed to initialize Kubernetes client: %v", err)
-		log.Printf("The application will start but Kubernetes features will be unavailable")
+	k8sClient, k8sErr := k8s.NewClient(*kubeconfig)
+	if k8sErr != nil {
+		slog.Warn("Failed to initialize Kubernetes client; Kubernetes features will be unavailable", "error", k8sErr)
 	}
 
 	// Debug mode from environment
 	debugEnv := strings.ToLower(os.Getenv("DEBUG"))
 	debug := debugEnv == "true" || debugEnv == "1" || debugEnv == "yes"
 
-	server := &Server{k8sClient: k8sClient, debug: debug}
+	allowImpersonation := strings.ToLower(os.Getenv("ALLOW_IMPERSONATION")) == "true"
+
+	server := &Server{
+		k8sClient:          k8sClient,
+		k8sErr:             k8sErr,
+		debug:              debug,
+		kubeconfig:         *kubeconfig,
+		contextClients:     make(map[string]*k8s.Client),
+		allowImpersonation: allowImpersonation,
+		allowSecretReveal:  strings.ToLower(os.Getenv("ALLOW_SECRET_REVEAL")) == "true",
+		defaultNamespace:   os.Getenv("DEFAULT_NAMESPACE"),
+		authUsername:       os.Getenv("AUTH_USERNAME"),
+		authPassword:       os.Getenv("AUTH_PASSWORD"),
+	}
+	if server.authUsername != "" || server.authPassword != "" {
+		slog.Info("🔒 HTTP Basic Auth enabled", "username", server.authUsername)
+	}
 
 	// Setup routes
 	router := mux.NewRouter()
+	router.Use(withRequestID)
+	router.Use(withMetrics)
+	router.Use(withAccessLog(accessLogSkip()))
+	router.Use(server.withBasicAuth)
+	router.Use(server.withKubeContext)
+	router.Use(server.withImpersonation)
+	router.Use(withLimits(requestTimeout(), maxRequestBodyBytes()))
 
 	// Static file serving setup first
-	webDir := "web"
-	if _, err := os.Stat(webDir); os.IsNotExist(err) {
-		execPath, _ := os.Executable()
-		execDir := filepath.Dir(execPath)
-		webDir = filepath.Join(execDir, "..", "web")
-		if _, err := os.Stat(webDir); os.IsNotExist(err) {
-			webDir = filepath.Join(".", "web")
-		}
-	}
+	webFiles, webSource := webFileSystem()
 
 	// API routes (must be registered before static file handler)
+	router.HandleFunc("/healthz", server.healthz).Methods("GET")
+	router.Handle("/metrics", promhttp.Handler()).Methods("GET")
+	router.HandleFunc("/readyz", server.readyz).Methods("GET")
+	router.HandleFunc("/api/config", server.getConfig).Methods("GET")
+	router.HandleFunc("/api/contexts", server.getContexts).Methods("GET")
 	router.HandleFunc("/api/namespaces", server.getNamespaces).Methods("GET")
+	router.HandleFunc("/api/apigroups", server.getAPIGroups).Methods("GET")
+	router.HandleFunc("/api/crds", server.getCRDs).Methods("GET")
+	router.HandleFunc("/api/api-resources", server.getAPIResources).Methods("GET")
+	router.HandleFunc("/api/cluster-info", server.getClusterInfo).Methods("GET")
+	router.HandleFunc("/api/resources", server.getAllNamespacesResources).Methods("GET")
 	router.HandleFunc("/api/resources/{namespace}", server.getNamespaceResources).Methods("GET")
+	router.HandleFunc("/api/cluster-resources", server.getClusterResources).Methods("GET")
+	router.HandleFunc("/api/resource-summary/{namespace}", server.getResourceSummary).Methods("GET")
+	router.HandleFunc("/api/resource-count-history/{namespace}", server.getResourceCountHistory).Methods("GET")
+	router.HandleFunc("/api/status", server.getStatus).Methods("GET")
+	router.HandleFunc("/api/status/{namespace}", server.getNamespaceStatus).Methods("GET")
 	router.HandleFunc("/api/debug-stream/{namespace}", server.getDebugStream).Methods("GET")
+	router.HandleFunc("/api/watch/{namespace}/{resource}", server.watchResourceObjects).Methods("GET")
 	router.HandleFunc("/api/objects/{namespace}/{resource}", server.getResourceObjects).Methods("GET")
+	router.HandleFunc("/api/objects-meta/{namespace}/{resource}", server.getResourceObjectsMetadata).Methods("GET")
+	router.HandleFunc("/api/objects-table/{namespace}/{resource}", server.getResourceTable).Methods("GET")
 	router.HandleFunc("/api/object/{namespace}/{resource}/{name}", server.getObjectDetails).Methods("GET")
+	router.HandleFunc("/api/object/{namespace}/{resource}/{name}", server.deleteObject).Methods("DELETE")
+	router.HandleFunc("/api/object/{namespace}/{resource}/{name}/scale", server.scaleObject).Methods("PATCH")
+	router.HandleFunc("/api/object/{namespace}/{resource}/{name}/references", server.getObjectReferences).Methods("GET")
+	router.HandleFunc("/api/object/{namespace}/{resource}/{name}/events", server.getObjectEvents).Methods("GET")
+	router.HandleFunc("/api/object/{namespace}/{resource}/{name}/owners", server.getObjectOwners).Methods("GET")
+	router.HandleFunc("/api/object/{namespace}/{resource}/{name}/related", server.getRelatedObjects).Methods("GET")
 	router.HandleFunc("/api/object-raw/{namespace}/{resource}/{name}", server.getRawObjectDetails).Methods("GET")
+	router.HandleFunc("/api/logs/{namespace}/{pod}", server.getPodLogs).Methods("GET")
+	router.HandleFunc("/api/object-diff", server.getObjectDiff).Methods("GET")
+	router.HandleFunc("/api/namespace-dump/{namespace}", server.getNamespaceDump).Methods("GET")
+	router.HandleFunc("/api/search/{namespace}", server.searchObjects).Methods("GET")
+	router.HandleFunc("/api/health/{namespace}", server.getNamespaceHealth).Methods("GET")
+	router.HandleFunc("/api/total/{resource}", server.getResourceTotal).Methods("GET")
+	router.HandleFunc("/api/resource-schema/{resource}", server.getResourceSchema).Methods("GET")
 	router.HandleFunc("/api/export/{namespace}", server.exportResourcesCSV).Methods("GET")
+	router.HandleFunc("/api/export-all", server.exportAllResourcesCSV).Methods("GET")
+	router.HandleFunc("/api/delete-collection/{namespace}/{resource}", server.deleteCollection).Methods("POST")
 	router.HandleFunc("/api/debug", server.debugStatus).Methods("GET")
 	router.HandleFunc("/api/clear-cache", server.clearCache).Methods("POST")
+	router.HandleFunc("/api/warm-cache", server.warmCache).Methods("POST")
+	router.HandleFunc("/api/compare", server.compareNamespaces).Methods("GET")
+	router.HandleFunc("/api/namespace-groups", server.getNamespaceGroups).Methods("GET")
 
-	// Serve static files (this must be last as it's a catch-all)
-	router.PathPrefix("/").Handler(http.FileServer(http.Dir(webDir + "/")))
+	// Serve static files (this must be last as it's a catch-all), opening the UI on the default namespace
+	router.PathPrefix("/").Handler(server.withDefaultNamespace(http.FileServer(webFiles)))
 
 	// Start server
+	addr := listenAddress()
+
+	certFile, keyFile := tlsFiles()
+	scheme := "http"
+	if certFile != "" {
+		scheme = "https"
+	}
+
+	slog.Info("🚀 Simple Kubernetes Explorer starting", "address", addr, "web", webSource, "connected", k8sClient != nil)
+	slog.Info(fmt.Sprintf("🌐 Open %s://%s in your browser", scheme, browserAddress(addr)))
+	if debug {
+		slog.Info("🛠️ Debug mode enabled (ENV DEBUG=true)")
+	}
+
+	srv := &http.Server{Addr: addr, Handler: router}
+
+	// Stop on SIGINT/SIGTERM, letting in-flight requests finish within the grace period
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Count every namespace ahead of user requests; stops with ctx on shutdown
+	if k8sClient != nil && strings.ToLower(os.Getenv("PREWARM_NAMESPACES")) == "true" {
+		go k8sClient.RunPrewarmer(ctx, prewarmInterval())
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- listenAndServe(srv, certFile, keyFile)
+	}()
+
+	select {
+	case err := <-serverErr:
+		slog.Error("Server failed", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	stop()
+
+	gracePeriod := shutdownGracePeriod()
+	slog.Info("🛑 Shutdown signal received, waiting for in-flight requests", "gracePeriod", gracePeriod)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Graceful shutdown incomplete, closing remaining connections", "error", err)
+		srv.Close()
+	}
+	if k8sClient != nil {
+		k8sClient.StopWatches()
+	}
+	slog.Info("👋 Server stopped")
+}
+
+// listenAddress joins BIND_ADDRESS and PORT into the address to listen on. An empty BIND_ADDRESS
+// listens on all interfaces; IPv6 addresses are bracketed.
+func listenAddress() string {
 	port := "8080"
 	if envPort := os.Getenv("PORT"); envPort != "" {
 		port = envPort
 	}
+	host := strings.Trim(os.Getenv("BIND_ADDRESS"), "[]")
+	return net.JoinHostPort(host, port)
+}
 
-	fmt.Printf("🚀 Simple Kubernetes Explorer starting on port %s\n", port)
-	fmt.Printf("📂 Serving web files from: %s\n", webDir)
-	if k8sClient != nil {
-		fmt.Printf("🔗 Connected to Kubernetes cluster\n")
+// browserAddress is the address to open the UI at: the listen address, with localhost for all interfaces
+func browserAddress(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
 	}
-	fmt.Printf("🌐 Open http://localhost:%s in your browser\n", port)
-	if debug {
-		fmt.Printf("🛠️ Debug mode enabled (ENV DEBUG=true)\n")
+	if host == "" || net.ParseIP(host).IsUnspecified() {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// defaultShutdownGracePeriod is how long in-flight requests may run after a shutdown signal
+const defaultShutdownGracePeriod = 15 * time.Second
+
+// shutdownGracePeriod reads SHUTDOWN_GRACE_PERIOD as a duration ("30s", "1m"), falling back to the default
+func shutdownGracePeriod() time.Duration {
+	raw := os.Getenv("SHUTDOWN_GRACE_PERIOD")
+	if raw == "" {
+		return defaultShutdownGracePeriod
+	}
+
+	period, err := time.ParseDuration(raw)
+	if err != nil || period <= 0 {
+		slog.Warn("Invalid SHUTDOWN_GRACE_PERIOD, using default", "value", raw, "default", defaultShutdownGracePeriod)
+		return defaultShutdownGracePeriod
+	}
+	return period
+}
+
+// defaultPrewarmInterval matches the namespace cache TTL, so warmed counts are renewed as they expire
+const defaultPrewarmInterval = 5 * time.Minute
+
+// prewarmInterval reads PREWARM_INTERVAL as a duration; 0 prewarms only at startup
+func prewarmInterval() time.Duration {
+	raw := os.Getenv("PREWARM_INTERVAL")
+	if raw == "" {
+		return defaultPrewarmInterval
 	}
 
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval < 0 {
+		slog.Warn("Invalid PREWARM_INTERVAL, using default", "value", raw, "default", defaultPrewarmInterval)
+		return defaultPrewarmInterval
+	}
+	return interval
 }
 
 func (s *Server) debugStatus(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
 	response := map[string]interface{}{
 		"debug":     s.debug,
 		"version":   Version,
@@ -105,37 +287,189 @@ func (s *Server) debugStatus(w http.ResponseWriter, r *http.Request) {
 		"status":    "ok",
 	}
 
-	if s.k8sClient != nil && s.debug {
+	if client != nil && s.debug {
 		// Add cache information when debug is enabled
-		cacheAge := time.Time{}
-		cacheSize := 0
-		if s.k8sClient != nil {
-			// Access cache info (we'll need to add a getter method)
-			response["cache"] = map[string]interface{}{
-				"enabled": true,
-				"ttl":     "5 minutes",
+		stats := client.GetCacheStats()
+
+		namespaces := make(map[string]interface{}, len(stats.Namespaces))
+		for namespace, nsStats := range stats.Namespaces {
+			namespaces[namespace] = map[string]interface{}{
+				"entries": nsStats.Entries,
+				"age":     nsStats.Age.Round(time.Second).String(),
+				"stale":   nsStats.Age >= stats.TTL && !nsStats.Watched,
+				"watched": nsStats.Watched,
 			}
 		}
-		_ = cacheAge
-		_ = cacheSize
+
+		cache := map[string]interface{}{
+			"enabled":         true,
+			"ttl":             stats.TTL.String(),
+			"resourcesCached": stats.ResourcesCached,
+			"resourceCount":   stats.ResourceCount,
+			"namespaces":      namespaces,
+		}
+		if stats.ResourcesCached {
+			cache["resourcesAge"] = stats.ResourcesAge.Round(time.Second).String()
+			cache["resourcesStale"] = stats.ResourcesAge >= stats.TTL
+		}
+		response["cache"] = cache
+		response["resourceFilters"] = client.ResourceFilters()
+
+		inFlight, limit := client.CountsInFlight()
+		response["counts"] = map[string]interface{}{
+			"inFlight": inFlight,
+			"limit":    limit,
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// clearCache drops cached counts and objects. It has no body for requireJSON to check, so a form
+// on another site could post to it with the cached Basic Auth credentials; such requests get 403.
 func (s *Server) clearCache(w http.ResponseWriter, r *http.Request) {
-	if s.k8sClient != nil {
-		// Clear both API resources cache and namespace caches
-		// We'll need to add methods to the k8s client for this
-		fmt.Println("🗑️ Cache cleared by user request")
+	if crossSiteRequest(r) {
+		http.Error(w, "Clearing the cache is refused for requests from another site", http.StatusForbidden)
+		return
+	}
+
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	namespace := r.URL.Query().Get("namespace")
+
+	var cleared int
+	if namespace != "" {
+		cleared = client.ClearNamespaceCache(namespace)
+		slog.InfoContext(r.Context(), "🗑️ Cache cleared by user request", "namespace", namespace, "count", cleared)
+	} else {
+		cleared = client.ClearCache()
+		slog.InfoContext(r.Context(), "🗑️ Cache cleared by user request", "count", cleared)
+	}
+
+	response := map[string]interface{}{
+		"status":  "cache cleared",
+		"cleared": cleared,
 	}
+	if namespace != "" {
+		response["namespace"] = namespace
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// warmCache counts the namespaces of {"namespaces": [...]} ahead of their first visit and reports the
+// timing and counts of each
+func (s *Server) warmCache(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !requireJSON(w, r) {
+		return
+	}
+
+	var request struct {
+		Namespaces []string `json:"namespaces"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	for _, namespace := range request.Namespaces {
+		if !validNamespaceParam(namespace) {
+			http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+			return
+		}
+	}
+
+	slog.InfoContext(r.Context(), "🔥 Warming namespace caches by user request", "namespaces", len(request.Namespaces))
+	start := time.Now()
+
+	results := client.WarmNamespaces(r.Context(), request.Namespaces)
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	slog.InfoContext(r.Context(), "🔥 Warmed namespace caches", "namespaces", len(results), "failed", failed, "duration", time.Since(start))
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "cache cleared"})
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results":    results,
+		"count":      len(results),
+		"failed":     failed,
+		"durationMs": time.Since(start).Milliseconds(),
+	})
+}
+
+// watchKeepAlive is how often an idle watch stream sends a comment so proxies keep the connection open
+const watchKeepAlive = 30 * time.Second
+
+// watchResourceObjects streams add, update and delete events of a resource's objects as Server-Sent Events
+func (s *Server) watchResourceObjects(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// The watch ends when the client disconnects and the request context is cancelled
+	events, err := client.WatchResourceObjects(r.Context(), namespace, resource)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Watching objects", "namespace", namespace, "resource", resource)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(watchKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				slog.DebugContext(r.Context(), "Watch ended", "namespace", namespace, "resource", resource)
+				return
+			}
+			jsonData, _ := json.Marshal(event)
+			fmt.Fprintf(w, "data: %s\n\n", jsonData)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
 }
 
 func (s *Server) getDebugStream(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
 	vars := mux.Vars(r)
 	namespace := vars["namespace"]
 
@@ -182,7 +516,7 @@ func (s *Server) getDebugStream(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Get resources with real-time debug callbacks
-		resources, err := s.k8sClient.GetResourcesInNamespaceWithCallback(namespace, debugCallback)
+		resources, err := client.GetResourcesInNamespaceWithCallback(r.Context(), namespace, debugCallback)
 		if err != nil {
 			debugOutput <- fmt.Sprintf("❌ Error: %v", err)
 			return
@@ -213,118 +547,388 @@ func (s *Server) getDebugStream(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) getNamespaces(w http.ResponseWriter, r *http.Request) {
-	if s.k8sClient == nil {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	// ?search= keeps namespaces whose name contains it and ?limit= caps the list, for clusters with
+	// too many namespaces to show at once; without them every namespace is returned
+	query := r.URL.Query()
+	search := query.Get("search")
+	limit := 0
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, fmt.Sprintf("Invalid limit %q: must be a positive integer", raw), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	// ?detailed=true returns labels, annotations, phase and creation time instead of just names
+	if query.Get("detailed") == "true" {
+		namespaces, err := client.GetNamespacesDetailed(r.Context())
+		if err != nil {
+			writeClientError(w, err)
+			return
+		}
+		namespaces, matched, truncated := filterNamespaces(namespaces, func(ns k8s.NamespaceInfo) string { return ns.Name }, search, limit)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"namespaces": namespaces,
+			"count":      len(namespaces),
+			"total":      matched,
+			"truncated":  truncated,
+		})
+		return
+	}
+
+	// ?accessible=true falls back to checking ACCESSIBLE_NAMESPACES when listing namespaces is forbidden
+	accessible := query.Get("accessible") == "true"
+
+	var namespaces []string
+	var err error
+	if accessible {
+		namespaces, err = client.GetAccessibleNamespaces(r.Context())
+	} else {
+		namespaces, err = client.GetNamespaces(r.Context())
+	}
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	namespaces, matched, truncated := filterNamespaces(namespaces, func(name string) string { return name }, search, limit)
+
+	response := map[string]interface{}{
+		"namespaces": namespaces,
+		"count":      len(namespaces),
+		"total":      matched,
+		"truncated":  truncated,
+	}
+	if accessible {
+		response["accessible"] = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// filterNamespaces keeps the namespaces whose name contains search, case-insensitively, and caps
+// them at limit when it is positive. It returns how many matched before the cap and whether the
+// cap cut any off.
+func filterNamespaces[T any](namespaces []T, name func(T) string, search string, limit int) ([]T, int, bool) {
+	if search != "" {
+		search = strings.ToLower(search)
+		matching := make([]T, 0, len(namespaces))
+		for _, namespace := range namespaces {
+			if strings.Contains(strings.ToLower(name(namespace)), search) {
+				matching = append(matching, namespace)
+			}
+		}
+		namespaces = matching
+	}
+
+	matched := len(namespaces)
+	if limit > 0 && matched > limit {
+		return namespaces[:limit], matched, true
+	}
+	return namespaces, matched, false
+}
+
+func (s *Server) getAPIGroups(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
 		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
 		return
 	}
 
-	namespaces, err := s.k8sClient.GetNamespaces()
+	groups, err := client.GetAPIGroups()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeClientError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"namespaces": namespaces,
-		"count":      len(namespaces),
+		"groups": groups,
+		"count":  len(groups),
 	})
 }
 
-func (s *Server) getNamespaceResources(w http.ResponseWriter, r *http.Request) {
-	if s.k8sClient == nil {
+func (s *Server) getClusterInfo(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
 		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
 		return
 	}
 
-	vars := mux.Vars(r)
-	namespace := vars["namespace"]
+	info, err := client.GetClusterInfo()
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
 
-	fmt.Printf("Loading resources for namespace: %s\n", namespace)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
 
-	// Use only server debug flag from environment
-	debug := s.debug
+func (s *Server) getCRDs(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
 
-	resources, err := s.k8sClient.GetResourcesInNamespace(namespace)
+	crds, err := client.GetCRDs(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeClientError(w, err)
 		return
 	}
 
-	// Simple filtering
-	search := strings.ToLower(r.URL.Query().Get("search"))
-	showOnlyPopulated := r.URL.Query().Get("populated") == "true"
-	apiGroup := r.URL.Query().Get("apiGroup")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"crds":  crds,
+		"count": len(crds),
+	})
+}
 
-	var filtered []k8s.ResourceInfo
-	totalObjects := 0
+// getAPIResources serves the discovered resource types without counts, like kubectl api-resources.
+// Subresources are included when INCLUDE_SUBRESOURCES is set.
+func (s *Server) getAPIResources(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
 
-	start := time.Now()
-	if debug {
-		log.Printf("[DEBUG] Namespace=%s resources discovered: %d", namespace, len(resources))
+	resources, err := client.GetAPIResources()
+	if err != nil {
+		writeClientError(w, err)
+		return
 	}
 
-	for _, resource := range resources {
-		totalObjects += resource.Count
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resources": resources,
+		"count":     len(resources),
+	})
+}
 
-		// Apply filters
-		if showOnlyPopulated && resource.Count == 0 {
-			continue
-		}
-		if apiGroup != "" && resource.APIVersion != apiGroup {
-			continue
-		}
-		if search != "" {
-			if !strings.Contains(strings.ToLower(resource.Name), search) &&
-				!strings.Contains(strings.ToLower(resource.Kind), search) {
-				continue
-			}
-		}
+// getAllNamespacesResources serves every resource type with its count summed across all namespaces,
+// with the same filters as getNamespaceResources
+func (s *Server) getAllNamespacesResources(w http.ResponseWriter, r *http.Request) {
+	s.getNamespaceResources(w, mux.SetURLVars(r, map[string]string{"namespace": k8s.AllNamespaces}))
+}
 
-		filtered = append(filtered, resource)
+// getClusterResources serves cluster-scoped resources with the same filters as getNamespaceResources
+func (s *Server) getClusterResources(w http.ResponseWriter, r *http.Request) {
+	s.getNamespaceResources(w, mux.SetURLVars(r, map[string]string{"namespace": k8s.ClusterScope}))
+}
+
+// getResourceSummary serves only the populated resource types of a namespace with their total, for dashboard tiles
+func (s *Server) getResourceSummary(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
 	}
 
-	if debug {
-		// Log top 10 resources by count
-		type kv struct {
-			Name    string
-			Group   string
-			Version string
-			Count   int
-		}
-		top := make([]kv, 0, len(filtered))
-		for _, rinfo := range filtered {
-			grp := rinfo.APIGroup
-			if grp == "" {
-				grp = "core"
-			}
-			top = append(top, kv{Name: rinfo.Name, Group: grp, Version: rinfo.APIVersion, Count: rinfo.Count})
-		}
-		sort.Slice(top, func(i, j int) bool { return top[i].Count > top[j].Count })
-		if len(top) > 10 {
-			top = top[:10]
-		}
-		log.Printf("[DEBUG] Filtered resources: %d (populatedOnly=%t search=\"%s\" apiGroup=\"%s\")", len(filtered), showOnlyPopulated, search, apiGroup)
-		for _, t := range top {
-			log.Printf("[DEBUG]   %s (%s/%s) -> %d objects", t.Name, t.Group, t.Version, t.Count)
-		}
-		log.Printf("[DEBUG] Completed in %s", time.Since(start))
+	namespace := mux.Vars(r)["namespace"]
+	if namespace != k8s.AllNamespaces && !validNamespaceParam(namespace) {
+		http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+		return
 	}
 
-	fmt.Printf("Found %d resources (%d total objects) in namespace %s\n",
-		len(filtered), totalObjects, namespace)
+	start := time.Now()
+	summary, err := client.GetResourceSummary(r.Context(), namespace)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
 
-	response := map[string]interface{}{
-		"resources":    filtered,
-		"count":        len(filtered),
-		"totalObjects": totalObjects,
-		"namespace":    namespace,
-		"debug":        s.debug,
+	slog.DebugContext(r.Context(), "Resource summary computed", "namespace", namespace, "resources", len(summary.Resources),
+		"count", summary.Total, "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// getResourceCountHistory serves the object counts recorded by past scans of a namespace, oldest first
+func (s *Server) getResourceCountHistory(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
 	}
 
-	// Add debug info to response when debug mode is enabled
-	if debug {
-		debugInfo := make([]map[string]interface{}, 0)
+	namespace := mux.Vars(r)["namespace"]
+	if namespace != k8s.AllNamespaces && !validNamespaceParam(namespace) {
+		http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+		return
+	}
+
+	history := client.GetCountHistory(namespace)
+
+	// ?resource= narrows the history to one resource type by FullName
+	if resource := r.URL.Query().Get("resource"); resource != "" {
+		history = map[string][]k8s.CountSample{resource: history[resource]}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"namespace": namespace,
+		"history":   history,
+		"retention": client.CountHistorySize(),
+	})
+}
+
+func (s *Server) getNamespaceStatus(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	namespace := mux.Vars(r)["namespace"]
+	status := client.GetNamespaceStatus(namespace)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+func (s *Server) getNamespaceResources(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	allNamespaces := namespace == k8s.AllNamespaces
+	if !allNamespaces && !validNamespaceParam(namespace) {
+		http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+		return
+	}
+
+	sortKey := r.URL.Query().Get("sort")
+	if !validSortKey(sortKey) {
+		http.Error(w, fmt.Sprintf("Invalid sort %q: must be count, name or kind, prefixed with - for descending order", sortKey), http.StatusBadRequest)
+		return
+	}
+
+	search := r.URL.Query().Get("search")
+	matchSearch, err := searchMatcher(search, r.URL.Query().Get("searchMode"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid search: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Loading resources", "namespace", namespace)
+
+	var resources []k8s.ResourceInfo
+	if allNamespaces {
+		resources, err = client.GetResourceCountsAllNamespaces(r.Context())
+	} else {
+		resources, err = client.GetResourcesInNamespace(r.Context(), namespace)
+	}
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	// Cap the catalog so clusters with hundreds of CRDs do not overwhelm the UI
+	totalObjects := 0
+	for _, resource := range resources {
+		totalObjects += resource.Count
+	}
+	resources, omittedTypes := client.LimitResourceTypes(resources)
+	if omittedTypes > 0 {
+		slog.InfoContext(r.Context(), "Omitted resource types (MAX_RESOURCE_TYPES)", "namespace", namespace, "count", omittedTypes)
+	}
+
+	// Simple filtering
+	showOnlyPopulated := r.URL.Query().Get("populated") == "true"
+	apiGroup := r.URL.Query().Get("apiGroup")
+
+	var filtered []k8s.ResourceInfo
+
+	start := time.Now()
+	slog.DebugContext(r.Context(), "Resources discovered", "namespace", namespace, "count", len(resources))
+
+	for _, resource := range resources {
+		// Apply filters
+		if showOnlyPopulated && resource.Count == 0 {
+			continue
+		}
+		if apiGroup != "" && resource.APIGroup != apiGroup && !(apiGroup == "core" && resource.APIGroup == "") {
+			continue
+		}
+		if search != "" && !matchSearch(resource.Name) && !matchSearch(resource.Kind) {
+			continue
+		}
+
+		filtered = append(filtered, resource)
+	}
+	sortResources(filtered, sortKey)
+
+	if logging.DebugEnabled(r.Context()) {
+		// Log top 10 resources by count
+		type kv struct {
+			Name    string
+			Group   string
+			Version string
+			Count   int
+		}
+		top := make([]kv, 0, len(filtered))
+		for _, rinfo := range filtered {
+			grp := rinfo.APIGroup
+			if grp == "" {
+				grp = "core"
+			}
+			top = append(top, kv{Name: rinfo.Name, Group: grp, Version: rinfo.APIVersion, Count: rinfo.Count})
+		}
+		sort.Slice(top, func(i, j int) bool { return top[i].Count > top[j].Count })
+		if len(top) > 10 {
+			top = top[:10]
+		}
+		slog.DebugContext(r.Context(), "Filtered resources", "namespace", namespace, "count", len(filtered),
+			"populatedOnly", showOnlyPopulated, "search", search, "apiGroup", apiGroup)
+		for _, t := range top {
+			slog.DebugContext(r.Context(), "Top resource", "namespace", namespace, "resource", t.Name, "group", t.Group, "version", t.Version, "count", t.Count)
+		}
+		slog.DebugContext(r.Context(), "Filtering completed", "namespace", namespace, "duration", time.Since(start))
+	}
+
+	slog.InfoContext(r.Context(), "Found resources", "namespace", namespace, "count", len(filtered), "totalObjects", totalObjects)
+
+	// Listings only change when the cache does, so polling clients can revalidate with If-None-Match
+	discoveryWarnings := client.DiscoveryWarnings()
+	etag, err := etagFor(client.NamespaceCacheTime(namespace), []interface{}{filtered, totalObjects, omittedTypes, discoveryWarnings, s.debug})
+	if err == nil && notModified(w, r, etag) {
+		slog.DebugContext(r.Context(), "Resources not modified", "namespace", namespace, "etag", etag)
+		return
+	}
+
+	response := map[string]interface{}{
+		"resources":    filtered,
+		"count":        len(filtered),
+		"totalObjects": totalObjects,
+		"namespace":    namespace,
+		"debug":        s.debug,
+		"truncated":    omittedTypes > 0,
+		"omittedTypes": omittedTypes,
+		// API groups whose discovery failed, so their resources are missing from the listing
+		"discoveryWarnings": discoveryWarnings,
+	}
+
+	// Add debug info to response when debug mode is enabled
+	if s.debug {
+		debugInfo := make([]map[string]interface{}, 0)
 
 		// Add top resources info
 		type kv struct {
@@ -383,8 +987,142 @@ func (s *Server) getNamespaceResources(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// writeClientError maps typed k8s client errors to the matching HTTP status
+func writeClientError(w http.ResponseWriter, err error) {
+	var tooMany *k8s.TooManyObjectsError
+	if errors.As(err, &tooMany) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+			"count": tooMany.Count,
+			"limit": tooMany.Limit,
+		})
+		return
+	}
+
+	var container *k8s.ContainerError
+	if errors.As(err, &container) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":      err.Error(),
+			"containers": container.Containers,
+		})
+		return
+	}
+
+	var ambiguous *k8s.AmbiguousResourceError
+	if errors.As(err, &ambiguous) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":      err.Error(),
+			"candidates": ambiguous.Candidates,
+		})
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, k8s.ErrNoClient):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, k8s.ErrInvalidArgument):
+		status = http.StatusBadRequest
+	case errors.Is(err, k8s.ErrResourceNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, k8s.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, k8s.ErrTimeout):
+		status = http.StatusGatewayTimeout
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// searchMatcher returns the ?search= matcher for a ?searchMode=: a case-insensitive substring match
+// by default, or a regular expression with searchMode=regex
+func searchMatcher(search, mode string) (func(string) bool, error) {
+	switch mode {
+	case "", "substring":
+		search = strings.ToLower(search)
+		return func(value string) bool {
+			return strings.Contains(strings.ToLower(value), search)
+		}, nil
+	case "regex":
+		pattern, err := regexp.Compile(search)
+		if err != nil {
+			return nil, err
+		}
+		return pattern.MatchString, nil
+	default:
+		return nil, fmt.Errorf("unknown searchMode %q: must be substring or regex", mode)
+	}
+}
+
+// resourceSortKeys compare two resources for each ?sort= key, in ascending order
+var resourceSortKeys = map[string]func(a, b k8s.ResourceInfo) bool{
+	"count": func(a, b k8s.ResourceInfo) bool { return a.Count < b.Count },
+	"name":  func(a, b k8s.ResourceInfo) bool { return a.Name < b.Name },
+	"kind":  func(a, b k8s.ResourceInfo) bool { return a.Kind < b.Kind },
+}
+
+// validSortKey reports whether key is empty or a resourceSortKeys key, optionally prefixed with -
+func validSortKey(key string) bool {
+	return validKey(resourceSortKeys, key)
+}
+
+// sortResources sorts resources in place by a ?sort= key; a leading - sorts descending. Ties, and
+// an empty key, keep the discovery order.
+func sortResources(resources []k8s.ResourceInfo, key string) {
+	sortByKey(resources, resourceSortKeys, key)
+}
+
+// objectSortKeys compare two objects for each object listing ?sort= key, in ascending order. Ages
+// ascend from the newest object, so -age lists the oldest first.
+var objectSortKeys = map[string]func(a, b k8s.ObjectInfo) bool{
+	"age": func(a, b k8s.ObjectInfo) bool { return a.CreationTimestamp.After(b.CreationTimestamp) },
+	"name": func(a, b k8s.ObjectInfo) bool {
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	},
+}
+
+// validKey reports whether key is empty or a key of keys, optionally prefixed with -
+func validKey[T any](keys map[string]func(a, b T) bool, key string) bool {
+	if key == "" {
+		return true
+	}
+	_, ok := keys[strings.TrimPrefix(key, "-")]
+	return ok
+}
+
+// sortByKey sorts items in place with the comparison keys holds for a ?sort= key; a leading - sorts
+// descending. Ties, and an empty key, keep the original order.
+func sortByKey[T any](items []T, keys map[string]func(a, b T) bool, key string) {
+	less, ok := keys[strings.TrimPrefix(key, "-")]
+	if !ok {
+		return
+	}
+	if strings.HasPrefix(key, "-") {
+		sort.SliceStable(items, func(i, j int) bool { return less(items[j], items[i]) })
+		return
+	}
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+}
+
+// validNamespaceParam accepts namespace names and the reserved k8s.ClusterScope value
+func validNamespaceParam(namespace string) bool {
+	if namespace == k8s.ClusterScope {
+		return true
+	}
+	return len(validation.IsDNS1123Label(namespace)) == 0
+}
+
 func (s *Server) getResourceObjects(w http.ResponseWriter, r *http.Request) {
-	if s.k8sClient == nil {
+	client := s.client(r)
+	if client == nil {
 		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
 		return
 	}
@@ -393,19 +1131,61 @@ func (s *Server) getResourceObjects(w http.ResponseWriter, r *http.Request) {
 	namespace := vars["namespace"]
 	resource := vars["resource"]
 
-	fmt.Printf("Loading objects for resource: %s in namespace: %s\n", resource, namespace)
-	debug := s.debug
+	// ?allNamespaces=true lists the resource cluster-wide; the namespace in the path is ignored
+	query := r.URL.Query()
+	allNamespaces := query.Get("allNamespaces") == "true"
+	listNamespace := namespace
+	if allNamespaces {
+		listNamespace = "" // an empty namespace lists across all namespaces
+		slog.InfoContext(r.Context(), "Loading objects across all namespaces", "resource", resource)
+	} else {
+		slog.InfoContext(r.Context(), "Loading objects", "namespace", namespace, "resource", resource)
+	}
+
+	options := k8s.ListOptions{
+		ResourceVersion: query.Get("resourceVersion"),
+		FieldSelector:   query.Get("fieldSelector"),
+		LabelSelector:   query.Get("labelSelector"),
+		Continue:        query.Get("continue"),
+	}
+	if err := parseLabelSelector(options.LabelSelector); err != nil {
+		writeLabelSelectorError(w, err)
+		return
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || limit <= 0 {
+			http.Error(w, fmt.Sprintf("Invalid limit %q: must be a positive integer", raw), http.StatusBadRequest)
+			return
+		}
+		options.Limit = limit
+	}
+	fields, err := parseFields(query.Get("fields"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid fields: %v", err), http.StatusBadRequest)
+		return
+	}
+	sortKey := query.Get("sort")
+	if !validKey(objectSortKeys, sortKey) {
+		http.Error(w, fmt.Sprintf("Invalid sort %q: must be age or name, prefixed with - for descending order", sortKey), http.StatusBadRequest)
+		return
+	}
 
 	start := time.Now()
-	objects, err := s.k8sClient.GetResourceObjects(namespace, resource)
+	list, err := client.GetResourceObjectList(r.Context(), listNamespace, resource, options)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeClientError(w, err)
 		return
 	}
+	objects := list.Items
+	sortByKey(objects, objectSortKeys, sortKey)
+	if fields == nil || fields["status"] {
+		client.AddResourceUsage(r.Context(), listNamespace, objects)
+	}
 
-	fmt.Printf("Found %d objects for resource %s in namespace %s\n", len(objects), resource, namespace)
+	slog.InfoContext(r.Context(), "Found objects", "namespace", namespace, "resource", resource, "count", len(objects), "duration", time.Since(start))
 
-	if debug {
+	if logging.DebugEnabled(r.Context()) {
 		// Log a few object names
 		limit := 5
 		if len(objects) < limit {
@@ -413,22 +1193,44 @@ func (s *Server) getResourceObjects(w http.ResponseWriter, r *http.Request) {
 		}
 		for i := 0; i < limit; i++ {
 			o := objects[i]
-			log.Printf("[DEBUG]   %s/%s kind=%s apiVersion=%s", o.Namespace, o.Name, o.Kind, o.APIVersion)
+			slog.DebugContext(r.Context(), "Listed object", "namespace", o.Namespace, "name", o.Name, "kind", o.Kind, "apiVersion", o.APIVersion)
 		}
-		log.Printf("[DEBUG] Objects listing completed in %s", time.Since(start))
+	}
+
+	response := map[string]interface{}{
+		"objects":         objects,
+		"count":           len(objects),
+		"namespace":       namespace,
+		"resource":        resource,
+		"resourceVersion": list.ResourceVersion,
+	}
+	if fields != nil {
+		response["objects"] = projectObjects(objects, fields)
+	}
+	if allNamespaces {
+		response["namespace"] = k8s.AllNamespaces
+		response["allNamespaces"] = true
+	}
+	if options.Limit > 0 {
+		addPagination(response, list)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"objects":   objects,
-		"count":     len(objects),
-		"namespace": namespace,
-		"resource":  resource,
-	})
+	json.NewEncoder(w).Encode(response)
 }
 
-func (s *Server) getObjectDetails(w http.ResponseWriter, r *http.Request) {
-	if s.k8sClient == nil {
+// addPagination adds the continue token of a limited listing and, when the server reported it, the
+// number of objects after this page so the UI can show "100 of ~4300" without a second full list
+func addPagination(response map[string]interface{}, list *k8s.ObjectList) {
+	response["continue"] = list.Continue
+	if list.RemainingItemCount != nil {
+		response["remainingItemCount"] = *list.RemainingItemCount
+	}
+}
+
+func (s *Server) deleteCollection(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
 		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
 		return
 	}
@@ -436,42 +1238,43 @@ func (s *Server) getObjectDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	namespace := vars["namespace"]
 	resource := vars["resource"]
-	name := vars["name"]
 
-	fmt.Printf("Loading object details: %s/%s/%s\n", namespace, resource, name)
-	debug := s.debug
-	start := time.Now()
+	if !requireJSON(w, r) {
+		return
+	}
 
-	object, err := s.k8sClient.GetResourceObject(namespace, resource, name)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	var request struct {
+		LabelSelector string `json:"labelSelector"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	if debug {
-		// Log basic manifest metadata
-		apiGroup := object.APIVersion
-		log.Printf("[DEBUG] Object: kind=%s apiVersion=%s ns=%s name=%s labels=%d annotations=%d",
-			object.Kind, apiGroup, object.Namespace, object.Name, len(object.Labels), len(object.Annotations))
-		if len(object.Spec) > 0 {
-			log.Printf("[DEBUG]   spec: present")
-		} else {
-			log.Printf("[DEBUG]   spec: empty")
-		}
-		if len(object.Status) > 0 {
-			log.Printf("[DEBUG]   status: present")
-		} else {
-			log.Printf("[DEBUG]   status: empty")
-		}
-		log.Printf("[DEBUG] Object details fetched in %s", time.Since(start))
+	slog.InfoContext(r.Context(), "Deleting objects", "namespace", namespace, "resource", resource, "labelSelector", request.LabelSelector)
+
+	deleted, err := client.DeleteCollection(r.Context(), namespace, resource, request.LabelSelector)
+	if err != nil {
+		writeClientError(w, err)
+		return
 	}
 
+	slog.InfoContext(r.Context(), "🗑️ Deleted objects", "namespace", namespace, "resource", resource, "count", deleted)
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(object)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deleted":       deleted,
+		"namespace":     namespace,
+		"resource":      resource,
+		"labelSelector": request.LabelSelector,
+	})
 }
 
-func (s *Server) getRawObjectDetails(w http.ResponseWriter, r *http.Request) {
-	if s.k8sClient == nil {
+// deleteObject deletes one object; like deleteCollection it answers 403 unless ALLOW_WRITES is set
+// and the resource is in WRITABLE_RESOURCES
+func (s *Server) deleteObject(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
 		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
 		return
 	}
@@ -481,72 +1284,848 @@ func (s *Server) getRawObjectDetails(w http.ResponseWriter, r *http.Request) {
 	resource := vars["resource"]
 	name := vars["name"]
 
-	fmt.Printf("Loading raw object details: %s/%s/%s\n", namespace, resource, name)
-	debug := s.debug
-	start := time.Now()
+	slog.InfoContext(r.Context(), "Deleting object", "namespace", namespace, "resource", resource, "name", name)
 
-	rawObject, err := s.k8sClient.GetRawResourceObject(namespace, resource, name)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := client.DeleteResourceObject(r.Context(), namespace, resource, name); err != nil {
+		writeClientError(w, err)
 		return
 	}
 
-	if debug {
-		// Log basic object information
-		if apiVersion, ok := rawObject["apiVersion"].(string); ok {
-			if kind, ok := rawObject["kind"].(string); ok {
-				log.Printf("[DEBUG] Raw Object: kind=%s apiVersion=%s", kind, apiVersion)
-			}
-		}
-		if metadata, ok := rawObject["metadata"].(map[string]interface{}); ok {
-			if objName, ok := metadata["name"].(string); ok && objName == name {
-				log.Printf("[DEBUG]   metadata.name=%s", objName)
-			}
-			if labels, ok := metadata["labels"].(map[string]interface{}); ok {
-				log.Printf("[DEBUG]   labels count=%d", len(labels))
-			}
-		}
-		log.Printf("[DEBUG] Raw object details fetched in %s", time.Since(start))
-	}
+	slog.InfoContext(r.Context(), "🗑️ Deleted object", "namespace", namespace, "resource", resource, "name", name)
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(rawObject)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deleted":   true,
+		"namespace": namespace,
+		"resource":  resource,
+		"name":      name,
+	})
 }
 
-func (s *Server) exportResourcesCSV(w http.ResponseWriter, r *http.Request) {
-	if s.k8sClient == nil {
+// scaleObject sets the replicas of a workload from {"replicas": N} through its scale subresource.
+// It answers 403 unless writes are allowed for the resource and 400 when it cannot be scaled.
+func (s *Server) scaleObject(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
 		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
 		return
 	}
 
 	vars := mux.Vars(r)
 	namespace := vars["namespace"]
+	resource := vars["resource"]
+	name := vars["name"]
 
-	fmt.Printf("Exporting resources for namespace: %s\n", namespace)
+	if !requireJSON(w, r) {
+		return
+	}
 
-	resources, err := s.k8sClient.GetResourcesInNamespace(namespace)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	var request struct {
+		Replicas *int32 `json:"replicas"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	if request.Replicas == nil {
+		http.Error(w, "Invalid request body: replicas is required", http.StatusBadRequest)
 		return
 	}
 
-	// Set CSV headers
-	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"k8s-resources-%s.csv\"", namespace))
+	slog.InfoContext(r.Context(), "Scaling object", "namespace", namespace, "resource", resource, "name", name, "replicas", *request.Replicas)
+
+	if err := client.ScaleResource(r.Context(), namespace, resource, name, *request.Replicas); err != nil {
+		writeClientError(w, err)
+		return
+	}
 
-	// Write CSV header
-	fmt.Fprintf(w, "Resource Name,Kind,API Group,API Version,Namespaced,Count\n")
+	slog.InfoContext(r.Context(), "⚖️ Scaled object", "namespace", namespace, "resource", resource, "name", name, "replicas", *request.Replicas)
 
-	// Write data rows
-	for _, resource := range resources {
-		apiGroup := resource.APIGroup
-		if apiGroup == "" {
-			apiGroup = "core"
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"namespace": namespace,
+		"resource":  resource,
+		"name":      name,
+		"replicas":  *request.Replicas,
+	})
+}
+
+// defaultDumpMaxObjects caps namespace dumps unless the request asks for a different maxObjects
+const defaultDumpMaxObjects = 5000
+
+func (s *Server) getNamespaceDump(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	namespace := mux.Vars(r)["namespace"]
+	if !validNamespaceParam(namespace) {
+		http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	options := k8s.DumpOptions{
+		Compact:       query.Get("compact") == "true",
+		OnlyPopulated: query.Get("onlyPopulated") == "true",
+		MaxObjects:    defaultDumpMaxObjects,
+	}
+	if raw := query.Get("maxObjects"); raw != "" {
+		maxObjects, err := strconv.Atoi(raw)
+		if err != nil || maxObjects <= 0 {
+			http.Error(w, fmt.Sprintf("Invalid maxObjects %q: must be a positive integer", raw), http.StatusBadRequest)
+			return
 		}
-		fmt.Fprintf(w, "\"%s\",\"%s\",\"%s\",\"%s\",%t,%d\n",
-			resource.Name, resource.Kind, apiGroup, resource.APIVersion,
-			resource.Namespaced, resource.Count)
+		options.MaxObjects = maxObjects
+	}
+
+	slog.InfoContext(r.Context(), "Dumping namespace", "namespace", namespace, "compact", options.Compact, "onlyPopulated", options.OnlyPopulated)
+	start := time.Now()
+
+	dump, err := client.GetNamespaceDump(r.Context(), namespace, options)
+	if err != nil {
+		writeClientError(w, err)
+		return
 	}
 
-	fmt.Printf("Exported %d resources for namespace %s\n", len(resources), namespace)
+	slog.DebugContext(r.Context(), "Namespace dumped", "namespace", namespace, "resources", len(dump.Resources),
+		"count", dump.TotalObjects, "truncated", dump.Truncated, "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dump)
+}
+
+// searchObjects serves the objects of a namespace whose name contains ?q=, across all resource types
+func (s *Server) searchObjects(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	namespace := mux.Vars(r)["namespace"]
+	if !validNamespaceParam(namespace) {
+		http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+		return
+	}
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "Missing search query: set ?q=", http.StatusBadRequest)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Searching objects by name", "namespace", namespace, "query", query)
+	start := time.Now()
+
+	objects, err := client.SearchObjectsByName(r.Context(), namespace, query)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Search completed", "namespace", namespace, "query", query,
+		"matches", len(objects), "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"namespace": namespace,
+		"query":     query,
+		"objects":   objects,
+		"count":     len(objects),
+	})
+}
+
+// getNamespaceGroups groups the namespaces by the value of the label named by ?by=, for the grouped
+// namespace picker
+func (s *Server) getNamespaceGroups(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	labelKey := strings.TrimSpace(r.URL.Query().Get("by"))
+	if labelKey == "" {
+		http.Error(w, "Missing label key: set ?by=, e.g. ?by=team", http.StatusBadRequest)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Grouping namespaces", "by", labelKey)
+
+	groups, err := client.GetNamespacesGrouped(r.Context(), labelKey)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"by":     labelKey,
+		"groups": groups,
+		"count":  len(groups),
+	})
+}
+
+// compareNamespaces compares the resource counts of the namespaces ?a= and ?b=
+func (s *Server) compareNamespaces(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	a, b := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	for _, namespace := range []string{a, b} {
+		if !validNamespaceParam(namespace) {
+			http.Error(w, fmt.Sprintf("Invalid namespace %q: set ?a= and ?b= to namespace names or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+			return
+		}
+	}
+
+	slog.InfoContext(r.Context(), "Comparing namespaces", "a", a, "b", b)
+	start := time.Now()
+
+	comparisons, err := client.CompareNamespaces(r.Context(), a, b)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	differing := 0
+	for _, comparison := range comparisons {
+		if comparison.Delta != 0 {
+			differing++
+		}
+	}
+	slog.DebugContext(r.Context(), "Comparison completed", "a", a, "b", b, "resources", len(comparisons),
+		"differing", differing, "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"a":         a,
+		"b":         b,
+		"resources": comparisons,
+		"count":     len(comparisons),
+		"differing": differing,
+	})
+}
+
+func (s *Server) getNamespaceHealth(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	namespace := mux.Vars(r)["namespace"]
+	if !validNamespaceParam(namespace) {
+		http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Computing workload health", "namespace", namespace)
+	start := time.Now()
+
+	health, err := client.GetNamespaceHealth(r.Context(), namespace)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Workload health computed", "namespace", namespace,
+		"healthy", health.Healthy, "unhealthy", health.Unhealthy, "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(health)
+}
+
+func (s *Server) getResourceObjectsMetadata(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+
+	slog.InfoContext(r.Context(), "Loading object metadata", "namespace", namespace, "resource", resource)
+	start := time.Now()
+
+	objects, err := client.GetResourceObjectsMetadata(r.Context(), namespace, resource, k8s.ListOptions{
+		ResourceVersion: r.URL.Query().Get("resourceVersion"),
+	})
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Metadata listing completed", "namespace", namespace, "resource", resource,
+		"count", len(objects), "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"objects":   objects,
+		"count":     len(objects),
+		"namespace": namespace,
+		"resource":  resource,
+	})
+}
+
+func (s *Server) getResourceTable(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+	if !validNamespaceParam(namespace) {
+		http.Error(w, fmt.Sprintf("Invalid namespace %q: must be a namespace name or %q for cluster-scoped resources", namespace, k8s.ClusterScope), http.StatusBadRequest)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Loading object table", "namespace", namespace, "resource", resource)
+	start := time.Now()
+
+	table, err := client.GetResourceTable(r.Context(), namespace, resource)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Object table loaded", "namespace", namespace, "resource", resource,
+		"columns", len(table.Columns), "count", len(table.Rows), "fallback", table.Fallback, "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"columns":   table.Columns,
+		"rows":      table.Rows,
+		"fallback":  table.Fallback,
+		"count":     len(table.Rows),
+		"namespace": namespace,
+		"resource":  resource,
+	})
+}
+
+func (s *Server) getResourceTotal(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	resource := mux.Vars(r)["resource"]
+
+	slog.InfoContext(r.Context(), "Counting cluster-wide total", "resource", resource)
+	start := time.Now()
+
+	info, err := client.GetResourceTotal(r.Context(), resource)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Cluster-wide total counted", "resource", info.FullName, "count", info.Count, "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resource":   resource,
+		"fullName":   info.FullName,
+		"kind":       info.Kind,
+		"namespaced": info.Namespaced,
+		"total":      info.Count,
+	})
+}
+
+func (s *Server) getResourceSchema(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	resource := mux.Vars(r)["resource"]
+
+	slog.InfoContext(r.Context(), "Loading OpenAPI schema", "resource", resource)
+
+	schema, err := client.GetResourceSchema(resource)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resource": resource,
+		"schema":   schema,
+	})
+}
+
+func (s *Server) getObjectDetails(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+	name := vars["name"]
+
+	slog.InfoContext(r.Context(), "Loading object details", "namespace", namespace, "resource", resource, "name", name)
+	start := time.Now()
+
+	reveal, ok := s.revealSecrets(w, r)
+	if !ok {
+		return
+	}
+
+	object, err := client.GetResourceObjectWithOptions(r.Context(), namespace, resource, name, k8s.ObjectOptions{RevealSecrets: reveal})
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	objects := []k8s.ObjectInfo{*object}
+	client.AddResourceUsage(r.Context(), namespace, objects)
+	object = &objects[0]
+
+	slog.DebugContext(r.Context(), "Object details fetched", "namespace", object.Namespace, "resource", resource, "name", object.Name,
+		"kind", object.Kind, "apiVersion", object.APIVersion, "labels", len(object.Labels), "annotations", len(object.Annotations),
+		"spec", len(object.Spec) > 0, "status", len(object.Status) > 0, "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(object)
+}
+
+func (s *Server) getObjectReferences(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+	name := vars["name"]
+
+	slog.InfoContext(r.Context(), "Resolving references", "namespace", namespace, "resource", resource, "name", name)
+
+	references, err := client.GetObjectReferences(r.Context(), namespace, resource, name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	missing := 0
+	for _, ref := range references {
+		if !ref.Exists {
+			missing++
+		}
+	}
+
+	slog.DebugContext(r.Context(), "References resolved", "namespace", namespace, "resource", resource, "name", name,
+		"count", len(references), "missing", missing)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"references": references,
+		"count":      len(references),
+		"missing":    missing,
+		"namespace":  namespace,
+		"resource":   resource,
+		"name":       name,
+	})
+}
+
+func (s *Server) getObjectEvents(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+	name := vars["name"]
+
+	slog.InfoContext(r.Context(), "Loading events", "namespace", namespace, "resource", resource, "name", name)
+
+	info, err := client.ResolveResource(resource)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	events, err := client.GetObjectEvents(r.Context(), namespace, info.Kind, name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Events loaded", "namespace", namespace, "resource", resource, "name", name, "count", len(events))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events":    events,
+		"count":     len(events),
+		"namespace": namespace,
+		"resource":  resource,
+		"kind":      info.Kind,
+		"name":      name,
+	})
+}
+
+func (s *Server) getObjectOwners(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+	name := vars["name"]
+
+	slog.InfoContext(r.Context(), "Resolving owners", "namespace", namespace, "resource", resource, "name", name)
+
+	owners, err := client.GetOwnerChain(r.Context(), namespace, resource, name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Owners resolved", "namespace", namespace, "resource", resource, "name", name, "count", len(owners))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"owners":    owners,
+		"count":     len(owners),
+		"namespace": namespace,
+		"resource":  resource,
+		"name":      name,
+	})
+}
+
+func (s *Server) getRelatedObjects(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+	name := vars["name"]
+
+	slog.InfoContext(r.Context(), "Resolving related objects", "namespace", namespace, "resource", resource, "name", name)
+
+	related, err := client.GetRelatedObjects(r.Context(), namespace, resource, name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	count := 0
+	for _, objects := range related {
+		count += len(objects)
+	}
+
+	slog.DebugContext(r.Context(), "Related objects resolved", "namespace", namespace, "resource", resource, "name", name, "count", count)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"related":   related,
+		"count":     count,
+		"namespace": namespace,
+		"resource":  resource,
+		"name":      name,
+	})
+}
+
+func (s *Server) getRawObjectDetails(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	resource := vars["resource"]
+	name := vars["name"]
+
+	slog.InfoContext(r.Context(), "Loading raw object details", "namespace", namespace, "resource", resource, "name", name)
+	start := time.Now()
+
+	reveal, ok := s.revealSecrets(w, r)
+	if !ok {
+		return
+	}
+
+	options := k8s.ObjectOptions{
+		ManagedFields: r.URL.Query().Get("managedFields") == "true",
+		RevealSecrets: reveal,
+	}
+	rawObject, err := client.GetRawResourceObjectWithOptions(r.Context(), namespace, resource, name, options)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	if logging.DebugEnabled(r.Context()) {
+		// Log basic object information
+		labels := 0
+		if metadata, ok := rawObject["metadata"].(map[string]interface{}); ok {
+			if objectLabels, ok := metadata["labels"].(map[string]interface{}); ok {
+				labels = len(objectLabels)
+			}
+		}
+		slog.DebugContext(r.Context(), "Raw object details fetched", "namespace", namespace, "resource", resource, "name", name,
+			"kind", rawObject["kind"], "apiVersion", rawObject["apiVersion"], "labels", labels, "duration", time.Since(start))
+	}
+
+	// ?path= returns only the subtree at a field path, e.g. spec.template.spec.containers[0]
+	if path := r.URL.Query().Get("path"); path != "" {
+		value, err := k8s.LookupFieldPath(rawObject, path)
+		if err != nil {
+			writeClientError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(value)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rawObject)
+}
+
+// defaultLogTailLines is the number of log lines returned when ?tail= is not given
+const defaultLogTailLines = 500
+
+func (s *Server) getPodLogs(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+	pod := vars["pod"]
+	container := r.URL.Query().Get("container")
+
+	// ?tail=0 returns the whole log, up to the size cap of the client
+	tail := int64(defaultLogTailLines)
+	if raw := r.URL.Query().Get("tail"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 0 {
+			http.Error(w, fmt.Sprintf("Invalid tail %q: must be a non-negative integer", raw), http.StatusBadRequest)
+			return
+		}
+		tail = parsed
+	}
+
+	slog.InfoContext(r.Context(), "Loading pod logs", "namespace", namespace, "pod", pod, "container", container, "tail", tail)
+	start := time.Now()
+
+	logs, err := client.GetPodLogs(r.Context(), namespace, pod, container, tail)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Pod logs loaded", "namespace", namespace, "pod", pod, "container", container,
+		"bytes", len(logs), "duration", time.Since(start))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"logs":      logs,
+		"namespace": namespace,
+		"pod":       pod,
+		"container": container,
+		"tail":      tail,
+	})
+}
+
+// revealSecrets reports whether ?reveal=true asks for unredacted Secret values. Asking while
+// ALLOW_SECRET_REVEAL is disabled, or from a page on another site, is answered with 403 and ok false.
+func (s *Server) revealSecrets(w http.ResponseWriter, r *http.Request) (reveal, ok bool) {
+	if r.URL.Query().Get("reveal") != "true" {
+		return false, true
+	}
+	if !s.allowSecretReveal {
+		http.Error(w, "Revealing Secret values is disabled (set ALLOW_SECRET_REVEAL=true)", http.StatusForbidden)
+		return false, false
+	}
+	if crossSiteRequest(r) {
+		http.Error(w, "Revealing Secret values is refused for requests from another site", http.StatusForbidden)
+		return false, false
+	}
+	slog.InfoContext(r.Context(), "🔓 Revealing Secret values", "path", r.URL.Path)
+	return true, true
+}
+
+// parseObjectPath parses a namespace/resource/name reference as used by /api/object-diff
+func parseObjectPath(value string) (k8s.ObjectPath, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return k8s.ObjectPath{}, fmt.Errorf("%q is not of the form namespace/resource/name", value)
+	}
+	if !validNamespaceParam(parts[0]) || parts[0] == k8s.ClusterScope {
+		return k8s.ObjectPath{}, fmt.Errorf("%q has an invalid namespace", value)
+	}
+	return k8s.ObjectPath{Namespace: parts[0], Resource: parts[1], Name: parts[2]}, nil
+}
+
+func (s *Server) getObjectDiff(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	a, err := parseObjectPath(query.Get("a"))
+	if err != nil {
+		http.Error(w, "Invalid a: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	b, err := parseObjectPath(query.Get("b"))
+	if err != nil {
+		http.Error(w, "Invalid b: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Comparing objects", "a", a.String(), "b", b.String())
+
+	diff, err := client.DiffObjects(r.Context(), a, b)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	slog.DebugContext(r.Context(), "Objects compared", "a", a.String(), "b", b.String(), "count", len(diff.Changes))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+func (s *Server) exportResourcesCSV(w http.ResponseWriter, r *http.Request) {
+	client := s.client(r)
+	if client == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	slog.InfoContext(r.Context(), "Exporting resources", "namespace", namespace)
+
+	resources, err := client.GetResourcesInNamespace(r.Context(), namespace)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "markdown" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"k8s-resources-%s.md\"", namespace))
+		writeResourcesMarkdown(w, resources)
+		slog.InfoContext(r.Context(), "Exported resources", "namespace", namespace, "format", "markdown", "count", len(resources))
+		return
+	}
+	if r.URL.Query().Get("format") == "json" {
+		writeResourcesJSON(w, namespace, resources)
+		slog.InfoContext(r.Context(), "Exported resources", "namespace", namespace, "format", "json", "count", len(resources))
+		return
+	}
+
+	// Set CSV headers
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"k8s-resources-%s.csv\"", namespace))
+
+	if err := writeResourcesCSV(w, resources); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to write CSV export", "namespace", namespace, "error", err)
+		return
+	}
+
+	slog.InfoContext(r.Context(), "Exported resources", "namespace", namespace, "format", "csv", "count", len(resources))
+}
+
+// writeResourcesMarkdown writes resources as a GitHub-flavored Markdown table with the CSV columns
+func writeResourcesMarkdown(w io.Writer, resources []k8s.ResourceInfo) {
+	fmt.Fprintf(w, "| Resource Name | Kind | API Group | API Version | Namespaced | Count |\n")
+	fmt.Fprintf(w, "|---|---|---|---|---|---:|\n")
+
+	for _, resource := range resources {
+		apiGroup := resource.APIGroup
+		if apiGroup == "" {
+			apiGroup = "core"
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %t | %d |\n",
+			escapeMarkdownCell(resource.Name), escapeMarkdownCell(resource.Kind),
+			escapeMarkdownCell(apiGroup), escapeMarkdownCell(resource.APIVersion),
+			resource.Namespaced, resource.Count)
+	}
+}
+
+// writeResourcesCSV writes resources as CSV, quoting fields per RFC 4180
+func writeResourcesCSV(w io.Writer, resources []k8s.ResourceInfo) error {
+	writer := csv.NewWriter(w)
+	writer.Write(resourceCSVHeader)
+
+	for _, resource := range resources {
+		writer.Write(resourceCSVRow(resource))
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// resourceCSVHeader names the columns of resourceCSVRow
+var resourceCSVHeader = []string{"Resource Name", "Kind", "API Group", "API Version", "Namespaced", "Count"}
+
+// resourceCSVRow is the CSV export row of a resource, with "core" for the core API group
+func resourceCSVRow(resource k8s.ResourceInfo) []string {
+	apiGroup := resource.APIGroup
+	if apiGroup == "" {
+		apiGroup = "core"
+	}
+	return []string{
+		resource.Name, resource.Kind, apiGroup, resource.APIVersion,
+		strconv.FormatBool(resource.Namespaced), strconv.Itoa(resource.Count),
+	}
+}
+
+// writeResourcesJSON writes resources as a downloadable JSON array, the same data the CSV export holds
+func writeResourcesJSON(w http.ResponseWriter, namespace string, resources []k8s.ResourceInfo) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"k8s-resources-%s.json\"", namespace))
+
+	if resources == nil {
+		resources = []k8s.ResourceInfo{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(resources)
+}
+
+// escapeMarkdownCell escapes pipes and flattens newlines so a value stays inside its table cell
+func escapeMarkdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r", " ")
+	return strings.ReplaceAll(value, "\n", " ")
 }
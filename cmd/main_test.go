@@ -1,10 +1,573 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"k8s-object-explorer/internal/k8s"
+
+	"github.com/gorilla/mux"
 )
 
 func TestMain(t *testing.T) {
 	// Basic test to verify the application can be imported
 	t.Log("Main package imports successfully")
 }
+
+func TestWriteResourcesCSV(t *testing.T) {
+	resources := []k8s.ResourceInfo{
+		{Name: "pods", Kind: "Pod", APIVersion: "v1", Namespaced: true, Count: 3},
+		{Name: `say "hi"`, Kind: "Greeting, Formal", APIGroup: "example.com", APIVersion: "v1", Namespaced: false, Count: 0},
+	}
+
+	var buf bytes.Buffer
+	if err := writeResourcesCSV(&buf, resources); err != nil {
+		t.Fatalf("writeResourcesCSV returned error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export does not parse as CSV: %v\n%s", err, buf.String())
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d records", len(records))
+	}
+	if records[1][2] != "core" || records[1][5] != "3" {
+		t.Errorf("unexpected pods row: %q", records[1])
+	}
+	want := []string{`say "hi"`, "Greeting, Formal", "example.com", "v1", "false", "0"}
+	for i, field := range want {
+		if records[2][i] != field {
+			t.Errorf("field %d: expected %q, got %q", i, field, records[2][i])
+		}
+	}
+}
+
+func TestWriteResourcesJSON(t *testing.T) {
+	resources := []k8s.ResourceInfo{
+		{Name: "pods", FullName: "pods", Kind: "Pod", APIVersion: "v1", Namespaced: true, Count: 3},
+		{Name: "deployments", FullName: "deployments.apps", Kind: "Deployment", APIGroup: "apps", APIVersion: "v1", Namespaced: true, Count: 1},
+	}
+
+	rec := httptest.NewRecorder()
+	writeResourcesJSON(rec, "default", resources)
+
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="k8s-resources-default.json"` {
+		t.Errorf("unexpected Content-Disposition %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("unexpected Content-Type %q", got)
+	}
+
+	var exported []k8s.ResourceInfo
+	if err := json.NewDecoder(rec.Body).Decode(&exported); err != nil {
+		t.Fatalf("export is not a JSON array of resources: %v", err)
+	}
+	if len(exported) != 2 || exported[1].FullName != "deployments.apps" || exported[0].Count != 3 {
+		t.Errorf("unexpected exported resources: %+v", exported)
+	}
+}
+
+func TestWriteResourcesMarkdown(t *testing.T) {
+	resources := []k8s.ResourceInfo{
+		{Name: "pods", Kind: "Pod", APIGroup: "", APIVersion: "v1", Namespaced: true, Count: 3},
+		{Name: "weird|things", Kind: "Weird|Thing", APIGroup: "example.com", APIVersion: "v1alpha1", Namespaced: true, Count: 0},
+	}
+
+	var buf bytes.Buffer
+	writeResourcesMarkdown(&buf, resources)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header, separator and 2 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+
+	// Split on unescaped pipes; every row must have the same number of cells as the header
+	splitCells := func(line string) []string {
+		if !strings.HasPrefix(line, "|") || !strings.HasSuffix(line, "|") {
+			t.Fatalf("row is not enclosed in pipes: %q", line)
+		}
+		line = strings.ReplaceAll(line, "\\|", "\x00")
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(strings.ReplaceAll(cells[i], "\x00", "|"))
+		}
+		return cells
+	}
+
+	header := splitCells(lines[0])
+	if len(header) != 6 || header[0] != "Resource Name" || header[5] != "Count" {
+		t.Errorf("unexpected header cells: %q", header)
+	}
+	for _, cell := range splitCells(lines[1]) {
+		if strings.Trim(cell, "-:") != "" {
+			t.Errorf("invalid separator cell %q", cell)
+		}
+	}
+
+	first := splitCells(lines[2])
+	if len(first) != 6 || first[0] != "pods" || first[2] != "core" || first[5] != "3" {
+		t.Errorf("unexpected first row: %q", first)
+	}
+	second := splitCells(lines[3])
+	if len(second) != 6 || second[0] != "weird|things" || second[1] != "Weird|Thing" {
+		t.Errorf("pipes were not escaped correctly: %q", second)
+	}
+}
+
+func TestValidNamespaceParam(t *testing.T) {
+	cases := map[string]bool{
+		"default":        true,
+		"kube-system":    true,
+		k8s.ClusterScope: true,
+		"_other":         false,
+		"Upper":          false,
+		"":               false,
+	}
+	for namespace, expected := range cases {
+		if got := validNamespaceParam(namespace); got != expected {
+			t.Errorf("validNamespaceParam(%q) = %t, expected %t", namespace, got, expected)
+		}
+	}
+}
+
+func TestWriteClientError(t *testing.T) {
+	cases := []struct {
+		err      error
+		expected int
+	}{
+		{k8s.ErrNoClient, http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: pods", k8s.ErrResourceNotFound), http.StatusNotFound},
+		{fmt.Errorf("%w: rbac", k8s.ErrForbidden), http.StatusForbidden},
+		{k8s.ErrWritesDisabled, http.StatusForbidden},
+		{fmt.Errorf("%w: labelSelector is required", k8s.ErrInvalidArgument), http.StatusBadRequest},
+		{fmt.Errorf("%w: slow", k8s.ErrTimeout), http.StatusGatewayTimeout},
+		{&k8s.AmbiguousResourceError{Identifier: "widgets", Candidates: []string{"widgets.a.io", "widgets.b.io"}}, http.StatusConflict},
+		{&k8s.TooManyObjectsError{Resource: "pods", Count: 50000, Limit: 5000}, http.StatusRequestEntityTooLarge},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		writeClientError(rec, tc.err)
+		if rec.Code != tc.expected {
+			t.Errorf("writeClientError(%v) status = %d, expected %d", tc.err, rec.Code, tc.expected)
+		}
+	}
+}
+
+func TestWriteClientErrorAmbiguousCandidates(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeClientError(rec, &k8s.AmbiguousResourceError{Identifier: "widgets", Candidates: []string{"widgets.a.io", "widgets.b.io"}})
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+	var body struct {
+		Error      string   `json:"error"`
+		Candidates []string `json:"candidates"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(body.Candidates) != 2 || body.Candidates[0] != "widgets.a.io" || body.Candidates[1] != "widgets.b.io" {
+		t.Errorf("unexpected candidates %v", body.Candidates)
+	}
+}
+
+func TestWriteClientErrorContainers(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeClientError(rec, &k8s.ContainerError{Pod: "api", Containers: []string{"app", "proxy"}})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+	var body struct {
+		Containers []string `json:"containers"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if strings.Join(body.Containers, ",") != "app,proxy" {
+		t.Errorf("unexpected containers %v", body.Containers)
+	}
+}
+
+func TestShutdownGracePeriod(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":       defaultShutdownGracePeriod,
+		"30s":    30 * time.Second,
+		"1m":     time.Minute,
+		"soon":   defaultShutdownGracePeriod,
+		"-5s":    defaultShutdownGracePeriod,
+		"0s":     defaultShutdownGracePeriod,
+		"1500ms": 1500 * time.Millisecond,
+	}
+	for raw, expected := range cases {
+		t.Setenv("SHUTDOWN_GRACE_PERIOD", raw)
+		if period := shutdownGracePeriod(); period != expected {
+			t.Errorf("SHUTDOWN_GRACE_PERIOD=%q: expected %s, got %s", raw, expected, period)
+		}
+	}
+}
+
+func TestListenAddress(t *testing.T) {
+	cases := []struct {
+		bind, port, addr, browser string
+	}{
+		{"", "", ":8080", "localhost:8080"},
+		{"", "3000", ":3000", "localhost:3000"},
+		{"127.0.0.1", "", "127.0.0.1:8080", "127.0.0.1:8080"},
+		{"10.0.0.5", "9090", "10.0.0.5:9090", "10.0.0.5:9090"},
+		{"0.0.0.0", "8080", "0.0.0.0:8080", "localhost:8080"},
+		{"::1", "8080", "[::1]:8080", "[::1]:8080"},
+		{"[::]", "8080", "[::]:8080", "localhost:8080"},
+	}
+	for _, c := range cases {
+		t.Setenv("BIND_ADDRESS", c.bind)
+		t.Setenv("PORT", c.port)
+		addr := listenAddress()
+		if addr != c.addr {
+			t.Errorf("BIND_ADDRESS=%q PORT=%q: expected %q, got %q", c.bind, c.port, c.addr, addr)
+		}
+		if browser := browserAddress(addr); browser != c.browser {
+			t.Errorf("browserAddress(%q) = %q, expected %q", addr, browser, c.browser)
+		}
+	}
+}
+
+func TestPrewarmInterval(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":      defaultPrewarmInterval,
+		"10m":   10 * time.Minute,
+		"0":     0,
+		"-1m":   defaultPrewarmInterval,
+		"often": defaultPrewarmInterval,
+	}
+	for raw, expected := range cases {
+		t.Setenv("PREWARM_INTERVAL", raw)
+		if interval := prewarmInterval(); interval != expected {
+			t.Errorf("PREWARM_INTERVAL=%q: expected %s, got %s", raw, expected, interval)
+		}
+	}
+}
+
+func TestSortResources(t *testing.T) {
+	resources := []k8s.ResourceInfo{
+		{Name: "services", Kind: "Service", Count: 2},
+		{Name: "pods", Kind: "Pod", Count: 7},
+		{Name: "configmaps", Kind: "ConfigMap", Count: 2},
+		{Name: "deployments", Kind: "Deployment", Count: 0},
+	}
+	names := func(resources []k8s.ResourceInfo) string {
+		var names []string
+		for _, resource := range resources {
+			names = append(names, resource.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "services,pods,configmaps,deployments"},
+		{"count", "deployments,services,configmaps,pods"},
+		{"-count", "pods,services,configmaps,deployments"},
+		{"name", "configmaps,deployments,pods,services"},
+		{"-name", "services,pods,deployments,configmaps"},
+		{"kind", "configmaps,deployments,pods,services"},
+		{"-kind", "services,pods,deployments,configmaps"},
+	}
+	for _, tt := range tests {
+		sorted := append([]k8s.ResourceInfo(nil), resources...)
+		sortResources(sorted, tt.key)
+		if got := names(sorted); got != tt.want {
+			t.Errorf("sort=%q: expected %s, got %s", tt.key, tt.want, got)
+		}
+	}
+
+	for key, want := range map[string]bool{"": true, "count": true, "-kind": true, "size": false, "--name": false} {
+		if got := validSortKey(key); got != want {
+			t.Errorf("validSortKey(%q) = %t, want %t", key, got, want)
+		}
+	}
+}
+
+func TestSortObjects(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	objects := []k8s.ObjectInfo{
+		{Name: "web", Namespace: "default", CreationTimestamp: created.Add(2 * time.Hour)},
+		{Name: "api", Namespace: "default", CreationTimestamp: created},
+		{Name: "db", Namespace: "data", CreationTimestamp: created.Add(48 * time.Hour)},
+		{Name: "cache", Namespace: "default", CreationTimestamp: created.Add(time.Hour)},
+	}
+	names := func(objects []k8s.ObjectInfo) string {
+		var names []string
+		for _, object := range objects {
+			names = append(names, object.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "web,api,db,cache"},
+		{"age", "db,web,cache,api"},
+		{"-age", "api,cache,web,db"},
+		{"name", "db,api,cache,web"},
+		{"-name", "web,cache,api,db"},
+	}
+	for _, tt := range tests {
+		sorted := append([]k8s.ObjectInfo(nil), objects...)
+		sortByKey(sorted, objectSortKeys, tt.key)
+		if got := names(sorted); got != tt.want {
+			t.Errorf("sort=%q: expected %s, got %s", tt.key, tt.want, got)
+		}
+	}
+
+	for key, want := range map[string]bool{"": true, "age": true, "-name": true, "count": false, "--age": false} {
+		if got := validKey(objectSortKeys, key); got != want {
+			t.Errorf("validKey(%q) = %t, want %t", key, got, want)
+		}
+	}
+}
+
+func TestSearchMatcher(t *testing.T) {
+	tests := []struct {
+		search, mode string
+		matches      []string
+		misses       []string
+	}{
+		{"DEPLOY", "", []string{"deployments", "Deployment"}, []string{"pods"}},
+		{"set", "substring", []string{"replicasets", "StatefulSet"}, []string{"services"}},
+		{"^(deploy|replica).*", "regex", []string{"deployments", "replicasets"}, []string{"Deployment", "statefulsets"}},
+		{"(?i)^pod$", "regex", []string{"Pod"}, []string{"pods", "poddisruptionbudgets"}},
+	}
+	for _, tt := range tests {
+		match, err := searchMatcher(tt.search, tt.mode)
+		if err != nil {
+			t.Fatalf("search=%q mode=%q: unexpected error %v", tt.search, tt.mode, err)
+		}
+		for _, value := range tt.matches {
+			if !match(value) {
+				t.Errorf("search=%q mode=%q: expected %q to match", tt.search, tt.mode, value)
+			}
+		}
+		for _, value := range tt.misses {
+			if match(value) {
+				t.Errorf("search=%q mode=%q: expected %q not to match", tt.search, tt.mode, value)
+			}
+		}
+	}
+
+	if _, err := searchMatcher("(deploy", "regex"); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	if _, err := searchMatcher("pods", "glob"); err == nil {
+		t.Error("expected an error for an unknown searchMode")
+	}
+}
+
+func TestNamespaceResourcesInvalidSearch(t *testing.T) {
+	// Query parameters are validated before the client is used, so a dummy client suffices
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	rec := httptest.NewRecorder()
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/resources/default?search=(deploy&searchMode=regex", nil), map[string]string{"namespace": "default"})
+	server.getNamespaceResources(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid pattern, got %d", rec.Code)
+	}
+}
+
+func TestSearchObjectsMissingQuery(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	rec := httptest.NewRecorder()
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/search/default?q=%20", nil), map[string]string{"namespace": "default"})
+	server.searchObjects(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without a query, got %d", rec.Code)
+	}
+}
+
+func TestScaleObjectInvalidBody(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	for _, body := range []string{"", "{}", `{"replicas": "3"}`} {
+		rec := httptest.NewRecorder()
+		req := mux.SetURLVars(newJSONRequest(http.MethodPatch, "/api/object/default/deployments/web/scale", body),
+			map[string]string{"namespace": "default", "resource": "deployments", "name": "web"})
+		server.scaleObject(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %q: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestWarmCacheInvalidRequest(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	for _, body := range []string{"", `{"namespaces": "team-a"}`, `{"namespaces": ["team-a", "Not_Valid"]}`} {
+		rec := httptest.NewRecorder()
+		server.warmCache(rec, newJSONRequest(http.MethodPost, "/api/warm-cache", body))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("body %q: expected 400, got %d", body, rec.Code)
+		}
+	}
+}
+
+func TestCompareNamespacesInvalid(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	for _, query := range []string{"", "?a=team-a", "?a=team-a&b=Not_Valid"} {
+		rec := httptest.NewRecorder()
+		server.compareNamespaces(rec, httptest.NewRequest(http.MethodGet, "/api/compare"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("query %q: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestNamespaceHealthInvalidNamespace(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	rec := httptest.NewRecorder()
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/health/Not_Valid", nil), map[string]string{"namespace": "Not_Valid"})
+	server.getNamespaceHealth(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid namespace, got %d", rec.Code)
+	}
+}
+
+func TestResourceTableInvalidNamespace(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	rec := httptest.NewRecorder()
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/objects-table/Not_Valid/pods", nil),
+		map[string]string{"namespace": "Not_Valid", "resource": "pods"})
+	server.getResourceTable(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid namespace, got %d", rec.Code)
+	}
+}
+
+func TestNamespaceGroupsMissingLabelKey(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+
+	rec := httptest.NewRecorder()
+	server.getNamespaceGroups(rec, httptest.NewRequest(http.MethodGet, "/api/namespace-groups?by=%20", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without ?by=, got %d", rec.Code)
+	}
+}
+
+func TestParseObjectPath(t *testing.T) {
+	path, err := parseObjectPath("default/deployments.apps/web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != (k8s.ObjectPath{Namespace: "default", Resource: "deployments.apps", Name: "web"}) {
+		t.Errorf("unexpected path %+v", path)
+	}
+
+	for _, value := range []string{"", "default/pods", "default/pods/web/extra", "default//web", "Default/pods/web", k8s.ClusterScope + "/nodes/node-a"} {
+		if _, err := parseObjectPath(value); err == nil {
+			t.Errorf("parseObjectPath(%q): expected an error", value)
+		}
+	}
+}
+
+func TestAddPagination(t *testing.T) {
+	remaining := int64(4200)
+	response := map[string]interface{}{}
+	addPagination(response, &k8s.ObjectList{Continue: "token", RemainingItemCount: &remaining})
+	if response["continue"] != "token" || response["remainingItemCount"] != int64(4200) {
+		t.Errorf("unexpected pagination fields %v", response)
+	}
+
+	// Servers that cannot tell the remaining count leave it out instead of sending null
+	response = map[string]interface{}{}
+	addPagination(response, &k8s.ObjectList{Continue: "token"})
+	if _, found := response["remainingItemCount"]; found {
+		t.Errorf("expected remainingItemCount to be omitted, got %v", response)
+	}
+}
+
+func TestRevealSecrets(t *testing.T) {
+	tests := []struct {
+		query      string
+		allow      bool
+		origin     string
+		wantReveal bool
+		wantOK     bool
+		wantStatus int
+	}{
+		{"", false, "", false, true, http.StatusOK},
+		{"", true, "", false, true, http.StatusOK},
+		{"?reveal=true", false, "", false, false, http.StatusForbidden},
+		{"?reveal=true", true, "", true, true, http.StatusOK},
+		{"?reveal=true", true, "http://example.com", true, true, http.StatusOK},
+		{"?reveal=true", true, "https://evil.example.org", false, false, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		server := &Server{allowSecretReveal: tt.allow}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/object-raw/default/secrets/db"+tt.query, nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		reveal, ok := server.revealSecrets(rec, req)
+		if reveal != tt.wantReveal || ok != tt.wantOK || rec.Code != tt.wantStatus {
+			t.Errorf("query %q, allow %t, origin %q: got reveal=%t ok=%t status=%d, want %t %t %d",
+				tt.query, tt.allow, tt.origin, reveal, ok, rec.Code, tt.wantReveal, tt.wantOK, tt.wantStatus)
+		}
+	}
+}
+
+func TestFilterNamespaces(t *testing.T) {
+	names := []string{"default", "kube-system", "team-a", "team-b", "Team-Prod", "monitoring"}
+	identity := func(name string) string { return name }
+
+	tests := []struct {
+		search        string
+		limit         int
+		want          []string
+		wantMatched   int
+		wantTruncated bool
+	}{
+		{"", 0, names, 6, false},
+		{"", 2, []string{"default", "kube-system"}, 6, true},
+		{"", 6, names, 6, false},
+		{"team", 0, []string{"team-a", "team-b", "Team-Prod"}, 3, false},
+		{"TEAM", 2, []string{"team-a", "team-b"}, 3, true},
+		{"prod", 0, []string{"Team-Prod"}, 1, false},
+		{"nothing", 5, []string{}, 0, false},
+	}
+	for _, tt := range tests {
+		got, matched, truncated := filterNamespaces(names, identity, tt.search, tt.limit)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || matched != tt.wantMatched || truncated != tt.wantTruncated {
+			t.Errorf("search %q limit %d: got %v, %d, %t; want %v, %d, %t",
+				tt.search, tt.limit, got, matched, truncated, tt.want, tt.wantMatched, tt.wantTruncated)
+		}
+	}
+}
+
+func TestNamespacesInvalidLimit(t *testing.T) {
+	server := &Server{k8sClient: &k8s.Client{}}
+	rec := httptest.NewRecorder()
+	server.getNamespaces(rec, httptest.NewRequest(http.MethodGet, "/api/namespaces?limit=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d for limit=0", rec.Code, http.StatusBadRequest)
+	}
+}
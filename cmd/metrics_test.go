@@ -0,0 +1,48 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(withMetrics)
+	router.HandleFunc("/api/resources/{namespace}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+	})
+	router.Handle("/metrics", promhttp.Handler())
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/resources/team-a", nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 from /metrics, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+
+	for _, name := range []string{
+		"k8s_explorer_http_requests_total",
+		"k8s_explorer_http_request_duration_seconds",
+		"k8s_explorer_cache_requests_total",
+		"k8s_explorer_discovered_resource_types",
+	} {
+		if !strings.Contains(body, name) {
+			t.Errorf("expected metric %s in /metrics output", name)
+		}
+	}
+
+	// Requests are labeled by route template, not by the namespace in the path
+	if !strings.Contains(body, `k8s_explorer_http_requests_total{code="503",method="GET",route="/api/resources/{namespace}"}`) {
+		t.Errorf("expected the request counted under its route template, got:\n%s", body)
+	}
+	if strings.Contains(body, "team-a") {
+		t.Error("expected no namespace names in metric labels")
+	}
+}
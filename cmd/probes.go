@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// readinessTimeout bounds the API server check of /readyz so a hanging cluster fails the probe quickly
+const readinessTimeout = 2 * time.Second
+
+// healthz is the liveness probe: the process is alive as long as it serves HTTP
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// readyz is the readiness probe: ready once the Kubernetes client exists and the API server answers
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	if s.k8sClient == nil {
+		http.Error(w, "No Kubernetes connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	if err := s.k8sClient.Ping(ctx); err != nil {
+		http.Error(w, fmt.Sprintf("Kubernetes API server unreachable: %v", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// getStatus reports whether the explorer has a Kubernetes client and, when it has none, why, so
+// setup problems can be diagnosed without reading the server logs
+func (s *Server) getStatus(w http.ResponseWriter, r *http.Request) {
+	response := map[string]interface{}{
+		"connected": s.k8sClient != nil,
+	}
+	if s.k8sClient == nil {
+		response["reason"] = connectionFailureReason(s.k8sErr)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// connectionFailureReason describes why the Kubernetes client could not be created without exposing
+// kubeconfig paths or API server addresses
+func connectionFailureReason(err error) string {
+	var configErr *k8s.ConfigError
+	if errors.As(err, &configErr) {
+		return configErr.Reason()
+	}
+	return "failed to create Kubernetes client"
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s-object-explorer/internal/k8s"
+)
+
+// newProbeTestServer returns a Server whose client talks to a fake API server answering /version with status
+func newProbeTestServer(t *testing.T, status int) *Server {
+	t.Helper()
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"major":"1","minor":"28","gitVersion":"v1.28.2"}`)
+	}))
+	t.Cleanup(apiServer.Close)
+
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+`, apiServer.URL)
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	client, err := k8s.NewClient(path)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	return &Server{k8sClient: client}
+}
+
+func TestHealthz(t *testing.T) {
+	server := &Server{}
+
+	rec := httptest.NewRecorder()
+	server.healthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 without a Kubernetes connection, got %d", rec.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name   string
+		server *Server
+		want   int
+	}{
+		{"no client", &Server{}, http.StatusServiceUnavailable},
+		{"API server reachable", newProbeTestServer(t, http.StatusOK), http.StatusOK},
+		{"API server failing", newProbeTestServer(t, http.StatusInternalServerError), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.server.readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, configErr := k8s.NewClient(filepath.Join(t.TempDir(), "missing"))
+
+	tests := []struct {
+		name      string
+		server    *Server
+		connected bool
+		reason    string
+	}{
+		{"connected", newProbeTestServer(t, http.StatusOK), true, ""},
+		{"kubeconfig missing", &Server{k8sErr: configErr}, false, "kubeconfig not found, and not running in a cluster"},
+		{"other failure", &Server{k8sErr: errors.New("failed to create dynamic client: boom")}, false, "failed to create Kubernetes client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.server.getStatus(rec, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+
+			var body struct {
+				Connected bool    `json:"connected"`
+				Reason    *string `json:"reason"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if body.Connected != tt.connected {
+				t.Errorf("expected connected %t, got %t", tt.connected, body.Connected)
+			}
+			if tt.connected && body.Reason != nil {
+				t.Errorf("expected no reason when connected, got %q", *body.Reason)
+			}
+			if !tt.connected && (body.Reason == nil || *body.Reason != tt.reason) {
+				t.Errorf("expected reason %q, got %v", tt.reason, body.Reason)
+			}
+		})
+	}
+}
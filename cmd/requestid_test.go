@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s-object-explorer/internal/logging"
+
+	"github.com/gorilla/mux"
+)
+
+func TestRequestIDFlowsIntoLogs(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(&buf, "json", slog.LevelInfo)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	router := mux.NewRouter()
+	router.Use(withRequestID)
+	router.HandleFunc("/api/resources/{namespace}", func(w http.ResponseWriter, r *http.Request) {
+		slog.InfoContext(r.Context(), "Loading resources", "namespace", mux.Vars(r)["namespace"])
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/resources/default", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("X-Request-ID"); got != "abc-123" {
+		t.Errorf("response X-Request-ID = %q, want the request's", got)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log output is not a JSON line: %v\n%s", err, buf.String())
+	}
+	if entry["requestId"] != "abc-123" {
+		t.Errorf("log requestId = %v, want abc-123", entry["requestId"])
+	}
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	handler := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if logging.RequestID(r.Context()) != w.Header().Get("X-Request-ID") {
+			t.Error("context and response carry different request IDs")
+		}
+	}))
+
+	for _, header := range []string{"", "bad id\nwith newline", strings.Repeat("x", maxRequestIDLength+1)} {
+		req := httptest.NewRequest(http.MethodGet, "/api/namespaces", nil)
+		if header != "" {
+			req.Header.Set("X-Request-ID", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if id := rec.Header().Get("X-Request-ID"); len(id) != 32 {
+			t.Errorf("header %q: expected a generated 32-character ID, got %q", header, id)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// labelSelectorError reports an invalid ?labelSelector= with the requirement that failed to parse
+// and where it starts, since the labels parser does not report positions itself
+type labelSelectorError struct {
+	Selector    string
+	Requirement string
+	Position    int // byte offset of Requirement in Selector
+	Err         error
+}
+
+func (e *labelSelectorError) Error() string {
+	return fmt.Sprintf("invalid labelSelector at position %d (%q): %v", e.Position, e.Requirement, e.Err)
+}
+
+// parseLabelSelector validates a label selector with equality (env=prod, tier!=frontend),
+// set-based (env in (prod,staging), !canary) and existence requirements before it is sent to the
+// API server. It returns nil for a valid selector.
+func parseLabelSelector(selector string) *labelSelectorError {
+	_, err := labels.Parse(selector)
+	if err == nil {
+		return nil
+	}
+
+	// Find the first requirement that does not parse on its own
+	selectorErr := &labelSelectorError{Selector: selector, Requirement: strings.TrimSpace(selector), Err: err}
+	for _, requirement := range splitRequirements(selector) {
+		text := strings.TrimSpace(requirement.text)
+		if _, requirementErr := labels.Parse(text); requirementErr != nil || text == "" {
+			selectorErr.Requirement = text
+			selectorErr.Position = requirement.offset + strings.Index(requirement.text, text)
+			if requirementErr != nil {
+				selectorErr.Err = requirementErr
+			}
+			break
+		}
+	}
+	return selectorErr
+}
+
+// selectorRequirement is one comma-separated requirement of a selector and its byte offset
+type selectorRequirement struct {
+	text   string
+	offset int
+}
+
+// splitRequirements splits a selector at the commas that separate requirements, leaving the commas
+// inside the value sets of in and notin alone
+func splitRequirements(selector string) []selectorRequirement {
+	var requirements []selectorRequirement
+	depth, start := 0, 0
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selectorRequirement{text: selector[start:i], offset: start})
+				start = i + 1
+			}
+		}
+	}
+	return append(requirements, selectorRequirement{text: selector[start:], offset: start})
+}
+
+// writeLabelSelectorError answers an invalid label selector with 400 and where it went wrong
+func writeLabelSelectorError(w http.ResponseWriter, err *labelSelectorError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":         err.Error(),
+		"labelSelector": err.Selector,
+		"requirement":   err.Requirement,
+		"position":      err.Position,
+	})
+}
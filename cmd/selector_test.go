@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	for _, selector := range []string{
+		"",
+		"app=web",
+		"app==web,tier!=frontend",
+		"env in (prod,staging),tier!=frontend",
+		"env notin (dev), !canary, release",
+	} {
+		if err := parseLabelSelector(selector); err != nil {
+			t.Errorf("parseLabelSelector(%q) returned error: %v", selector, err)
+		}
+	}
+
+	tests := []struct {
+		selector    string
+		requirement string
+		position    int
+	}{
+		{"env in (prod,staging),tier!!=frontend", "tier!!=frontend", 22},
+		{"app=web, env in prod", "env in prod", 9},
+		{"env in (prod", "env in (prod", 0},
+		{"app=web,,tier=db", "", 8},
+		{"app=we b", "app=we b", 0},
+	}
+	for _, tt := range tests {
+		err := parseLabelSelector(tt.selector)
+		if err == nil {
+			t.Errorf("parseLabelSelector(%q): expected an error", tt.selector)
+			continue
+		}
+		if err.Requirement != tt.requirement || err.Position != tt.position {
+			t.Errorf("parseLabelSelector(%q): expected %q at %d, got %q at %d", tt.selector, tt.requirement, tt.position, err.Requirement, err.Position)
+		}
+	}
+}
+
+func TestWriteLabelSelectorError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeLabelSelectorError(rec, parseLabelSelector("env in (prod,staging),tier!!=frontend"))
+
+	var body struct {
+		Error         string `json:"error"`
+		LabelSelector string `json:"labelSelector"`
+		Requirement   string `json:"requirement"`
+		Position      int    `json:"position"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+	if body.Requirement != "tier!!=frontend" || body.Position != 22 || body.LabelSelector != "env in (prod,staging),tier!!=frontend" || body.Error == "" {
+		t.Errorf("unexpected response %+v", body)
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+
+	"k8s-object-explorer/web"
+)
+
+// webFileSystem returns the UI files to serve and where they come from: the directory WEB_DIR when
+// set, so the UI can be edited without rebuilding, and otherwise the copy embedded in the binary
+func webFileSystem() (http.FileSystem, string) {
+	dir := os.Getenv("WEB_DIR")
+	if dir == "" {
+		return http.FS(web.FS), "embedded"
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		slog.Warn("Invalid WEB_DIR, serving the embedded UI", "value", dir, "error", err)
+		return http.FS(web.FS), "embedded"
+	}
+	return http.Dir(dir), dir
+}
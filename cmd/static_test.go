@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWebFileSystemEmbedded(t *testing.T) {
+	t.Setenv("WEB_DIR", "")
+	files, source := webFileSystem()
+	if source != "embedded" {
+		t.Errorf("expected the embedded UI without WEB_DIR, got %q", source)
+	}
+
+	for _, path := range []string{"/", "/css/styles.css", "/js/app.js"} {
+		rec := httptest.NewRecorder()
+		http.FileServer(files).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected 200, got %d", path, rec.Code)
+		}
+		if path == "/" && !strings.Contains(rec.Body.String(), "<html") {
+			t.Errorf("expected index.html at /, got:\n%s", rec.Body.String())
+		}
+	}
+}
+
+func TestWebFileSystemOverride(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte("development UI"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("WEB_DIR", dir)
+	files, source := webFileSystem()
+	if source != dir {
+		t.Errorf("expected the UI from %s, got %q", dir, source)
+	}
+	rec := httptest.NewRecorder()
+	http.FileServer(files).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Body.String() != "development UI" {
+		t.Errorf("expected index.html of WEB_DIR, got %q", rec.Body.String())
+	}
+
+	t.Setenv("WEB_DIR", filepath.Join(dir, "missing"))
+	if _, source := webFileSystem(); source != "embedded" {
+		t.Errorf("expected the embedded UI for a missing WEB_DIR, got %q", source)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// defaultRequestTimeout bounds a request when REQUEST_TIMEOUT is not set
+const defaultRequestTimeout = 60 * time.Second
+
+// defaultMaxRequestBodyBytes caps request bodies when MAX_REQUEST_BODY_BYTES is not set
+const defaultMaxRequestBodyBytes int64 = 1 << 20
+
+// streamingRoutes are the routes that hold their response open and flush it as they go: the SSE
+// streams and the streamed export. A handler timeout would buffer and then cut them off, so they run
+// without one.
+var streamingRoutes = map[string]bool{
+	"/api/debug-stream/{namespace}":     true,
+	"/api/watch/{namespace}/{resource}": true,
+	"/api/export-all":                   true,
+}
+
+// withLimits caps request bodies at maxBody bytes and answers 503 when a handler runs longer than
+// timeout, cancelling its context so the Kubernetes calls it made stop too. Streaming routes only
+// get the body cap.
+func withLimits(timeout time.Duration, maxBody int64) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		timed := http.TimeoutHandler(next, timeout, "Request timed out")
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBody)
+			}
+			if isStreamingRoute(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			timed.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isStreamingRoute reports whether r matched one of streamingRoutes
+func isStreamingRoute(r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	template, err := route.GetPathTemplate()
+	return err == nil && streamingRoutes[template]
+}
+
+// requestTimeout reads REQUEST_TIMEOUT as a duration ("30s", "2m"), falling back to the default
+func requestTimeout() time.Duration {
+	raw := os.Getenv("REQUEST_TIMEOUT")
+	if raw == "" {
+		return defaultRequestTimeout
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		slog.Warn("Invalid REQUEST_TIMEOUT, using default", "value", raw, "default", defaultRequestTimeout)
+		return defaultRequestTimeout
+	}
+	return timeout
+}
+
+// maxRequestBodyBytes reads MAX_REQUEST_BODY_BYTES as a positive number of bytes, falling back to the default
+func maxRequestBodyBytes() int64 {
+	raw := os.Getenv("MAX_REQUEST_BODY_BYTES")
+	if raw == "" {
+		return defaultMaxRequestBodyBytes
+	}
+
+	limit, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || limit <= 0 {
+		slog.Warn("Invalid MAX_REQUEST_BODY_BYTES, using default", "value", raw, "default", defaultMaxRequestBodyBytes)
+		return defaultMaxRequestBodyBytes
+	}
+	return limit
+}
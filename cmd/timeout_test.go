@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// newLimitsTestRouter serves a slow handler on a regular and on a streaming route
+func newLimitsTestRouter(timeout time.Duration, maxBody int64) *mux.Router {
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			w.Write([]byte("done"))
+		case <-r.Context().Done():
+		}
+	}
+
+	router := mux.NewRouter()
+	router.Use(withLimits(timeout, maxBody))
+	router.HandleFunc("/api/resources/{namespace}", slow)
+	router.HandleFunc("/api/debug-stream/{namespace}", slow)
+	router.HandleFunc("/api/delete-collection/{namespace}/{resource}", func(w http.ResponseWriter, r *http.Request) {
+		var tooLarge *http.MaxBytesError
+		if _, err := io.ReadAll(r.Body); errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	}).Methods("POST")
+	return router
+}
+
+func TestWithLimitsTimesOutSlowHandler(t *testing.T) {
+	router := newLimitsTestRouter(20*time.Millisecond, 1024)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/resources/default", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "Request timed out") {
+		t.Errorf("expected 503 Request timed out, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWithLimitsExemptsStreams(t *testing.T) {
+	router := newLimitsTestRouter(20*time.Millisecond, 1024)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/debug-stream/default", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "done" {
+		t.Errorf("expected the stream to run past the timeout, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWithLimitsCapsRequestBody(t *testing.T) {
+	router := newLimitsTestRouter(time.Second, 16)
+
+	for body, expected := range map[string]int{
+		`{"a":1}`:                 http.StatusOK,
+		strings.Repeat("x", 1024): http.StatusRequestEntityTooLarge,
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/delete-collection/default/pods", strings.NewReader(body)))
+		if rec.Code != expected {
+			t.Errorf("%d byte body: expected %d, got %d", len(body), expected, rec.Code)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	cases := map[string]time.Duration{
+		"":     defaultRequestTimeout,
+		"90s":  90 * time.Second,
+		"soon": defaultRequestTimeout,
+		"-1s":  defaultRequestTimeout,
+	}
+	for raw, expected := range cases {
+		t.Setenv("REQUEST_TIMEOUT", raw)
+		if timeout := requestTimeout(); timeout != expected {
+			t.Errorf("REQUEST_TIMEOUT=%q: expected %s, got %s", raw, expected, timeout)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+)
+
+// tlsFiles reads TLS_CERT_FILE and TLS_KEY_FILE. HTTPS needs both; when only one is set the server
+// falls back to plain HTTP and both are returned empty.
+func tlsFiles() (certFile, keyFile string) {
+	certFile, keyFile = os.Getenv("TLS_CERT_FILE"), os.Getenv("TLS_KEY_FILE")
+	if (certFile == "") != (keyFile == "") {
+		slog.Warn("TLS_CERT_FILE and TLS_KEY_FILE must both be set for HTTPS, serving plain HTTP",
+			"certFile", certFile, "keyFile", keyFile)
+		return "", ""
+	}
+	return certFile, keyFile
+}
+
+// listenAndServe serves srv on its address, over HTTPS when certFile and keyFile are set
+func listenAndServe(srv *http.Server, certFile, keyFile string) error {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return serve(srv, listener, certFile, keyFile)
+}
+
+// serve serves srv on listener, over HTTPS when certFile and keyFile are set
+func serve(srv *http.Server, listener net.Listener, certFile, keyFile string) error {
+	if certFile != "" && keyFile != "" {
+		slog.Info("🔒 Serving HTTPS", "addr", listener.Addr().String(), "certFile", certFile)
+		return srv.ServeTLS(listener, certFile, keyFile)
+	}
+	slog.Info("Serving plain HTTP", "addr", listener.Addr().String())
+	return srv.Serve(listener)
+}
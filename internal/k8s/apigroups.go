@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/client-go/discovery"
+)
+
+// APIGroupInfo describes a discovered API group and the versions the server serves for it
+type APIGroupInfo struct {
+	Name             string   `json:"name"` // empty for the core group
+	PreferredVersion string   `json:"preferredVersion"`
+	Versions         []string `json:"versions"`
+}
+
+// GetAPIGroups returns the API groups served by the cluster sorted by name, cached like the API resources
+func (c *Client) GetAPIGroups() ([]APIGroupInfo, error) {
+	if c.discoveryClient == nil {
+		return nil, fmt.Errorf("%w: no discovery client", ErrNoClient)
+	}
+
+	c.resourcesMu.RLock()
+	cachedGroups, cacheTime := c.apiGroupsCache, c.apiGroupsCacheTime
+	c.resourcesMu.RUnlock()
+	if cachedGroups != nil && time.Since(cacheTime) < c.cacheTTL {
+		return cachedGroups, nil
+	}
+
+	list, err := c.discoveryClient.ServerGroups()
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	groups := make([]APIGroupInfo, 0, len(list.Groups))
+	for _, group := range list.Groups {
+		info := APIGroupInfo{
+			Name:             group.Name,
+			PreferredVersion: group.PreferredVersion.Version,
+			Versions:         make([]string, 0, len(group.Versions)),
+		}
+		for _, version := range group.Versions {
+			info.Versions = append(info.Versions, version.Version)
+		}
+		groups = append(groups, info)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+
+	c.resourcesMu.Lock()
+	c.apiGroupsCache = groups
+	c.apiGroupsCacheTime = time.Now()
+	c.resourcesMu.Unlock()
+
+	return groups, nil
+}
+
+// DiscoveryWarning names an API group version whose discovery failed, typically an aggregated API
+// such as metrics.k8s.io whose backing service is down. Its resources are missing from the counts.
+type DiscoveryWarning struct {
+	GroupVersion string `json:"groupVersion"`
+	Error        string `json:"error"`
+}
+
+// DiscoveryWarnings returns the API group versions that failed the last API resource discovery,
+// sorted by group version. It is empty when discovery fully succeeded.
+func (c *Client) DiscoveryWarnings() []DiscoveryWarning {
+	c.resourcesMu.RLock()
+	defer c.resourcesMu.RUnlock()
+
+	warnings := make([]DiscoveryWarning, len(c.discoveryWarnings))
+	copy(warnings, c.discoveryWarnings)
+	return warnings
+}
+
+// discoveryWarnings lists the failed group versions of a partial discovery failure
+func discoveryWarnings(err *discovery.ErrGroupDiscoveryFailed) []DiscoveryWarning {
+	warnings := make([]DiscoveryWarning, 0, len(err.Groups))
+	for groupVersion, groupErr := range err.Groups {
+		warnings = append(warnings, DiscoveryWarning{GroupVersion: groupVersion.String(), Error: groupErr.Error()})
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].GroupVersion < warnings[j].GroupVersion })
+	return warnings
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetAPIGroups(t *testing.T) {
+	client := newTestClient()
+	discoveryClient := client.discoveryClient.(*testDiscovery)
+	discoveryClient.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1"},
+		{GroupVersion: "autoscaling/v2"},
+		{GroupVersion: "autoscaling/v1"},
+		{GroupVersion: "apps/v1"},
+		{GroupVersion: "example.com/v1beta1"},
+		{GroupVersion: "example.com/v1alpha1"},
+	}
+	groupCalls := 0
+	discoveryClient.PrependReactor("get", "group", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		groupCalls++
+		return false, nil, nil
+	})
+
+	groups, err := client.GetAPIGroups()
+	if err != nil {
+		t.Fatalf("GetAPIGroups returned error: %v", err)
+	}
+	want := []APIGroupInfo{
+		{Name: "", PreferredVersion: "v1", Versions: []string{"v1"}},
+		{Name: "apps", PreferredVersion: "v1", Versions: []string{"v1"}},
+		{Name: "autoscaling", PreferredVersion: "v2", Versions: []string{"v2", "v1"}},
+		{Name: "example.com", PreferredVersion: "v1beta1", Versions: []string{"v1beta1", "v1alpha1"}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("expected %+v, got %+v", want, groups)
+	}
+
+	// Served from the cache until it expires or is cleared
+	if _, err := client.GetAPIGroups(); err != nil {
+		t.Fatalf("GetAPIGroups returned error: %v", err)
+	}
+	if groupCalls != 1 {
+		t.Errorf("expected 1 discovery call with a warm cache, got %d", groupCalls)
+	}
+	client.ClearCache()
+	if _, err := client.GetAPIGroups(); err != nil {
+		t.Fatalf("GetAPIGroups returned error: %v", err)
+	}
+	if groupCalls != 2 {
+		t.Errorf("expected discovery to run again after ClearCache, got %d calls", groupCalls)
+	}
+}
+
+func TestDiscoveryWarnings(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+	client.discoveryClient.(*testDiscovery).err = &discovery.ErrGroupDiscoveryFailed{
+		Groups: map[schema.GroupVersion]error{
+			{Group: "metrics.k8s.io", Version: "v1beta1"}:        errors.New("the server is currently unable to handle the request"),
+			{Group: "custom.metrics.k8s.io", Version: "v1beta2"}: errors.New("service unavailable"),
+		},
+	}
+
+	// The resources that were discovered are still counted
+	resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	pods := false
+	for _, resource := range resources {
+		pods = pods || (resource.Name == "pods" && resource.Count == 1)
+	}
+	if !pods {
+		t.Errorf("expected the pod to be counted despite the failed groups, got %+v", resources)
+	}
+
+	warnings := client.DiscoveryWarnings()
+	if len(warnings) != 2 || warnings[0].GroupVersion != "custom.metrics.k8s.io/v1beta2" || warnings[1].GroupVersion != "metrics.k8s.io/v1beta1" {
+		t.Fatalf("expected both failed groups sorted, got %+v", warnings)
+	}
+	if warnings[1].Error != "the server is currently unable to handle the request" {
+		t.Errorf("unexpected warning error %q", warnings[1].Error)
+	}
+
+	// A discovery that succeeds again clears the warnings
+	client.discoveryClient.(*testDiscovery).err = nil
+	client.ClearCache()
+	if _, err := client.GetAPIResources(); err != nil {
+		t.Fatalf("GetAPIResources returned error: %v", err)
+	}
+	if warnings := client.DiscoveryWarnings(); len(warnings) != 0 {
+		t.Errorf("expected no warnings after a full discovery, got %+v", warnings)
+	}
+}
@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"time"
+)
+
+// CacheStats describes the contents and freshness of the client caches
+type CacheStats struct {
+	TTL time.Duration
+	// ResourcesCached reports whether the API resource catalog is cached; ResourceCount and
+	// ResourcesAge are only meaningful when it is
+	ResourcesCached bool
+	ResourceCount   int
+	ResourcesAge    time.Duration
+	Namespaces      map[string]NamespaceCacheStats
+}
+
+// NamespaceCacheStats describes the cached resources of one namespace
+type NamespaceCacheStats struct {
+	Entries int
+	Age     time.Duration
+	Watched bool // kept current by watches instead of expiring with the TTL
+}
+
+// GetCacheStats reports the age and size of the API resource cache and of every namespace cache
+func (c *Client) GetCacheStats() CacheStats {
+	now := time.Now()
+	stats := CacheStats{TTL: c.cacheTTL, Namespaces: make(map[string]NamespaceCacheStats)}
+
+	c.resourcesMu.RLock()
+	if c.resourcesCache != nil {
+		stats.ResourcesCached = true
+		stats.ResourceCount = len(c.resourcesCache)
+		stats.ResourcesAge = now.Sub(c.resourcesCacheTime)
+	}
+	c.resourcesMu.RUnlock()
+
+	c.namespaceMu.RLock()
+	for namespace, resources := range c.namespaceCaches {
+		_, watched := c.namespaceWatches[namespace]
+		stats.Namespaces[namespace] = NamespaceCacheStats{
+			Entries: len(resources),
+			Age:     now.Sub(c.namespaceCacheTimes[namespace]),
+			Watched: watched,
+		}
+	}
+	c.namespaceMu.RUnlock()
+
+	return stats
+}
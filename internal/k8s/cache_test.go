@@ -0,0 +1,222 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestConcurrentNamespaceRequestsAreRaceFree(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", nil),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+		newTestObject("v1", "ConfigMap", "default", "settings", nil),
+	)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetResourcesInNamespaceWithCallback(context.Background(), "default", nil); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent request returned error: %v", err)
+	}
+
+	resources, _, exists := client.cachedNamespaceResources("default")
+	if !exists {
+		t.Fatal("expected namespace to be cached")
+	}
+	for _, resource := range resources {
+		if resource.Name == "pods" && resource.Count != 2 {
+			t.Errorf("expected 2 pods, got %d", resource.Count)
+		}
+	}
+}
+
+func TestClearCache(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+	for _, namespace := range []string{"default", "kube-system"} {
+		if _, err := client.GetResourcesInNamespace(context.Background(), namespace); err != nil {
+			t.Fatalf("GetResourcesInNamespace(%s) returned error: %v", namespace, err)
+		}
+	}
+
+	// The API resource cache plus two namespace caches
+	if cleared := client.ClearCache(); cleared != 3 {
+		t.Errorf("expected 3 cleared entries, got %d", cleared)
+	}
+	if _, _, exists := client.cachedNamespaceResources("default"); exists {
+		t.Error("expected default namespace cache to be cleared")
+	}
+	if client.resourcesCache != nil || !client.resourcesCacheTime.IsZero() {
+		t.Error("expected API resource cache to be cleared")
+	}
+	if cleared := client.ClearCache(); cleared != 0 {
+		t.Errorf("expected nothing left to clear, got %d", cleared)
+	}
+}
+
+func TestClearNamespaceCache(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+	for _, namespace := range []string{"default", "kube-system"} {
+		if _, err := client.GetResourcesInNamespace(context.Background(), namespace); err != nil {
+			t.Fatalf("GetResourcesInNamespace(%s) returned error: %v", namespace, err)
+		}
+	}
+
+	if cleared := client.ClearNamespaceCache("default"); cleared != 1 {
+		t.Errorf("expected 1 cleared entry, got %d", cleared)
+	}
+	if _, _, exists := client.cachedNamespaceResources("default"); exists {
+		t.Error("expected default namespace cache to be cleared")
+	}
+	if _, _, exists := client.cachedNamespaceResources("kube-system"); !exists {
+		t.Error("expected kube-system namespace cache to be kept")
+	}
+	if client.resourcesCache == nil {
+		t.Error("expected API resource cache to be kept")
+	}
+	if cleared := client.ClearNamespaceCache("missing"); cleared != 0 {
+		t.Errorf("expected 0 cleared entries for an uncached namespace, got %d", cleared)
+	}
+}
+
+func TestNamespaceCacheTime(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+	if cacheTime := client.NamespaceCacheTime("default"); !cacheTime.IsZero() {
+		t.Fatalf("expected zero time before counting, got %v", cacheTime)
+	}
+
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	counted := client.NamespaceCacheTime("default")
+	if counted.IsZero() {
+		t.Fatal("expected a cache time after counting")
+	}
+
+	// A refresh moves the time, so ETags derived from it change
+	client.ClearNamespaceCache("default")
+	time.Sleep(time.Millisecond)
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	if refreshed := client.NamespaceCacheTime("default"); !refreshed.After(counted) {
+		t.Errorf("expected the cache time to move on refresh, got %v then %v", counted, refreshed)
+	}
+}
+
+func TestGetCacheStats(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+
+	stats := client.GetCacheStats()
+	if stats.ResourcesCached || len(stats.Namespaces) != 0 {
+		t.Fatalf("expected empty caches, got %+v", stats)
+	}
+	if stats.TTL != client.cacheTTL {
+		t.Errorf("expected TTL %s, got %s", client.cacheTTL, stats.TTL)
+	}
+
+	resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+
+	// Backdate the namespace cache so its reported age is known
+	client.namespaceMu.Lock()
+	client.namespaceCacheTimes["default"] = time.Now().Add(-2 * time.Minute)
+	client.namespaceMu.Unlock()
+
+	stats = client.GetCacheStats()
+	const tolerance = 5 * time.Second
+	apiResources, err := client.GetAPIResources()
+	if err != nil {
+		t.Fatalf("GetAPIResources returned error: %v", err)
+	}
+	if !stats.ResourcesCached || stats.ResourceCount != len(apiResources) {
+		t.Errorf("expected %d cached API resources, got cached=%t count=%d", len(apiResources), stats.ResourcesCached, stats.ResourceCount)
+	}
+	if stats.ResourcesAge < 0 || stats.ResourcesAge > tolerance {
+		t.Errorf("expected fresh API resource cache, got age %s", stats.ResourcesAge)
+	}
+
+	nsStats, exists := stats.Namespaces["default"]
+	if !exists {
+		t.Fatal("expected stats for default namespace")
+	}
+	if nsStats.Entries != len(resources) {
+		t.Errorf("expected %d namespace entries, got %d", len(resources), nsStats.Entries)
+	}
+	if nsStats.Age < 2*time.Minute || nsStats.Age > 2*time.Minute+tolerance {
+		t.Errorf("expected namespace cache age of about 2m, got %s", nsStats.Age)
+	}
+}
+
+func TestConcurrentColdNamespaceCountedOnce(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+
+	// Hold the first pods count so the other requests arrive while it is in flight
+	var mu sync.Mutex
+	podLists := 0
+	listing := make(chan struct{})
+	release := make(chan struct{})
+	fakeMetadataClient(client).PrependReactor("list", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			mu.Lock()
+			podLists++
+			first := podLists == 1
+			mu.Unlock()
+			if first {
+				close(listing)
+				<-release
+			}
+			return false, nil, nil
+		})
+
+	var wg sync.WaitGroup
+	results := make([][]ResourceInfo, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+			if err != nil {
+				t.Errorf("request %d returned error: %v", i, err)
+			}
+			results[i] = resources
+		}(i)
+	}
+
+	<-listing
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if podLists != 1 {
+		t.Errorf("expected the namespace to be counted once, pods were listed %d times", podLists)
+	}
+	for i, resources := range results {
+		if len(resources) == 0 || len(resources) != len(results[0]) {
+			t.Errorf("request %d: expected the shared result of %d resources, got %d", i, len(results[0]), len(resources))
+		}
+	}
+}
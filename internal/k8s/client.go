@@ -1,39 +1,165 @@
 package k8s
 
 import (
+	"container/list"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/rand"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"k8s-object-explorer/internal/metrics"
+
+	"golang.org/x/sync/singleflight"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
+// ClusterScope is the reserved namespace value that selects cluster-scoped resources.
+// Namespace names are DNS labels and can never contain an underscore, so it cannot collide.
+const ClusterScope = "_cluster"
+
+// AllNamespaces is the reserved cache key of the cluster-wide counts of GetResourceCountsAllNamespaces
+const AllNamespaces = "_all"
+
+// defaultSkipResources are create-only or virtual resources that cannot be listed and are never
+// counted; EXTRA_SKIP_RESOURCES adds to them
+var defaultSkipResources = map[string]bool{
+	"bindings":                  true,
+	"localsubjectaccessreviews": true,
+	"selfsubjectaccessreviews":  true,
+	"selfsubjectrulesreviews":   true,
+	"uploadtokenrequests":       true,
+	"tokenrequests":             true,
+	"subjectaccessreviews":      true,
+}
+
 // Client represents a Kubernetes client with discovery capabilities
 type Client struct {
-	clientset       *kubernetes.Clientset
+	clientset       kubernetes.Interface
 	dynamicClient   dynamic.Interface
+	metadataClient  metadata.Interface
 	discoveryClient discovery.DiscoveryInterface
+	restClient      rest.Interface // raw requests such as server-side printed Tables
 	config          *rest.Config
 
-	// Cache for resource discovery
+	// Namespace of the kubeconfig context, see CurrentNamespace
+	namespace string
+
+	// Cache for resource and API group discovery, guarded by resourcesMu
+	resourcesMu        sync.RWMutex
 	resourcesCache     []ResourceInfo
 	resourcesCacheTime time.Time
+	apiGroupsCache     []APIGroupInfo
+	apiGroupsCacheTime time.Time
 	cacheTTL           time.Duration
 
-	// Cache for namespace resource counts
-	namespaceCaches     map[string][]ResourceInfo // namespace -> resources with counts
-	namespaceCacheTimes map[string]time.Time      // namespace -> cache time
+	// API groups that failed the last discovery, guarded by resourcesMu
+	discoveryWarnings []DiscoveryWarning
+
+	// OpenAPI schemas by resource FullName, guarded by schemaMu. Schemas only change when an API is
+	// upgraded or a CRD is edited, so they are kept until ClearCache rather than expiring with the TTL.
+	schemaMu    sync.Mutex
+	schemaCache map[string]map[string]interface{}
+
+	// Cache for namespace resource counts, guarded by namespaceMu
+	namespaceMu         sync.RWMutex
+	namespaceCaches     map[string][]ResourceInfo  // namespace -> resources with counts
+	namespaceCacheTimes map[string]time.Time       // namespace -> cache time
+	namespaceStatus     map[string]NamespaceStatus // namespace -> counting status
+	namespaceWatches    map[string]*namespaceWatch // namespace -> watches keeping its counts current
+	// Watched namespaces, most recently used first, and how many may be watched at once, 0 for any
+	// number (WATCH_CACHE_MAX_NAMESPACES)
+	watchOrder           *list.List
+	maxWatchedNamespaces int
+
+	// Counts of past scans per namespace and resource FullName, guarded by historyMu
+	historyMu        sync.Mutex
+	countHistories   map[string]map[string]*countRing
+	countHistorySize int // samples kept per resource (COUNT_HISTORY_SIZE)
+
+	// Keep cached namespace counts current with watches instead of TTL expiry (WATCH_CACHE)
+	watchCache bool
+
+	// Count and list with resourceVersion=0, served from the API server's watch cache instead of a
+	// quorum read from etcd (USE_WATCH_CACHE)
+	useWatchCache bool
+
+	// Recently fetched objects, nil unless OBJECT_CACHE_SIZE is set
+	objectCache *objectCache
+
+	// Discover every served version of each API group instead of only the preferred one (ALL_VERSIONS)
+	allVersions bool
+
+	// Discover subresources such as pods/status alongside the resources (INCLUDE_SUBRESOURCES)
+	includeSubresources bool
+
+	// Deduplicates concurrent counts of the same namespace
+	countGroup singleflight.Group
+
+	// Page size for list and count requests (LIST_CHUNK_SIZE)
+	listChunkSize int64
+
+	// Timeouts for counting one resource type (COUNT_TIMEOUT) and for listing or reading objects (LIST_TIMEOUT)
+	countTimeout time.Duration
+	listTimeout  time.Duration
+
+	// Fraction of countTimeout added at random to each count's deadline, so counts that time out
+	// together do not fail and retry in lockstep (COUNT_TIMEOUT_JITTER). jitter returns a number in
+	// [0, 1) and is replaced in tests.
+	countTimeoutJitter float64
+	jitter             func() float64
+
+	// Number of resource types counted in parallel per namespace scan (COUNT_CONCURRENCY)
+	countConcurrency int
+
+	// Bounds the count operations in flight across all scans (GLOBAL_COUNT_LIMIT)
+	countLimiter *countLimiter
+
+	// Maximum number of resource types returned to the UI, 0 for no limit (MAX_RESOURCE_TYPES)
+	maxResourceTypes int
+
+	// Maximum number of objects a listing without limit may return (MAX_OBJECTS)
+	maxObjects int
+
+	// Write operations are refused unless enabled (ALLOW_WRITES) and limited to these
+	// resources by FullName (WRITABLE_RESOURCES)
+	allowWrites       bool
+	writableResources map[string]bool
+
+	// Resource types that are never counted, by Name or FullName: defaultSkipResources plus
+	// EXTRA_SKIP_RESOURCES
+	skipResources map[string]bool
+
+	// Resource types to count exclusively (INCLUDE_RESOURCES) or to leave out (EXCLUDE_RESOURCES),
+	// by Name or FullName; the include list wins when both are set
+	includeResources map[string]bool
+	excludeResources map[string]bool
+
+	// Namespaces checked for access when listing namespaces is forbidden (ACCESSIBLE_NAMESPACES)
+	accessibleNamespaces map[string]bool
+
+	// Directory the caches are persisted to for warm restarts, empty to disable (CACHE_DIR);
+	// persistMu serializes writes of the cache file
+	cacheDir  string
+	persistMu sync.Mutex
 }
 
 // ResourceInfo contains information about a Kubernetes resource
@@ -46,42 +172,80 @@ type ResourceInfo struct {
 	APIGroup    string `json:"apiGroup"`
 	APIVersion  string `json:"apiVersion"`
 	Namespaced  bool   `json:"namespaced"`
-	Count       int    `json:"count"`
+	// Versions lists every served version, preferred first, when ALL_VERSIONS is set. Objects are
+	// counted and listed at APIVersion unless an identifier of the form name.version.group selects
+	// another one.
+	Versions []string `json:"versions,omitempty"`
+	// Subresource marks entries such as pods/status or deployments/scale, which are only discovered
+	// with INCLUDE_SUBRESOURCES and are never counted or listed
+	Subresource bool `json:"subresource,omitempty"`
+	Count       int  `json:"count"`
+	// CountStatus tells why Count is 0 when counting failed, and Error holds the failure
+	CountStatus CountStatus `json:"countStatus,omitempty"`
+	Error       string      `json:"error,omitempty"`
+
+	// resourceVersion of the list Count was taken at, empty when counting failed
+	resourceVersion string
 }
 
 // ObjectInfo contains information about a Kubernetes object
 type ObjectInfo struct {
-	Name              string                 `json:"name"`
-	Namespace         string                 `json:"namespace,omitempty"`
-	Kind              string                 `json:"kind"`
-	APIVersion        string                 `json:"apiVersion"`
-	CreationTimestamp time.Time              `json:"creationTimestamp"`
-	Labels            map[string]string      `json:"labels,omitempty"`
-	Annotations       map[string]string      `json:"annotations,omitempty"`
-	Status            map[string]interface{} `json:"status,omitempty"`
-	Spec              map[string]interface{} `json:"spec,omitempty"`
-}
-
-// NewClient creates a new Kubernetes client
+	Name                  string                 `json:"name"`
+	Namespace             string                 `json:"namespace,omitempty"`
+	Kind                  string                 `json:"kind"`
+	APIVersion            string                 `json:"apiVersion"`
+	CreationTimestamp     time.Time              `json:"creationTimestamp"`
+	CreationTimestampUnix int64                  `json:"creationTimestampUnix"` // seconds since the epoch, for sorting
+	Age                   string                 `json:"age"`                   // relative to now, e.g. "5m" or "3d4h"
+	Labels                map[string]string      `json:"labels,omitempty"`
+	Annotations           map[string]string      `json:"annotations,omitempty"`
+	OwnerReferences       []OwnerRef             `json:"ownerReferences,omitempty"`
+	Status                map[string]interface{} `json:"status,omitempty"`
+	Spec                  map[string]interface{} `json:"spec,omitempty"`
+	Usage                 *PodMetrics            `json:"usage,omitempty"` // live usage of Pods and Nodes, see AddResourceUsage
+}
+
+// NewClient creates a new Kubernetes client. The kubeconfig is resolved like kubectl does: an
+// explicit path wins, otherwise the colon-separated $KUBECONFIG paths and then ~/.kube/config are
+// used. Without a usable kubeconfig the in-cluster config is used.
 func NewClient(kubeconfig string) (*Client, error) {
-	var config *rest.Config
-	var err error
+	config, err := restConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client.namespace = kubeconfigNamespace(kubeconfig, "")
+	return client, nil
+}
 
-	if kubeconfig == "" {
-		if home := homedir.HomeDir(); home != "" {
-			kubeconfig = filepath.Join(home, ".kube", "config")
-		}
+// restConfig builds the REST config of the kubeconfig's current context, falling back to the
+// in-cluster config when no kubeconfig can be loaded
+func restConfig(kubeconfig string) (*rest.Config, error) {
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules(kubeconfig),
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+	if err == nil {
+		return config, nil
 	}
 
-	// Try to use kubeconfig file first
-	config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
-	if err != nil {
-		// Fall back to in-cluster config
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, fmt.Errorf("failed to create kubernetes config: %v", err)
-		}
+	config, inClusterErr := rest.InClusterConfig()
+	if inClusterErr != nil {
+		return nil, &ConfigError{Kubeconfig: err, InCluster: inClusterErr}
 	}
+	return config, nil
+}
+
+// newClientForConfig creates the API clients for a REST config
+func newClientForConfig(config *rest.Config) (*Client, error) {
+	// Counting a namespace fires hundreds of list calls; client-go's default limits (5 QPS, burst 10) throttle them
+	config.QPS = float32(envFloat("K8S_QPS", defaultQPS))
+	config.Burst = int(envInt64("K8S_BURST", defaultBurst))
+	slog.Info("Kubernetes client rate limits", "qps", config.QPS, "burst", config.Burst)
+	applyEnvImpersonation(config)
 
 	// Create clientset
 	clientset, err := kubernetes.NewForConfig(config)
@@ -95,35 +259,91 @@ func NewClient(kubeconfig string) (*Client, error) {
 		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
 	}
 
+	// Create metadata client for metadata-only (PartialObjectMetadata) requests
+	metadataClient, err := metadata.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata client: %v", err)
+	}
+
 	// Create discovery client
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create discovery client: %v", err)
 	}
 
-	return &Client{
-		clientset:           clientset,
-		dynamicClient:       dynamicClient,
-		discoveryClient:     discoveryClient,
-		config:              config,
-		cacheTTL:            5 * time.Minute, // Cache for 5 minutes
-		namespaceCaches:     make(map[string][]ResourceInfo),
-		namespaceCacheTimes: make(map[string]time.Time),
-	}, nil
+	// Create REST client for raw requests such as server-side printed Tables
+	restClient, err := newRESTClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST client: %v", err)
+	}
+
+	client := newClient(clientset, dynamicClient, metadataClient, discoveryClient, config)
+	client.restClient = restClient
+	return client, nil
+}
+
+// newRESTClient returns a client for requests by absolute path, not bound to an API group
+func newRESTClient(config *rest.Config) (rest.Interface, error) {
+	config = rest.CopyConfig(config)
+	config.GroupVersion = nil
+	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	return rest.UnversionedRESTClientFor(config)
+}
+
+// newClient wires the given API clients into a Client with empty caches
+func newClient(clientset kubernetes.Interface, dynamicClient dynamic.Interface, metadataClient metadata.Interface, discoveryClient discovery.DiscoveryInterface, config *rest.Config) *Client {
+	c := &Client{
+		clientset:            clientset,
+		dynamicClient:        dynamicClient,
+		metadataClient:       metadataClient,
+		discoveryClient:      discoveryClient,
+		config:               config,
+		cacheTTL:             5 * time.Minute, // Cache for 5 minutes
+		namespaceCaches:      make(map[string][]ResourceInfo),
+		namespaceCacheTimes:  make(map[string]time.Time),
+		namespaceStatus:      make(map[string]NamespaceStatus),
+		namespaceWatches:     make(map[string]*namespaceWatch),
+		watchOrder:           list.New(),
+		maxWatchedNamespaces: int(envLimit("WATCH_CACHE_MAX_NAMESPACES", defaultMaxWatchedNamespaces)),
+		watchCache:           envBool("WATCH_CACHE"),
+		useWatchCache:        envBool("USE_WATCH_CACHE"),
+		allVersions:          envBool("ALL_VERSIONS"),
+		includeSubresources:  envBool("INCLUDE_SUBRESOURCES"),
+		objectCache:          newObjectCache(int(envInt64("OBJECT_CACHE_SIZE", 0)), envDuration("OBJECT_CACHE_TTL", defaultObjectCacheTTL)),
+		listChunkSize:        envInt64("LIST_CHUNK_SIZE", defaultListChunkSize),
+		countTimeout:         envDuration("COUNT_TIMEOUT", defaultCountTimeout),
+		listTimeout:          envDuration("LIST_TIMEOUT", defaultListTimeout),
+		countTimeoutJitter:   envFraction("COUNT_TIMEOUT_JITTER", defaultCountTimeoutJitter),
+		jitter:               rand.Float64,
+		countConcurrency:     int(envInt64("COUNT_CONCURRENCY", defaultCountConcurrency)),
+		countLimiter:         newCountLimiter(int(envInt64("GLOBAL_COUNT_LIMIT", defaultGlobalCountLimit))),
+		maxResourceTypes:     int(envLimit("MAX_RESOURCE_TYPES", 0)),
+		countHistorySize:     int(envInt64("COUNT_HISTORY_SIZE", defaultCountHistorySize)),
+		maxObjects:           int(envLimit("MAX_OBJECTS", defaultMaxObjects)),
+		allowWrites:          envBool("ALLOW_WRITES"),
+		writableResources:    envSet("WRITABLE_RESOURCES", defaultWritableResources),
+		skipResources:        skipResourceSet(),
+		includeResources:     envSet("INCLUDE_RESOURCES", ""),
+		excludeResources:     envSet("EXCLUDE_RESOURCES", ""),
+		accessibleNamespaces: envSet("ACCESSIBLE_NAMESPACES", ""),
+		cacheDir:             os.Getenv("CACHE_DIR"),
+	}
+	c.loadCache()
+	return c
 }
 
 // GetNamespaces returns a list of all namespaces
-func (c *Client) GetNamespaces() ([]string, error) {
+func (c *Client) GetNamespaces(ctx context.Context) ([]string, error) {
 	if c.clientset == nil {
-		return nil, fmt.Errorf("no kubernetes client available")
+		return nil, ErrNoClient
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
 	defer cancel()
 
 	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		return nil, err
+		return nil, wrapAPIError(err)
 	}
 
 	result := make([]string, len(namespaces.Items))
@@ -137,28 +357,51 @@ func (c *Client) GetNamespaces() ([]string, error) {
 // GetAPIResources returns all available API resources with caching
 func (c *Client) GetAPIResources() ([]ResourceInfo, error) {
 	if c.discoveryClient == nil {
-		return nil, fmt.Errorf("no discovery client available")
+		return nil, fmt.Errorf("%w: no discovery client", ErrNoClient)
 	}
 
 	// Check cache first
-	if len(c.resourcesCache) > 0 && time.Since(c.resourcesCacheTime) < c.cacheTTL {
-		log.Printf("[DEBUG] Using cached API resources (%d resources, cached %v ago)",
-			len(c.resourcesCache), time.Since(c.resourcesCacheTime).Round(time.Second))
-		return c.resourcesCache, nil
+	c.resourcesMu.RLock()
+	cachedResources, cacheTime := c.resourcesCache, c.resourcesCacheTime
+	c.resourcesMu.RUnlock()
+	if len(cachedResources) > 0 && time.Since(cacheTime) < c.cacheTTL {
+		slog.Debug("Using cached API resources",
+			"count", len(cachedResources), "age", time.Since(cacheTime).Round(time.Second))
+		metrics.CacheHit(metrics.CacheAPIResources)
+		return cachedResources, nil
 	}
+	metrics.CacheMiss(metrics.CacheAPIResources)
 
-	log.Printf("[DEBUG] Cache miss or expired, discovering API resources...")
+	slog.Debug("Cache miss or expired, discovering API resources")
 	start := time.Now()
 
-	// Use ServerPreferredNamespacedResources
-	resourceLists, err := c.discoveryClient.ServerPreferredNamespacedResources()
+	// Use ServerPreferredResources so cluster-scoped resources are discovered too; ALL_VERSIONS
+	// discovers every served version instead
+	var resourceLists []*metav1.APIResourceList
+	var preferredVersions map[string]string
+	var err error
+	if c.allVersions {
+		resourceLists, preferredVersions, err = c.discoverAllVersions()
+	} else {
+		resourceLists, err = c.discoveryClient.ServerPreferredResources()
+		if c.includeSubresources && len(resourceLists) > 0 {
+			resourceLists = append(resourceLists, c.discoverSubresources(resourceLists)...)
+		}
+	}
+	warnings := []DiscoveryWarning{}
 	if err != nil {
 		// Handle partial discovery errors - many clusters have some APIs that fail
 		if discovery.IsGroupDiscoveryFailedError(err) {
 			groupErr := err.(*discovery.ErrGroupDiscoveryFailed)
-			log.Printf("Warning: Some API groups failed discovery: %v", len(groupErr.Groups))
+			warnings = discoveryWarnings(groupErr)
+			slog.Warn("Some API groups failed discovery", "groups", len(warnings), "warnings", warnings)
 			// Continue with whatever we successfully discovered
 			if len(resourceLists) == 0 {
+				// The fallback is not cached, but its warnings are reported all the same
+				c.resourcesMu.Lock()
+				c.discoveryWarnings = warnings
+				c.resourcesMu.Unlock()
+
 				// Return a minimal set of core resources that should always be available
 				coreResources := []ResourceInfo{
 					{Name: "pods", FullName: "pods", DisplayName: "pods", Kind: "Pod", ShortName: "po", APIGroup: "", APIVersion: "v1", Namespaced: true},
@@ -167,11 +410,11 @@ func (c *Client) GetAPIResources() ([]ResourceInfo, error) {
 					{Name: "secrets", FullName: "secrets", DisplayName: "secrets", Kind: "Secret", ShortName: "", APIGroup: "", APIVersion: "v1", Namespaced: true},
 					{Name: "deployments", FullName: "deployments.apps", DisplayName: "deployments (apps)", Kind: "Deployment", ShortName: "deploy", APIGroup: "apps", APIVersion: "v1", Namespaced: true},
 				}
-				log.Printf("Using core resources fallback: %d resources", len(coreResources))
+				slog.Info("Using core resources fallback", "count", len(coreResources))
 				return coreResources, nil
 			}
 		} else {
-			return nil, fmt.Errorf("failed to discover API resources: %v", err)
+			return nil, fmt.Errorf("failed to discover API resources: %w", wrapAPIError(err))
 		}
 	}
 
@@ -183,8 +426,9 @@ func (c *Client) GetAPIResources() ([]ResourceInfo, error) {
 		}
 
 		for _, resource := range resourceList.APIResources {
-			// Skip subresources
-			if strings.Contains(resource.Name, "/") {
+			// Skip subresources unless INCLUDE_SUBRESOURCES surfaces them
+			subresource := strings.Contains(resource.Name, "/")
+			if subresource && !c.includeSubresources {
 				continue
 			}
 
@@ -210,134 +454,374 @@ func (c *Client) GetAPIResources() ([]ResourceInfo, error) {
 				APIGroup:    gv.Group,
 				APIVersion:  gv.Version,
 				Namespaced:  resource.Namespaced,
+				Subresource: subresource,
 			})
 		}
 	}
 
+	if c.allVersions {
+		resources = mergeVersions(resources, preferredVersions)
+	}
+
 	// Update cache
+	c.resourcesMu.Lock()
 	c.resourcesCache = resources
 	c.resourcesCacheTime = time.Now()
-	log.Printf("[DEBUG] API resource discovery completed in %v, cached %d resources",
-		time.Since(start), len(resources))
+	c.discoveryWarnings = warnings
+	c.resourcesMu.Unlock()
+	metrics.DiscoveredResourceTypes.Set(float64(len(resources)))
+	c.persistCache()
+	slog.Debug("API resource discovery completed",
+		"duration", time.Since(start), "count", len(resources))
 
 	return resources, nil
 }
 
-// GetResourcesInNamespace returns resources with object counts for a specific namespace with caching
-func (c *Client) GetResourcesInNamespace(namespace string) ([]ResourceInfo, error) {
-	// Check namespace cache first
-	if cachedResources, exists := c.namespaceCaches[namespace]; exists {
-		if cacheTime, timeExists := c.namespaceCacheTimes[namespace]; timeExists {
-			if time.Since(cacheTime) < c.cacheTTL {
-				log.Printf("[DEBUG] Using cached namespace data for '%s' (%d resources, cached %v ago)",
-					namespace, len(cachedResources), time.Since(cacheTime).Round(time.Second))
-				return cachedResources, nil
-			} else {
-				log.Printf("[DEBUG] Cache expired for namespace '%s', refreshing...", namespace)
+// GetClusterScopedResources returns cluster-scoped resources (Nodes, PersistentVolumes, ClusterRoles, ...)
+// with object counts. Counts are cached like a namespace, under the ClusterScope key.
+func (c *Client) GetClusterScopedResources(ctx context.Context) ([]ResourceInfo, error) {
+	return c.GetResourcesInNamespace(ctx, ClusterScope)
+}
+
+// GetResourceCountsAllNamespaces returns every resource type with its object count summed across all
+// namespaces; cluster-scoped resources are counted as usual. Namespaced resources are counted with a
+// single all-namespaces list each, and the result is cached under AllNamespaces.
+func (c *Client) GetResourceCountsAllNamespaces(ctx context.Context) ([]ResourceInfo, error) {
+	if cachedResources, cacheTime, exists := c.cachedNamespaceResources(AllNamespaces); exists && time.Since(cacheTime) < c.cacheTTL {
+		slog.DebugContext(ctx, "Using cached all-namespaces counts",
+			"count", len(cachedResources), "age", time.Since(cacheTime).Round(time.Second))
+		metrics.CacheHit(metrics.CacheNamespace)
+		return cachedResources, nil
+	}
+	metrics.CacheMiss(metrics.CacheNamespace)
+
+	resources, _, err := c.countOnce(ctx, AllNamespaces, func() ([]ResourceInfo, error) {
+		apiResources, err := c.GetAPIResources()
+		if err != nil {
+			return nil, err
+		}
+
+		var countable []ResourceInfo
+		for _, resource := range apiResources {
+			if c.countable(resource) {
+				countable = append(countable, resource)
 			}
 		}
+
+		slog.InfoContext(ctx, "Counting objects across all namespaces", "resources", len(countable))
+		if err := c.countResources(ctx, metav1.NamespaceAll, countable, nil); err != nil {
+			return nil, err
+		}
+
+		c.storeNamespaceResources(AllNamespaces, countable)
+		return countable, nil
+	})
+	return resources, err
+}
+
+// GetResourcesInNamespace returns resources with object counts for a specific namespace with caching.
+// Passing ClusterScope as the namespace returns cluster-scoped resources instead.
+func (c *Client) GetResourcesInNamespace(ctx context.Context, namespace string) ([]ResourceInfo, error) {
+	// Check namespace cache first
+	if cachedResources, cacheTime, exists := c.cachedNamespaceResources(namespace); exists {
+		if time.Since(cacheTime) < c.cacheTTL || c.isWatched(namespace) {
+			slog.DebugContext(ctx, "Using cached namespace data", "namespace", namespace,
+				"count", len(cachedResources), "age", time.Since(cacheTime).Round(time.Second))
+			metrics.CacheHit(metrics.CacheNamespace)
+			return cachedResources, nil
+		} else {
+			slog.DebugContext(ctx, "Cache expired, refreshing", "namespace", namespace)
+		}
 	} else {
-		log.Printf("[DEBUG] No cache found for namespace '%s', counting objects...", namespace)
+		slog.DebugContext(ctx, "No cache found, counting objects", "namespace", namespace)
 	}
+
+	metrics.CacheMiss(metrics.CacheNamespace)
+	resources, _, err := c.countOnce(ctx, namespace, func() ([]ResourceInfo, error) {
+		return c.countNamespace(ctx, namespace)
+	})
+	return resources, err
+}
+
+// countNamespace discovers and counts the resources of a namespace and caches the result
+func (c *Client) countNamespace(ctx context.Context, namespace string) ([]ResourceInfo, error) {
+	c.markCounting(namespace)
 	resources, err := c.GetAPIResources()
 	if err != nil {
+		c.markCountDone(namespace, 0, err)
 		return nil, err
 	}
 
 	// Filter to only namespaced resources and skip problematic ones
 	var namespacedResources []ResourceInfo
-	skipResources := map[string]bool{
-		"bindings":                  true,
-		"localsubjectaccessreviews": true,
-		"selfsubjectaccessreviews":  true,
-		"selfsubjectrulesreviews":   true,
-		"uploadtokenrequests":       true,
-		"tokenrequests":             true,
-		"subjectaccessreviews":      true,
-	}
-
 	for _, resource := range resources {
-		if resource.Namespaced && !skipResources[resource.Name] {
+		if inScope(namespace, resource) && c.countable(resource) {
 			namespacedResources = append(namespacedResources, resource)
 		}
 	}
 
-	log.Printf("Counting objects for %d namespaced resources in namespace '%s'", len(namespacedResources), namespace)
+	slog.InfoContext(ctx, "Counting objects", "namespace", namespace, "resources", len(namespacedResources))
 
 	// Count objects with progress reporting
-	processed := 0
-	debugMode := os.Getenv("DEBUG") == "true" || os.Getenv("DEBUG") == "1"
+	if err := c.countResources(ctx, namespace, namespacedResources, nil); err != nil {
+		c.markCountDone(namespace, 0, err)
+		return nil, err
+	}
 
-	for i := range namespacedResources {
-		processed++
-		resource := &namespacedResources[i]
+	slog.InfoContext(ctx, "Counting completed", "namespace", namespace, "resources", len(namespacedResources))
 
-		if debugMode && processed <= 10 {
-			// Show first 10 resources in detail when debug is enabled
-			log.Printf("[DEBUG] Counting objects for: %s (%s/%s)",
-				resource.DisplayName, resource.APIGroup, resource.APIVersion)
-		}
+	// Cache the results
+	c.storeNamespaceResources(namespace, namespacedResources)
+	slog.DebugContext(ctx, "Cached resources", "namespace", namespace, "count", len(namespacedResources))
+	c.startNamespaceWatches(namespace, namespacedResources)
+	c.markCountDone(namespace, len(namespacedResources), nil)
 
-		count, err := c.countResourceObjects(namespace, *resource)
-		if err != nil {
-			// Skip common permission errors without logging
-			if strings.Contains(err.Error(), "does not allow this method") ||
-				strings.Contains(err.Error(), "forbidden") {
-				resource.Count = 0
-				if debugMode && processed <= 10 {
-					log.Printf("[DEBUG]   → Permission denied (expected)")
+	return namespacedResources, nil
+}
+
+// countResources counts objects for each resource concurrently, bounded by countConcurrency.
+// Counts are written in place so the order of resources is preserved; resources that cannot
+// be counted get Count 0. Progress is reported in completion order. When ctx is cancelled no
+// further resources are counted and ctx's error is returned, as the counts are incomplete.
+func (c *Client) countResources(ctx context.Context, namespace string, resources []ResourceInfo, debugCallback func(string)) error {
+	debugMode := debugEnabled()
+	total := len(resources)
+
+	workers := c.countConcurrency
+	if workers > total {
+		workers = total
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	// progressMu serializes logging and callbacks so progress messages stay coherent
+	var progressMu sync.Mutex
+	started, processed := 0, 0
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				resource := &resources[i]
+
+				progressMu.Lock()
+				started++
+				if debugCallback != nil && debugMode && started <= 15 {
+					debugCallback(fmt.Sprintf("🔍 Counting objects for: %s (%s/%s)",
+						resource.DisplayName, resource.APIGroup, resource.APIVersion))
 				}
-			} else {
-				log.Printf("Warning: Failed to count objects for resource %s: %v", resource.Name, err)
-				resource.Count = 0
-			}
-		} else {
-			resource.Count = count
-			if debugMode && (count > 0 || processed <= 10) {
-				log.Printf("[DEBUG]   → %d objects found", count)
+				progressMu.Unlock()
+
+				count, resourceVersion, err := c.countResourceObjects(ctx, namespace, *resource)
+
+				progressMu.Lock()
+				processed++
+				resource.CountStatus = countStatusOf(err)
+				if err != nil {
+					resource.Count = 0
+					resource.Error = err.Error()
+					// Skip common permission errors without logging
+					if isPermissionError(err) {
+						if processed <= 10 {
+							slog.DebugContext(ctx, "Permission denied (expected)", "namespace", namespace, "resource", resource.FullName)
+						}
+						if debugCallback != nil && debugMode && processed <= 15 {
+							debugCallback(fmt.Sprintf("  ⚠️ %s: Permission denied (expected)", resource.DisplayName))
+						}
+					} else {
+						slog.WarnContext(ctx, "Failed to count objects", "namespace", namespace, "resource", resource.FullName, "error", err)
+					}
+				} else {
+					resource.Count = count
+					resource.Error = ""
+					resource.resourceVersion = resourceVersion
+					if count > 0 || processed <= 10 {
+						slog.DebugContext(ctx, "Counted objects", "namespace", namespace, "resource", resource.FullName, "count", count)
+					}
+					if debugCallback != nil && count > 0 {
+						debugCallback(fmt.Sprintf("  ✅ %s: %d objects found", resource.DisplayName, count))
+					}
+				}
+
+				// Send progress updates via callback
+				if debugCallback != nil && (processed%10 == 0 || processed == total) {
+					progress := int((float64(processed) / float64(total)) * 100)
+					debugCallback(fmt.Sprintf("📈 Progress: %d%% (%d/%d resources)", progress, processed, total))
+				}
+
+				// Log progress every 20 resources to reduce noise
+				if processed%20 == 0 {
+					slog.InfoContext(ctx, "Counting progress", "namespace", namespace, "processed", processed, "total", total)
+				}
+				progressMu.Unlock()
 			}
+		}()
+	}
+
+feed:
+	for i := range resources {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
 		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// debugEnabled reports whether verbose debug logging is enabled via the DEBUG env var
+func debugEnabled() bool {
+	debugEnv := strings.ToLower(os.Getenv("DEBUG"))
+	return debugEnv == "true" || debugEnv == "1"
+}
 
-		// Log progress every 20 resources to reduce noise
-		if processed%20 == 0 {
-			log.Printf("Processed %d/%d resources", processed, len(namespacedResources))
+// countOnce runs count for a namespace unless a count of that namespace is already in flight, in
+// which case it waits for and shares that count's result. shared reports whether the result was
+// handed to more than one caller. A shared count runs with the context of the caller that started
+// it; when that caller goes away the others start a count of their own rather than failing.
+func (c *Client) countOnce(ctx context.Context, namespace string, count func() ([]ResourceInfo, error)) ([]ResourceInfo, bool, error) {
+	for {
+		result, err, shared := c.countGroup.Do(namespace, func() (interface{}, error) {
+			return count()
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) && ctx.Err() == nil {
+				continue
+			}
+			return nil, shared, err
 		}
+		return result.([]ResourceInfo), shared, nil
 	}
+}
 
-	log.Printf("Completed: Found %d namespaced resources in '%s'", len(namespacedResources), namespace)
+// cachedNamespaceResources returns the cached resources for a namespace and when they were cached
+func (c *Client) cachedNamespaceResources(namespace string) ([]ResourceInfo, time.Time, bool) {
+	c.namespaceMu.RLock()
+	defer c.namespaceMu.RUnlock()
 
-	// Cache the results
-	c.namespaceCaches[namespace] = namespacedResources
-	c.namespaceCacheTimes[namespace] = time.Now()
-	log.Printf("[DEBUG] Cached %d resources for namespace '%s'", len(namespacedResources), namespace)
+	resources, exists := c.namespaceCaches[namespace]
+	if !exists {
+		return nil, time.Time{}, false
+	}
+	return resources, c.namespaceCacheTimes[namespace], true
+}
 
-	return namespacedResources, nil
+// NamespaceCacheTime returns when the resources of a namespace were last counted, or the zero time
+// when they are not cached. Watch updates keep this time, only a recount moves it.
+func (c *Client) NamespaceCacheTime(namespace string) time.Time {
+	_, cacheTime, _ := c.cachedNamespaceResources(namespace)
+	return cacheTime
+}
+
+// storeNamespaceResources caches the counted resources for a namespace
+func (c *Client) storeNamespaceResources(namespace string, resources []ResourceInfo) {
+	now := time.Now()
+	c.namespaceMu.Lock()
+	c.namespaceCaches[namespace] = resources
+	c.namespaceCacheTimes[namespace] = now
+	c.namespaceMu.Unlock()
+
+	c.recordCounts(namespace, resources, now)
+
+	c.persistCache()
+}
+
+// ClearCache drops the cached API resources, every namespace cache and the cached objects,
+// returning the number of cache entries that were cleared
+func (c *Client) ClearCache() int {
+	cleared := c.objectCache.clear("")
+
+	c.resourcesMu.Lock()
+	if c.resourcesCache != nil {
+		cleared++
+	}
+	c.resourcesCache = nil
+	c.resourcesCacheTime = time.Time{}
+	c.apiGroupsCache = nil
+	c.apiGroupsCacheTime = time.Time{}
+	c.discoveryWarnings = nil
+	c.resourcesMu.Unlock()
+
+	c.schemaMu.Lock()
+	c.schemaCache = nil
+	c.schemaMu.Unlock()
+
+	c.namespaceMu.Lock()
+	cleared += len(c.namespaceCaches)
+	c.namespaceCaches = make(map[string][]ResourceInfo)
+	c.namespaceCacheTimes = make(map[string]time.Time)
+	for namespace := range c.namespaceWatches {
+		c.unwatchLocked(namespace)
+	}
+	c.namespaceMu.Unlock()
+
+	c.persistCache()
+	return cleared
+}
+
+// ClearNamespaceCache drops the cached resources and objects of one namespace, returning the number
+// of cache entries that were cleared
+func (c *Client) ClearNamespaceCache(namespace string) int {
+	cleared := c.objectCache.clear(namespace + "/")
+
+	c.namespaceMu.Lock()
+	if _, exists := c.namespaceCaches[namespace]; !exists {
+		c.namespaceMu.Unlock()
+		return cleared
+	}
+	delete(c.namespaceCaches, namespace)
+	delete(c.namespaceCacheTimes, namespace)
+	c.unwatchLocked(namespace)
+	c.namespaceMu.Unlock()
+
+	c.persistCache()
+	return cleared + 1
 }
 
 // GetResourcesInNamespaceWithCallback returns resources with real-time debug callbacks
-func (c *Client) GetResourcesInNamespaceWithCallback(namespace string, debugCallback func(string)) ([]ResourceInfo, error) {
+func (c *Client) GetResourcesInNamespaceWithCallback(ctx context.Context, namespace string, debugCallback func(string)) ([]ResourceInfo, error) {
 	// Check namespace cache first
-	if cachedResources, exists := c.namespaceCaches[namespace]; exists {
-		if cacheTime, timeExists := c.namespaceCacheTimes[namespace]; timeExists {
-			if time.Since(cacheTime) < c.cacheTTL {
-				if debugCallback != nil {
-					debugCallback(fmt.Sprintf("⚡ Using cached data for '%s' (%d resources, cached %v ago)",
-						namespace, len(cachedResources), time.Since(cacheTime).Round(time.Second)))
-				}
-				return cachedResources, nil
+	if cachedResources, cacheTime, exists := c.cachedNamespaceResources(namespace); exists {
+		if time.Since(cacheTime) < c.cacheTTL || c.isWatched(namespace) {
+			if debugCallback != nil {
+				debugCallback(fmt.Sprintf("⚡ Using cached data for '%s' (%d resources, cached %v ago)",
+					namespace, len(cachedResources), time.Since(cacheTime).Round(time.Second)))
 			}
+			metrics.CacheHit(metrics.CacheNamespace)
+			return cachedResources, nil
 		}
 	}
+	metrics.CacheMiss(metrics.CacheNamespace)
 
 	if debugCallback != nil {
 		debugCallback(fmt.Sprintf("🔍 No cache found for namespace '%s', discovering resources...", namespace))
 	}
 
-	log.Printf("[DEBUG] No cache found for namespace '%s', counting objects...", namespace)
+	slog.DebugContext(ctx, "No cache found, counting objects", "namespace", namespace)
 
+	resources, shared, err := c.countOnce(ctx, namespace, func() ([]ResourceInfo, error) {
+		return c.countNamespaceWithCallback(ctx, namespace, debugCallback)
+	})
+	if shared && err == nil && debugCallback != nil {
+		debugCallback(fmt.Sprintf("♻️ Shared the results of a concurrent count of '%s' (%d resources)", namespace, len(resources)))
+	}
+	return resources, err
+}
+
+// countNamespaceWithCallback is countNamespace with real-time debug callbacks
+func (c *Client) countNamespaceWithCallback(ctx context.Context, namespace string, debugCallback func(string)) ([]ResourceInfo, error) {
 	// Get API resources (cached)
+	c.markCounting(namespace)
 	resources, err := c.GetAPIResources()
 	if err != nil {
+		c.markCountDone(namespace, 0, err)
 		return nil, err
 	}
 
@@ -348,7 +832,7 @@ func (c *Client) GetResourcesInNamespaceWithCallback(namespace string, debugCall
 	// Filter namespaced resources
 	var namespacedResources []ResourceInfo
 	for _, resource := range resources {
-		if resource.Namespaced {
+		if inScope(namespace, resource) && c.countable(resource) {
 			namespacedResources = append(namespacedResources, resource)
 		}
 	}
@@ -356,98 +840,109 @@ func (c *Client) GetResourcesInNamespaceWithCallback(namespace string, debugCall
 	if debugCallback != nil {
 		debugCallback(fmt.Sprintf("🔢 Counting objects for %d namespaced resources in namespace '%s'", len(namespacedResources), namespace))
 	}
-	log.Printf("Counting objects for %d namespaced resources in namespace '%s'", len(namespacedResources), namespace)
+	slog.InfoContext(ctx, "Counting objects", "namespace", namespace, "resources", len(namespacedResources))
 
 	// Count objects for each resource with real-time updates
-	debugMode := strings.ToLower(os.Getenv("DEBUG")) == "true"
-	processed := 0
-
-	for i := range namespacedResources {
-		resource := &namespacedResources[i]
-		processed++
-
-		if debugCallback != nil && debugMode && processed <= 15 {
-			debugCallback(fmt.Sprintf("🔍 Counting objects for: %s (%s/%s)",
-				resource.DisplayName, resource.APIGroup, resource.APIVersion))
-		}
-
-		if debugMode && processed <= 10 {
-			log.Printf("[DEBUG] Counting objects for: %s (%s/%s)",
-				resource.DisplayName, resource.APIGroup, resource.APIVersion)
-		}
-
-		count, err := c.countResourceObjects(namespace, *resource)
-		if err != nil {
-			if strings.Contains(err.Error(), "does not allow this method") ||
-				strings.Contains(err.Error(), "forbidden") {
-				resource.Count = 0
-				if debugCallback != nil && debugMode && processed <= 15 {
-					debugCallback(fmt.Sprintf("  ⚠️ Permission denied (expected)"))
-				}
-			} else {
-				log.Printf("Warning: Failed to count objects for resource %s: %v", resource.Name, err)
-				resource.Count = 0
-			}
-		} else {
-			resource.Count = count
-			if debugCallback != nil && count > 0 {
-				debugCallback(fmt.Sprintf("  ✅ %s: %d objects found", resource.DisplayName, count))
-			}
-			if debugMode && (count > 0 || processed <= 10) {
-				log.Printf("[DEBUG]   → %d objects found", count)
-			}
-		}
-
-		// Send progress updates via callback
-		if debugCallback != nil && (processed%10 == 0 || processed == len(namespacedResources)) {
-			progress := int((float64(processed) / float64(len(namespacedResources))) * 100)
-			debugCallback(fmt.Sprintf("📈 Progress: %d%% (%d/%d resources)", progress, processed, len(namespacedResources)))
-		}
-
-		// Log progress every 20 resources to reduce noise
-		if processed%20 == 0 {
-			log.Printf("Processed %d/%d resources", processed, len(namespacedResources))
-		}
+	if err := c.countResources(ctx, namespace, namespacedResources, debugCallback); err != nil {
+		c.markCountDone(namespace, 0, err)
+		return nil, err
 	}
 
 	if debugCallback != nil {
 		debugCallback(fmt.Sprintf("✨ Resource discovery complete! Found %d namespaced resources", len(namespacedResources)))
 	}
 
-	log.Printf("Completed: Found %d namespaced resources in '%s'", len(namespacedResources), namespace)
+	slog.InfoContext(ctx, "Counting completed", "namespace", namespace, "resources", len(namespacedResources))
 
 	// Cache the results
-	c.namespaceCaches[namespace] = namespacedResources
-	c.namespaceCacheTimes[namespace] = time.Now()
-	log.Printf("[DEBUG] Cached %d resources for namespace '%s'", len(namespacedResources), namespace)
+	c.storeNamespaceResources(namespace, namespacedResources)
+	slog.DebugContext(ctx, "Cached resources", "namespace", namespace, "count", len(namespacedResources))
+	c.startNamespaceWatches(namespace, namespacedResources)
+	c.markCountDone(namespace, len(namespacedResources), nil)
 
 	return namespacedResources, nil
 }
 
-// GetResourceObjects returns all objects of a specific resource type in a namespace
-func (c *Client) GetResourceObjects(namespace, resourceIdentifier string) ([]ObjectInfo, error) {
-	if c.dynamicClient == nil {
-		return nil, fmt.Errorf("no dynamic client available")
-	}
+// ListOptions are the optional parameters of an object listing
+type ListOptions struct {
+	// ResourceVersion pins the listing to a snapshot of the cluster state; empty means the latest state
+	ResourceVersion string
+
+	// FieldSelector filters objects server-side, e.g. "status.phase=Running". Every resource supports
+	// metadata.name and metadata.namespace; other fields are resource specific. Commonly supported:
+	//   pods:        status.phase, spec.nodeName, spec.serviceAccountName, spec.restartPolicy, status.podIP
+	//   events:      involvedObject.kind, involvedObject.name, involvedObject.uid, reason, type
+	//   secrets:     type
+	//   jobs.batch:  status.successful
+	//   replicasets: status.replicas
+	// Custom resources only support the metadata fields plus any selectableFields of their CRD.
+	FieldSelector string
+
+	// LabelSelector filters objects by label, with equality ("tier!=frontend") and set-based
+	// ("env in (prod,staging)") requirements
+	LabelSelector string
+
+	// Limit returns a single page of at most Limit objects, and of no more than MAX_OBJECTS; 0 lists
+	// all objects. Continue is the
+	// token from the previous page's ObjectList.
+	Limit    int64
+	Continue string
+}
 
-	// Find the resource info
-	resources, err := c.GetAPIResources()
+// ObjectList is the result of an object listing
+type ObjectList struct {
+	Items []ObjectInfo
+	// ResourceVersion is the snapshot the items were read at. It can be passed back in ListOptions
+	// to read other resources at the same point in time.
+	ResourceVersion string
+
+	// Continue fetches the next page when the listing was limited; empty on the last page.
+	// RemainingItemCount estimates the objects after this page and is nil when the server cannot tell.
+	Continue           string
+	RemainingItemCount *int64
+}
+
+// GetResourceObjects returns all objects of a specific resource type in a namespace
+func (c *Client) GetResourceObjects(ctx context.Context, namespace, resourceIdentifier string) ([]ObjectInfo, error) {
+	list, err := c.GetResourceObjectList(ctx, namespace, resourceIdentifier, ListOptions{})
 	if err != nil {
 		return nil, err
 	}
+	return list.Items, nil
+}
 
-	var targetResource *ResourceInfo
-	for _, resource := range resources {
-		// Try to match by FullName first (exact match including API group)
-		// Then fall back to Name match for backward compatibility
-		if (resource.FullName == resourceIdentifier || resource.Name == resourceIdentifier) && resource.Namespaced {
-			targetResource = &resource
-			break
+// GetResourceObjectsAllNamespaces returns the objects of a namespaced resource type across all
+// namespaces with a single cluster-wide list
+func (c *Client) GetResourceObjectsAllNamespaces(ctx context.Context, resourceIdentifier string) ([]ObjectInfo, error) {
+	return c.GetResourceObjects(ctx, metav1.NamespaceAll, resourceIdentifier)
+}
+
+// GetResourceObjectList returns all objects of a specific resource type in a namespace along with
+// the resourceVersion they were read at. When the requested resourceVersion has been compacted
+// away the listing is retried against the latest state.
+func (c *Client) GetResourceObjectList(ctx context.Context, namespace, resourceIdentifier string, options ListOptions) (*ObjectList, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	if options.FieldSelector != "" {
+		if _, err := fields.ParseSelector(options.FieldSelector); err != nil {
+			return nil, fmt.Errorf("%w: fieldSelector: %w", ErrInvalidArgument, err)
+		}
+	}
+	if options.LabelSelector != "" {
+		if _, err := labels.Parse(options.LabelSelector); err != nil {
+			return nil, fmt.Errorf("%w: labelSelector: %w", ErrInvalidArgument, err)
 		}
 	}
+	if options.Continue != "" && options.Limit <= 0 {
+		return nil, fmt.Errorf("%w: continue requires a limit", ErrInvalidArgument)
+	}
 
-	if targetResource == nil {
-		return nil, fmt.Errorf("resource %s not found or not namespaced", resourceIdentifier)
+	// Find the resource info
+	targetResource, err := c.findResource(resourceIdentifier, true)
+	if err != nil {
+		return nil, err
 	}
 
 	gvr := schema.GroupVersionResource{
@@ -456,86 +951,162 @@ func (c *Client) GetResourceObjects(namespace, resourceIdentifier string) ([]Obj
 		Resource: targetResource.Name,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
 	defer cancel()
 
-	list, err := c.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, err
+	resourceClient := c.dynamicClient.Resource(gvr).Namespace(namespace)
+	listOptions := metav1.ListOptions{
+		ResourceVersion: options.ResourceVersion,
+		FieldSelector:   options.FieldSelector,
+		LabelSelector:   options.LabelSelector,
+		Limit:           options.Limit,
+		Continue:        options.Continue,
+	}
+	if c.maxObjects > 0 && listOptions.Limit > int64(c.maxObjects) {
+		// A page may not hold more than an unpaginated listing; the continue token fetches the rest
+		listOptions.Limit = int64(c.maxObjects)
+	}
+	if listOptions.Continue != "" {
+		// The continue token carries the snapshot of the first page
+		listOptions.ResourceVersion = ""
+	}
+	watchCacheRead := false
+	if listOptions.ResourceVersion == "" && options.Limit <= 0 && c.useWatchCache {
+		listOptions.ResourceVersion = c.watchCacheListOptions().ResourceVersion
+		watchCacheRead = true
 	}
 
-	objects := make([]ObjectInfo, len(list.Items))
-	for i, item := range list.Items {
-		objects[i] = ObjectInfo{
-			Name:              item.GetName(),
-			Namespace:         item.GetNamespace(),
-			Kind:              item.GetKind(),
-			APIVersion:        item.GetAPIVersion(),
-			CreationTimestamp: item.GetCreationTimestamp().Time,
-			Labels:            item.GetLabels(),
-			Annotations:       item.GetAnnotations(),
+	fetch := func(ctx context.Context, resourceClient dynamic.ResourceInterface, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+		return c.listAllPages(ctx, resourceClient, opts, c.maxObjects)
+	}
+	if options.Limit > 0 {
+		fetch = func(ctx context.Context, resourceClient dynamic.ResourceInterface, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+			return resourceClient.List(ctx, opts)
 		}
+	}
 
-		// Extract status and spec if available
-		if status, found := item.Object["status"].(map[string]interface{}); found {
-			objects[i].Status = status
-		}
-		if spec, found := item.Object["spec"].(map[string]interface{}); found {
-			objects[i].Spec = spec
+	list, err := fetch(ctx, resourceClient, listOptions)
+	if err != nil && watchCacheRead && watchCacheRejected(err) {
+		slog.DebugContext(ctx, "Watch cache read rejected, listing from etcd", "resource", targetResource.FullName, "error", err)
+		listOptions.ResourceVersion = ""
+		list, err = fetch(ctx, resourceClient, listOptions)
+	}
+	if err != nil && listOptions.ResourceVersion != "" && isResourceExpired(err) {
+		slog.WarnContext(ctx, "resourceVersion is too old, listing the latest state instead",
+			"resource", targetResource.FullName, "resourceVersion", options.ResourceVersion)
+		listOptions.ResourceVersion = ""
+		list, err = fetch(ctx, resourceClient, listOptions)
+	}
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+	if options.Limit <= 0 && c.maxObjects > 0 && len(list.Items) > c.maxObjects {
+		// listAllPages stopped at MAX_OBJECTS; count the rest from metadata for the error message
+		count := len(list.Items)
+		if list.GetContinue() != "" {
+			rest, _, err := c.countAllPages(ctx, c.metadataResource(namespace, *targetResource), metav1.ListOptions{
+				FieldSelector: options.FieldSelector,
+				LabelSelector: options.LabelSelector,
+				Continue:      list.GetContinue(),
+			})
+			if err == nil {
+				count += rest
+			}
 		}
+		return nil, &TooManyObjectsError{Resource: targetResource.FullName, Count: count, Limit: c.maxObjects}
+	}
+
+	objects := make([]ObjectInfo, len(list.Items))
+	for i := range list.Items {
+		objects[i] = toObjectInfo(&list.Items[i])
 	}
 
-	return objects, nil
+	return &ObjectList{
+		Items:              objects,
+		ResourceVersion:    list.GetResourceVersion(),
+		Continue:           list.GetContinue(),
+		RemainingItemCount: list.GetRemainingItemCount(),
+	}, nil
+}
+
+// GetResourceObject returns a specific object, with Secret values redacted
+func (c *Client) GetResourceObject(ctx context.Context, namespace, resourceIdentifier, objectName string) (*ObjectInfo, error) {
+	return c.GetResourceObjectWithOptions(ctx, namespace, resourceIdentifier, objectName, ObjectOptions{})
 }
 
-// GetResourceObject returns a specific object
-func (c *Client) GetResourceObject(namespace, resourceIdentifier, objectName string) (*ObjectInfo, error) {
+// GetResourceObjectWithOptions returns a specific object, cleaned up according to options
+func (c *Client) GetResourceObjectWithOptions(ctx context.Context, namespace, resourceIdentifier, objectName string, options ObjectOptions) (*ObjectInfo, error) {
 	if c.dynamicClient == nil {
-		return nil, fmt.Errorf("no dynamic client available")
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
 	}
 
 	// Find the resource info
-	resources, err := c.GetAPIResources()
+	targetResource, err := c.findResource(resourceIdentifier, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var targetResource *ResourceInfo
-	for _, resource := range resources {
-		// Try to match by FullName first (exact match including API group)
-		// Then fall back to Name match for backward compatibility
-		if (resource.FullName == resourceIdentifier || resource.Name == resourceIdentifier) && resource.Namespaced {
-			targetResource = &resource
-			break
-		}
+	item, err := c.getObject(ctx, namespace, targetResource, objectName)
+	if err != nil {
+		return nil, err
 	}
 
-	if targetResource == nil {
-		return nil, fmt.Errorf("resource %s not found or not namespaced", resourceIdentifier)
+	cleanObject(item, options)
+	object := toObjectInfo(item)
+	if options.RevealSecrets {
+		// toObjectInfo redacts Secrets wherever they are listed; only an explicit reveal keeps them
+		object.Annotations = item.GetAnnotations()
+	}
+	return &object, nil
+}
+
+// getObject reads one object, from the object cache when it holds a fresh copy. The returned object
+// is the caller's to modify.
+func (c *Client) getObject(ctx context.Context, namespace string, resource *ResourceInfo, objectName string) (*unstructured.Unstructured, error) {
+	key := objectCacheKey(namespace, resource.FullName, resource.APIVersion, objectName)
+	if item, found := c.objectCache.get(key); found {
+		metrics.CacheHit(metrics.CacheObject)
+		return item, nil
+	}
+	if c.objectCache != nil {
+		metrics.CacheMiss(metrics.CacheObject)
 	}
 
 	gvr := schema.GroupVersionResource{
-		Group:    targetResource.APIGroup,
-		Version:  targetResource.APIVersion,
-		Resource: targetResource.Name,
+		Group:    resource.APIGroup,
+		Version:  resource.APIVersion,
+		Resource: resource.Name,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
 	defer cancel()
 
 	item, err := c.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, objectName, metav1.GetOptions{})
 	if err != nil {
-		return nil, err
+		return nil, wrapAPIError(err)
 	}
+	c.objectCache.put(key, item)
+	return item, nil
+}
 
-	object := &ObjectInfo{
-		Name:              item.GetName(),
-		Namespace:         item.GetNamespace(),
-		Kind:              item.GetKind(),
-		APIVersion:        item.GetAPIVersion(),
-		CreationTimestamp: item.GetCreationTimestamp().Time,
-		Labels:            item.GetLabels(),
-		Annotations:       item.GetAnnotations(),
+// toObjectInfo converts an unstructured object to ObjectInfo. The last-applied annotation of a
+// Secret is redacted, so listings, searches, dumps and watches never carry the Secret's values.
+func toObjectInfo(item *unstructured.Unstructured) ObjectInfo {
+	created := item.GetCreationTimestamp().Time
+	object := ObjectInfo{
+		Name:                  item.GetName(),
+		Namespace:             item.GetNamespace(),
+		Kind:                  item.GetKind(),
+		APIVersion:            item.GetAPIVersion(),
+		CreationTimestamp:     created,
+		CreationTimestampUnix: created.Unix(),
+		Age:                   formatAge(created),
+		Labels:                item.GetLabels(),
+		Annotations:           item.GetAnnotations(),
+		OwnerReferences:       toOwnerRefs(item.GetOwnerReferences()),
+	}
+	if isSecret(item) {
+		object.Annotations = redactAnnotations(object.Annotations)
 	}
 
 	// Extract status and spec if available
@@ -546,86 +1117,419 @@ func (c *Client) GetResourceObject(namespace, resourceIdentifier, objectName str
 		object.Spec = spec
 	}
 
-	return object, nil
+	return object
 }
 
-// GetRawResourceObject returns the complete raw Kubernetes object for YAML display
-func (c *Client) GetRawResourceObject(namespace, resourceIdentifier, objectName string) (map[string]interface{}, error) {
+// formatAge renders the time since t the way kubectl shows ages
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(time.Since(t))
+}
+
+// ObjectOptions controls how a single object is returned
+type ObjectOptions struct {
+	// ManagedFields keeps metadata.managedFields, which server-side apply fills with field
+	// ownership entries that usually dwarf the rest of the object
+	ManagedFields bool
+	// RevealSecrets keeps the values of a Secret's data and stringData, which are otherwise replaced
+	// by "****" and their length
+	RevealSecrets bool
+}
+
+// GetRawResourceObject returns the complete raw Kubernetes object for YAML display, without managedFields
+// and with Secret values redacted
+func (c *Client) GetRawResourceObject(ctx context.Context, namespace, resourceIdentifier, objectName string) (map[string]interface{}, error) {
+	return c.GetRawResourceObjectWithOptions(ctx, namespace, resourceIdentifier, objectName, ObjectOptions{})
+}
+
+// GetRawResourceObjectWithOptions returns the complete raw Kubernetes object, cleaned up according to options
+func (c *Client) GetRawResourceObjectWithOptions(ctx context.Context, namespace, resourceIdentifier, objectName string, options ObjectOptions) (map[string]interface{}, error) {
 	if c.dynamicClient == nil {
-		return nil, fmt.Errorf("no dynamic client available")
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
 	}
 
 	// Find the resource info
-	resources, err := c.GetAPIResources()
+	targetResource, err := c.findResource(resourceIdentifier, true)
 	if err != nil {
 		return nil, err
 	}
 
-	var targetResource *ResourceInfo
-	for _, resource := range resources {
-		// Try to match by FullName first (exact match including API group)
-		// Then fall back to Name match for backward compatibility
-		if (resource.FullName == resourceIdentifier || resource.Name == resourceIdentifier) && resource.Namespaced {
-			targetResource = &resource
-			break
-		}
+	item, err := c.getObject(ctx, namespace, targetResource, objectName)
+	if err != nil {
+		return nil, err
 	}
 
-	if targetResource == nil {
-		return nil, fmt.Errorf("resource %s not found or not namespaced", resourceIdentifier)
+	// Return the complete raw object for proper YAML conversion
+	cleanObject(item, options)
+	return item.Object, nil
+}
+
+// cleanObject removes the noise the API server adds to objects: managedFields unless options keep
+// them, and the null creationTimestamp of embedded templates. Secret values are redacted unless
+// options reveal them.
+func cleanObject(item *unstructured.Unstructured, options ObjectOptions) {
+	if !options.ManagedFields {
+		item.SetManagedFields(nil)
+	}
+	if !options.RevealSecrets {
+		redactSecret(item)
 	}
+	removeNullCreationTimestamps(item.Object)
+}
 
-	gvr := schema.GroupVersionResource{
-		Group:    targetResource.APIGroup,
-		Version:  targetResource.APIVersion,
-		Resource: targetResource.Name,
+// lastAppliedAnnotation holds the object as last applied by kubectl, Secret values included
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// redactSecret replaces the values of a core Secret's data and stringData with "****" and the
+// length of the value, so the keys stay visible without exposing credentials. The last-applied
+// annotation repeats those values and is redacted as a whole.
+func redactSecret(item *unstructured.Unstructured) {
+	if !isSecret(item) {
+		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := item.Object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, value := range values {
+			raw, _ := value.(string)
+			length := len(raw)
+			if field == "data" {
+				// data holds base64; report the length of the secret itself
+				if decoded, err := base64.StdEncoding.DecodeString(raw); err == nil {
+					length = len(decoded)
+				}
+			}
+			values[key] = fmt.Sprintf("**** (%d bytes)", length)
+		}
+	}
 
-	item, err := c.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, objectName, metav1.GetOptions{})
-	if err != nil {
-		return nil, err
+	if annotations := item.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+		item.SetAnnotations(redactAnnotations(annotations))
 	}
+}
 
-	// Return the complete raw object for proper YAML conversion
-	return item.Object, nil
+// isSecret reports whether item is a core Secret
+func isSecret(item *unstructured.Unstructured) bool {
+	return item.GetKind() == "Secret" && item.GetAPIVersion() == "v1"
 }
 
-// countResourceObjects counts the number of objects for a resource in a namespace
-func (c *Client) countResourceObjects(namespace string, resource ResourceInfo) (int, error) {
-	if c.dynamicClient == nil {
-		return 0, fmt.Errorf("no dynamic client available")
+// redactAnnotations returns the annotations of a Secret with the last-applied annotation, which
+// repeats the Secret's values, replaced by "****". The annotations passed in are left alone, as they
+// may be shared with a cached object.
+func redactAnnotations(annotations map[string]string) map[string]string {
+	if annotations[lastAppliedAnnotation] == "" {
+		return annotations
 	}
+	redacted := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		redacted[key] = value
+	}
+	redacted[lastAppliedAnnotation] = "****"
+	return redacted
+}
 
+// removeNullCreationTimestamps deletes "creationTimestamp: null" from every metadata block, such as
+// the one of a Deployment's pod template
+func removeNullCreationTimestamps(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if metadata, ok := child.(map[string]interface{}); ok && key == "metadata" {
+				if timestamp, exists := metadata["creationTimestamp"]; exists && timestamp == nil {
+					delete(metadata, "creationTimestamp")
+				}
+			}
+			removeNullCreationTimestamps(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			removeNullCreationTimestamps(child)
+		}
+	}
+}
+
+// inScope reports whether a resource belongs to the namespace view, or to the cluster view for ClusterScope
+func inScope(namespace string, resource ResourceInfo) bool {
+	if namespace == ClusterScope {
+		return !resource.Namespaced
+	}
+	return resource.Namespaced
+}
+
+// resourceClient returns the dynamic client for a resource, scoped to the namespace when the resource is namespaced
+func (c *Client) resourceClient(namespace string, resource ResourceInfo) dynamic.ResourceInterface {
 	gvr := schema.GroupVersionResource{
 		Group:    resource.APIGroup,
 		Version:  resource.APIVersion,
 		Resource: resource.Name,
 	}
+	if !resource.Namespaced {
+		return c.dynamicClient.Resource(gvr)
+	}
+	return c.dynamicClient.Resource(gvr).Namespace(namespace)
+}
+
+// defaultQPS and defaultBurst are the client-side rate limits used when K8S_QPS and K8S_BURST are not set
+const (
+	defaultQPS         = 50
+	defaultBurst int64 = 100
+)
+
+// defaultCountTimeout and defaultListTimeout bound counting one resource type and listing or
+// reading objects when COUNT_TIMEOUT and LIST_TIMEOUT are not set
+const (
+	defaultCountTimeout = 3 * time.Second
+	defaultListTimeout  = 30 * time.Second
+)
+
+// defaultCountTimeoutJitter spreads count deadlines over up to 20% past COUNT_TIMEOUT when
+// COUNT_TIMEOUT_JITTER is not set
+const defaultCountTimeoutJitter = 0.2
+
+// defaultListChunkSize is the list page size used when LIST_CHUNK_SIZE is not set
+const defaultListChunkSize int64 = 500
+
+// defaultMaxObjects caps listings without limit when MAX_OBJECTS is not set, so a single response
+// never holds tens of thousands of objects
+const defaultMaxObjects int64 = 5000
+
+// defaultWritableResources are the resources write operations may touch when WRITABLE_RESOURCES is not set
+const defaultWritableResources = "pods,jobs.batch"
+
+// defaultCountConcurrency is the number of parallel count workers when COUNT_CONCURRENCY is not set
+const defaultCountConcurrency int64 = 8
+
+// findResource looks up a discovered resource, strictly preferring an exact FullName match or,
+// with ALL_VERSIONS, a name.version.group identifier that selects a served version.
+// A bare Name is only accepted when it is unique across API groups; otherwise an
+// AmbiguousResourceError lists the FullName candidates the caller should use instead.
+// Without a Name match the identifier is tried as a short name (po, svc, deploy) and then as
+// a Kind, both case-insensitive; when those match in several groups the core group wins.
+func (c *Client) findResource(resourceIdentifier string, namespacedOnly bool) (*ResourceInfo, error) {
+	resources, err := c.GetAPIResources()
+	if err != nil {
+		return nil, err
+	}
+
+	var nameMatches, shortNameMatches, kindMatches []ResourceInfo
+	for _, resource := range resources {
+		if resource.Subresource || (namespacedOnly && !resource.Namespaced) {
+			continue
+		}
+		if resource.FullName == resourceIdentifier {
+			return &resource, nil
+		}
+		if versioned, ok := selectVersion(resource, resourceIdentifier); ok {
+			return &versioned, nil
+		}
+		switch {
+		case resource.Name == resourceIdentifier:
+			nameMatches = append(nameMatches, resource)
+		case resource.ShortName != "" && strings.EqualFold(resource.ShortName, resourceIdentifier):
+			shortNameMatches = append(shortNameMatches, resource)
+		case strings.EqualFold(resource.Kind, resourceIdentifier):
+			kindMatches = append(kindMatches, resource)
+		}
+	}
+
+	matches := nameMatches
+	if len(matches) == 0 {
+		matches = preferCoreGroup(shortNameMatches)
+	}
+	if len(matches) == 0 {
+		matches = preferCoreGroup(kindMatches)
+	}
+
+	switch len(matches) {
+	case 0:
+		if namespacedOnly {
+			return nil, fmt.Errorf("%w: %s not found or not namespaced", ErrResourceNotFound, resourceIdentifier)
+		}
+		return nil, fmt.Errorf("%w: %s not found", ErrResourceNotFound, resourceIdentifier)
+	case 1:
+		return &matches[0], nil
+	default:
+		candidates := make([]string, len(matches))
+		for i, resource := range matches {
+			candidates[i] = resource.FullName
+		}
+		sort.Strings(candidates)
+		return nil, &AmbiguousResourceError{Identifier: resourceIdentifier, Candidates: candidates}
+	}
+}
+
+// preferCoreGroup narrows matches to the core group resource when there is one, like kubectl
+// resolves "ev" to core events rather than events.k8s.io
+func preferCoreGroup(matches []ResourceInfo) []ResourceInfo {
+	for _, resource := range matches {
+		if resource.APIGroup == "" {
+			return []ResourceInfo{resource}
+		}
+	}
+	return matches
+}
+
+// GetResourceTotal counts all objects of a resource cluster-wide with a single all-namespaces list
+func (c *Client) GetResourceTotal(ctx context.Context, resourceIdentifier string) (*ResourceInfo, error) {
+	if c.metadataClient == nil {
+		return nil, fmt.Errorf("%w: no metadata client", ErrNoClient)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	resource, err := c.findResource(resourceIdentifier, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
 	defer cancel()
 
-	// Try to get count with limit=0 (just metadata)
-	list, err := c.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{
-		Limit:          0,
-		TimeoutSeconds: &[]int64{3}[0],
-	})
+	// An empty namespace lists namespaced resources across all namespaces
+	count, _, err := c.countAllPages(ctx, c.metadataResource(metav1.NamespaceAll, *resource), metav1.ListOptions{})
 	if err != nil {
-		return 0, err
+		return nil, wrapAPIError(err)
+	}
+
+	resource.Count = count
+	return resource, nil
+}
+
+// countAllPages counts list items page by page, following continue tokens until the list is exhausted.
+// It lists metadata only (PartialObjectMetadataList), so object specs and statuses never cross the
+// wire just to be counted. Every page is requested exactly once with Limit set to the chunk size,
+// which is always positive; a Limit of 0 would disable paging and return the whole list in one
+// response. It also returns the resourceVersion the count was taken at. Counts wait for a slot of
+// the client's countLimiter first.
+func (c *Client) countAllPages(ctx context.Context, resourceClient metadata.ResourceInterface, opts metav1.ListOptions) (int, string, error) {
+	if err := c.countLimiter.acquire(ctx); err != nil {
+		return 0, "", err
+	}
+	defer c.countLimiter.release()
+
+	return c.countPages(ctx, resourceClient, opts)
+}
+
+// countPages is countAllPages for callers already holding a countLimiter slot.
+//
+// A continue token can expire between pages when its snapshot is compacted away, which the API
+// server answers with 410 Gone. Counting then restarts from the first page, up to
+// maxExpiredCountRestarts times, rather than adding pages of two different snapshots. A continue
+// token passed in opts is never restarted from, as the pages before it are not counted here.
+func (c *Client) countPages(ctx context.Context, resourceClient metadata.ResourceInterface, opts metav1.ListOptions) (int, string, error) {
+	total := 0
+	resourceVersion := ""
+	opts.Limit = c.listChunkSize
+	first := opts
+	restarts := 0
+	for {
+		list, err := resourceClient.List(ctx, opts)
+		if err != nil {
+			if first.Continue == "" && opts.Continue != "" && isResourceExpired(err) && restarts < maxExpiredCountRestarts {
+				restarts++
+				slog.DebugContext(ctx, "Continue token expired, counting again from the first page",
+					"counted", total, "restart", restarts)
+				total, opts = 0, first
+				continue
+			}
+			return 0, "", err
+		}
+		if opts.Continue == "" {
+			resourceVersion = list.GetResourceVersion()
+		}
+		total += len(list.Items)
+
+		if list.GetContinue() == "" {
+			return total, resourceVersion, nil
+		}
+		opts.Continue = list.GetContinue()
+		opts.ResourceVersion = ""
 	}
+}
 
-	// Get the total count from the list metadata
-	if list.GetContinue() != "" {
-		// If there's a continue token, we need to count all items
-		fullList, err := c.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+// maxExpiredCountRestarts bounds how often countAllPages starts over after its continue token
+// expired, so a resource churning faster than it can be paged through still fails
+const maxExpiredCountRestarts = 3
+
+// listAllPages lists all items page by page so large resources are fetched in manageable chunks.
+// A resourceVersion in opts is only sent with the first page; the continue tokens of later pages
+// carry the same snapshot. The returned list has the resourceVersion of the first page. When
+// maxItems is positive, listing stops as soon as more than maxItems items were read and the
+// returned list carries the continue token of the next page, if any.
+func (c *Client) listAllPages(ctx context.Context, resourceClient dynamic.ResourceInterface, opts metav1.ListOptions, maxItems int) (*unstructured.UnstructuredList, error) {
+	result := &unstructured.UnstructuredList{}
+	opts.Limit = c.listChunkSize
+	for {
+		list, err := resourceClient.List(ctx, opts)
 		if err != nil {
-			return 0, err
+			return nil, err
+		}
+		if opts.Continue == "" {
+			result.SetResourceVersion(list.GetResourceVersion())
 		}
-		return len(fullList.Items), nil
+		result.Items = append(result.Items, list.Items...)
+
+		if maxItems > 0 && len(result.Items) > maxItems {
+			result.SetContinue(list.GetContinue())
+			return result, nil
+		}
+		if list.GetContinue() == "" {
+			return result, nil
+		}
+		opts.Continue = list.GetContinue()
+		opts.ResourceVersion = ""
+	}
+}
+
+// watchCacheListOptions returns the options of a read that may be served from the API server's
+// watch cache: resourceVersion=0 with USE_WATCH_CACHE, so the result may lag the latest state by a
+// moment, and a quorum read otherwise
+func (c *Client) watchCacheListOptions() metav1.ListOptions {
+	if c.useWatchCache {
+		return metav1.ListOptions{ResourceVersion: "0"}
+	}
+	return metav1.ListOptions{}
+}
+
+// jitteredCountTimeout returns countTimeout extended by up to countTimeoutJitter of itself. The
+// configured timeout stays the minimum, so jitter never cuts a count short.
+func (c *Client) jitteredCountTimeout() time.Duration {
+	if c.jitter == nil {
+		return c.countTimeout
+	}
+	return c.countTimeout + time.Duration(float64(c.countTimeout)*c.countTimeoutJitter*c.jitter())
+}
+
+// countResourceObjects counts the number of objects for a resource in a namespace and returns the
+// resourceVersion the count was taken at
+func (c *Client) countResourceObjects(ctx context.Context, namespace string, resource ResourceInfo) (int, string, error) {
+	if c.metadataClient == nil {
+		return 0, "", fmt.Errorf("%w: no metadata client", ErrNoClient)
+	}
+
+	resourceClient := c.metadataResource(namespace, resource)
+
+	// Wait for a slot on the caller's context, so time spent queued behind other counts does not use
+	// up the count timeout, which only starts once the count can reach the API server
+	if err := c.countLimiter.acquire(ctx); err != nil {
+		return 0, "", err
+	}
+	defer c.countLimiter.release()
+
+	ctx, cancel := context.WithTimeout(ctx, c.jitteredCountTimeout())
+	defer cancel()
+
+	count, resourceVersion, err := c.countPages(ctx, resourceClient, c.watchCacheListOptions())
+	if err != nil && c.useWatchCache && watchCacheRejected(err) {
+		slog.DebugContext(ctx, "Watch cache read rejected, counting from etcd", "namespace", namespace, "resource", resource.FullName, "error", err)
+		count, resourceVersion, err = c.countPages(ctx, resourceClient, metav1.ListOptions{})
+	}
+	if err != nil {
+		return 0, "", wrapAPIError(err)
 	}
 
-	return len(list.Items), nil
+	return count, resourceVersion, nil
 }
@@ -0,0 +1,942 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/metadata"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// testAPIResources is the discovery document served by test clients
+var testAPIResources = []*metav1.APIResourceList{
+	{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{
+			{Name: "pods", Kind: "Pod", ShortNames: []string{"po"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "services", Kind: "Service", ShortNames: []string{"svc"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "configmaps", Kind: "ConfigMap", ShortNames: []string{"cm"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "persistentvolumeclaims", Kind: "PersistentVolumeClaim", ShortNames: []string{"pvc"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "events", Kind: "Event", ShortNames: []string{"ev"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "namespaces", Kind: "Namespace", ShortNames: []string{"ns"}, Namespaced: false, Verbs: []string{"list", "get"}},
+			{Name: "nodes", Kind: "Node", ShortNames: []string{"no"}, Namespaced: false, Verbs: []string{"list", "get"}},
+		},
+	},
+	{
+		GroupVersion: "apps/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", ShortNames: []string{"deploy"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "replicasets", Kind: "ReplicaSet", ShortNames: []string{"rs"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			{Name: "statefulsets", Kind: "StatefulSet", ShortNames: []string{"sts"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			// Subresources are left out of preferred resources by the real client, but served per group version
+			{Name: "deployments/scale", Kind: "Scale", Group: "autoscaling", Version: "v1", Namespaced: true, Verbs: []string{"get", "patch", "update"}},
+			{Name: "statefulsets/scale", Kind: "Scale", Group: "autoscaling", Version: "v1", Namespaced: true, Verbs: []string{"get", "patch", "update"}},
+		},
+	},
+	{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: []string{"list", "get"}},
+		},
+	},
+	{
+		GroupVersion: "other.example.org/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Namespaced: true, Verbs: []string{"list", "get"}},
+		},
+	},
+	{
+		GroupVersion: "events.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "events", Kind: "Event", ShortNames: []string{"ev"}, Namespaced: true, Verbs: []string{"list", "get"}},
+		},
+	},
+}
+
+// testDiscovery serves testAPIResources for the preferred-resources call the fake leaves unimplemented
+type testDiscovery struct {
+	*fakediscovery.FakeDiscovery
+
+	// err, when set, fails discovery
+	err error
+
+	// openAPI serves the OpenAPI v3 documents the fake leaves unimplemented
+	openAPI openapi.Client
+}
+
+func (d *testDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	// Partial failures still return the groups that were discovered, like the real client
+	if d.err != nil && !discovery.IsGroupDiscoveryFailedError(d.err) {
+		return nil, d.err
+	}
+	// Like the real client, leave subresources out
+	lists := make([]*metav1.APIResourceList, 0, len(d.Resources))
+	for _, list := range d.Resources {
+		filtered := &metav1.APIResourceList{GroupVersion: list.GroupVersion}
+		for _, resource := range list.APIResources {
+			if !strings.Contains(resource.Name, "/") {
+				filtered.APIResources = append(filtered.APIResources, resource)
+			}
+		}
+		lists = append(lists, filtered)
+	}
+	return lists, d.err
+}
+
+func (d *testDiscovery) OpenAPIV3() openapi.Client {
+	return d.openAPI
+}
+
+func (d *testDiscovery) ServerVersion() (*version.Info, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	return d.FakeDiscovery.ServerVersion()
+}
+
+// testListKinds registers a list kind for every resource in testAPIResources with the fake dynamic client
+func testListKinds() map[schema.GroupVersionResource]string {
+	listKinds := make(map[schema.GroupVersionResource]string)
+	for _, list := range testAPIResources {
+		gv, _ := schema.ParseGroupVersion(list.GroupVersion)
+		for _, resource := range list.APIResources {
+			listKinds[gv.WithResource(resource.Name)] = resource.Kind + "List"
+		}
+	}
+	return listKinds
+}
+
+// newTestClient returns a Client backed by fake API clients seeded with objects
+func newTestClient(objects ...runtime.Object) *Client {
+	clientset := fake.NewSimpleClientset()
+	discoveryClient := &testDiscovery{FakeDiscovery: clientset.Discovery().(*fakediscovery.FakeDiscovery)}
+	discoveryClient.Resources = testAPIResources
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), testListKinds(), objects...)
+
+	// Seed the metadata client with the same objects as PartialObjectMetadata
+	var metadataObjects []runtime.Object
+	for _, obj := range objects {
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			metadataObjects = append(metadataObjects, &metav1.PartialObjectMetadata{
+				TypeMeta: metav1.TypeMeta{APIVersion: u.GetAPIVersion(), Kind: u.GetKind()},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              u.GetName(),
+					Namespace:         u.GetNamespace(),
+					Labels:            u.GetLabels(),
+					Annotations:       u.GetAnnotations(),
+					CreationTimestamp: u.GetCreationTimestamp(),
+					OwnerReferences:   u.GetOwnerReferences(),
+				},
+			})
+		}
+	}
+	metadataScheme := metadatafake.NewTestScheme()
+	metav1.AddMetaToScheme(metadataScheme)
+	metadataClient := metadatafake.NewSimpleMetadataClient(metadataScheme, metadataObjects...)
+
+	return newClient(clientset, dynamicClient, metadataClient, discoveryClient, nil)
+}
+
+// newTestObject builds an unstructured object with the given top-level fields merged in
+func newTestObject(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for key, value := range fields {
+		obj.Object[key] = value
+	}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestGetResourcesInNamespaceClusterScope(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Node", "", "node-a", nil),
+		newTestObject("v1", "Node", "", "node-b", nil),
+		newTestObject("v1", "Pod", "default", "web", nil),
+	)
+
+	resources, err := client.GetResourcesInNamespace(context.Background(), ClusterScope)
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		if resource.Namespaced {
+			t.Errorf("cluster scope returned namespaced resource %s", resource.FullName)
+		}
+		counts[resource.Name] = resource.Count
+	}
+	if _, ok := counts["nodes"]; !ok {
+		t.Fatalf("expected nodes in cluster-scoped resources, got %+v", resources)
+	}
+	if counts["nodes"] != 2 {
+		t.Errorf("expected 2 nodes, got %d", counts["nodes"])
+	}
+	if _, ok := counts["namespaces"]; !ok {
+		t.Errorf("expected namespaces in cluster-scoped resources")
+	}
+
+	namespaced, err := client.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	for _, resource := range namespaced {
+		if !resource.Namespaced {
+			t.Errorf("namespace view returned cluster-scoped resource %s", resource.FullName)
+		}
+	}
+}
+
+func TestGetClusterScopedResourcesCached(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Node", "", "node-a", nil))
+
+	resources, err := client.GetClusterScopedResources(context.Background())
+	if err != nil {
+		t.Fatalf("GetClusterScopedResources returned error: %v", err)
+	}
+	for _, resource := range resources {
+		if resource.Namespaced {
+			t.Errorf("returned namespaced resource %s", resource.FullName)
+		}
+		if resource.Name == "nodes" && resource.Count != 1 {
+			t.Errorf("expected 1 node, got %d", resource.Count)
+		}
+	}
+
+	cached, _, exists := client.cachedNamespaceResources(ClusterScope)
+	if !exists || len(cached) != len(resources) {
+		t.Errorf("expected cluster-scoped counts cached under %s", ClusterScope)
+	}
+}
+
+func TestGetResourceTotalMatchesNamespaceSum(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "team-a", "web-1", nil),
+		newTestObject("v1", "Pod", "team-a", "web-2", nil),
+		newTestObject("v1", "Pod", "team-b", "api-1", nil),
+		newTestObject("v1", "Pod", "team-c", "job-1", nil),
+		newTestObject("v1", "ConfigMap", "team-a", "settings", nil),
+	)
+
+	total, err := client.GetResourceTotal(context.Background(), "pods")
+	if err != nil {
+		t.Fatalf("GetResourceTotal returned error: %v", err)
+	}
+
+	sum := 0
+	for _, namespace := range []string{"team-a", "team-b", "team-c"} {
+		resources, err := client.GetResourcesInNamespace(context.Background(), namespace)
+		if err != nil {
+			t.Fatalf("GetResourcesInNamespace(%s) returned error: %v", namespace, err)
+		}
+		for _, resource := range resources {
+			if resource.FullName == "pods" {
+				sum += resource.Count
+			}
+		}
+	}
+
+	if total.Count != 4 || total.Count != sum {
+		t.Errorf("expected cluster-wide total 4 matching namespace sum %d, got %d", sum, total.Count)
+	}
+	if !total.Namespaced || total.Kind != "Pod" {
+		t.Errorf("unexpected resource info: %+v", total)
+	}
+}
+
+func TestFindResourceAmbiguousBareName(t *testing.T) {
+	client := newTestClient(newTestObject("example.com/v1", "Widget", "default", "gear", nil))
+
+	_, err := client.GetResourceObjects(context.Background(), "default", "widgets")
+	var ambiguous *AmbiguousResourceError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousResourceError, got %v", err)
+	}
+	candidates := strings.Join(ambiguous.Candidates, ",")
+	if candidates != "widgets.example.com,widgets.other.example.org" {
+		t.Errorf("unexpected candidates %q", candidates)
+	}
+
+	// The fullName always resolves unambiguously
+	objects, err := client.GetResourceObjects(context.Background(), "default", "widgets.example.com")
+	if err != nil {
+		t.Fatalf("GetResourceObjects by fullName returned error: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Name != "gear" {
+		t.Errorf("unexpected objects %+v", objects)
+	}
+
+	// The core group's fullName is the bare name, so core events win over events.k8s.io
+	resource, err := client.findResource("events", true)
+	if err != nil {
+		t.Fatalf("findResource(events) returned error: %v", err)
+	}
+	if resource.APIGroup != "" {
+		t.Errorf("expected core events, got group %q", resource.APIGroup)
+	}
+}
+
+func TestFindResourceByShortNameAndKind(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web", nil),
+		newTestObject("apps/v1", "Deployment", "default", "web", nil),
+	)
+
+	// Short names and kinds resolve case-insensitively in every object endpoint
+	objects, err := client.GetResourceObjects(context.Background(), "default", "po")
+	if err != nil || len(objects) != 1 || objects[0].Kind != "Pod" {
+		t.Fatalf("GetResourceObjects(po) returned %+v, error: %v", objects, err)
+	}
+	object, err := client.GetResourceObject(context.Background(), "default", "Deployment", "web")
+	if err != nil || object.Kind != "Deployment" {
+		t.Fatalf("GetResourceObject(Deployment) returned %+v, error: %v", object, err)
+	}
+	raw, err := client.GetRawResourceObject(context.Background(), "default", "DEPLOY", "web")
+	if err != nil || raw["kind"] != "Deployment" {
+		t.Fatalf("GetRawResourceObject(DEPLOY) returned %v, error: %v", raw, err)
+	}
+
+	for identifier, fullName := range map[string]string{"svc": "services", "pod": "pods", "sts": "statefulsets.apps"} {
+		resource, err := client.findResource(identifier, true)
+		if err != nil {
+			t.Errorf("findResource(%s) returned error: %v", identifier, err)
+			continue
+		}
+		if resource.FullName != fullName {
+			t.Errorf("findResource(%s) = %s, expected %s", identifier, resource.FullName, fullName)
+		}
+	}
+}
+
+func TestFindResourceShortNameCollisions(t *testing.T) {
+	client := newTestClient()
+
+	// ev and Event match events in the core group and in events.k8s.io; the core group wins
+	for _, identifier := range []string{"ev", "Event"} {
+		resource, err := client.findResource(identifier, true)
+		if err != nil {
+			t.Fatalf("findResource(%s) returned error: %v", identifier, err)
+		}
+		if resource.FullName != "events" {
+			t.Errorf("findResource(%s) = %s, expected core events", identifier, resource.FullName)
+		}
+	}
+
+	// A kind served by several non-core groups stays ambiguous
+	_, err := client.findResource("widget", true)
+	var ambiguous *AmbiguousResourceError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("expected AmbiguousResourceError, got %v", err)
+	}
+	if candidates := strings.Join(ambiguous.Candidates, ","); candidates != "widgets.example.com,widgets.other.example.org" {
+		t.Errorf("unexpected candidates %q", candidates)
+	}
+
+	// Short names only resolve to namespaced resources where those are required
+	if _, err := client.findResource("no", true); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected nodes to be refused as a namespaced resource, got %v", err)
+	}
+	if resource, err := client.findResource("no", false); err != nil || resource.Name != "nodes" {
+		t.Errorf("findResource(no) returned %+v, error: %v", resource, err)
+	}
+}
+
+// pagedDynamic wraps the fake dynamic and metadata clients, which ignore Limit and Continue, to
+// serve real pages and record the options of every List call
+type pagedDynamic struct {
+	dynamic.Interface
+
+	// latency simulates API server response time; it is applied outside the fake's lock
+	// so concurrent List calls overlap like they would against a real server
+	latency time.Duration
+
+	// resourceVersion is reported on every page; expiredResourceVersion, when set, is answered
+	// with 410 Gone like a snapshot that has been compacted away
+	resourceVersion        string
+	expiredResourceVersion string
+
+	// expiredContinue is a continue token answered with 410 Gone the next expiredContinueTimes
+	// times it is sent, like a token whose snapshot was compacted away mid-pagination
+	expiredContinue      string
+	expiredContinueTimes int
+
+	mu          sync.Mutex
+	listOptions []metav1.ListOptions
+	timeouts    []time.Duration // time left until the context deadline of each List call
+}
+
+func newPagedTestClient(objects ...runtime.Object) (*Client, *pagedDynamic) {
+	client := newTestClient(objects...)
+	paged := &pagedDynamic{Interface: client.dynamicClient, resourceVersion: "100"}
+	client.dynamicClient = paged
+	client.metadataClient = &pagedMetadata{Interface: client.metadataClient, parent: paged}
+	return client, paged
+}
+
+func (d *pagedDynamic) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	inner := d.Interface.Resource(gvr)
+	return &pagedNamespaceableResource{NamespaceableResourceInterface: inner, pager: &pagedResource{ResourceInterface: inner, parent: d}}
+}
+
+// recordedListOptions returns the options of every List call made so far
+func (d *pagedDynamic) recordedListOptions() []metav1.ListOptions {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]metav1.ListOptions(nil), d.listOptions...)
+}
+
+// recordList simulates latency, records a List call and fails it if it asks for the expired
+// resourceVersion or continue token or, like a real transport, if ctx is done
+func (d *pagedDynamic) recordList(ctx context.Context, opts metav1.ListOptions) error {
+	select {
+	case <-time.After(d.latency):
+	case <-ctx.Done():
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.listOptions = append(d.listOptions, opts)
+	if deadline, ok := ctx.Deadline(); ok {
+		d.timeouts = append(d.timeouts, time.Until(deadline))
+	}
+	d.mu.Unlock()
+
+	if opts.ResourceVersion != "" && opts.ResourceVersion == d.expiredResourceVersion {
+		return apierrors.NewResourceExpired(fmt.Sprintf("too old resource version: %s", opts.ResourceVersion))
+	}
+	if opts.Continue != "" && opts.Continue == d.expiredContinue && d.expiredContinueTimes > 0 {
+		d.expiredContinueTimes--
+		return apierrors.NewResourceExpired("The provided continue parameter is too old")
+	}
+	return nil
+}
+
+// pageBounds returns the item range of the page opts asks for out of total items, and the
+// continue token of the next page if there is one
+func pageBounds(total int, opts metav1.ListOptions) (offset, end int, next string, err error) {
+	if opts.Continue != "" {
+		if offset, err = strconv.Atoi(opts.Continue); err != nil {
+			return 0, 0, "", fmt.Errorf("invalid continue token %q", opts.Continue)
+		}
+	}
+	end = total
+	if opts.Limit > 0 && offset+int(opts.Limit) < end {
+		end = offset + int(opts.Limit)
+	}
+	if end < total {
+		next = strconv.Itoa(end)
+	}
+	return offset, end, next, nil
+}
+
+type pagedNamespaceableResource struct {
+	dynamic.NamespaceableResourceInterface
+	pager *pagedResource
+}
+
+func (r *pagedNamespaceableResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &pagedResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(namespace), parent: r.pager.parent}
+}
+
+func (r *pagedNamespaceableResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return r.pager.List(ctx, opts)
+}
+
+type pagedResource struct {
+	dynamic.ResourceInterface
+	parent *pagedDynamic
+}
+
+// List serves the page starting at the offset encoded in the continue token
+func (r *pagedResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	if err := r.parent.recordList(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	full, err := r.ResourceInterface.List(ctx, metav1.ListOptions{LabelSelector: opts.LabelSelector, FieldSelector: opts.FieldSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	offset, end, next, err := pageBounds(len(full.Items), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := full.DeepCopy()
+	page.Items = full.Items[offset:end]
+	page.SetResourceVersion(r.parent.resourceVersion)
+	if next != "" {
+		remaining := int64(len(full.Items) - end)
+		page.SetContinue(next)
+		page.SetRemainingItemCount(&remaining)
+	}
+	return page, nil
+}
+
+// pagedMetadata pages the fake metadata client like pagedDynamic pages the dynamic one, recording
+// its List calls in the same pagedDynamic
+type pagedMetadata struct {
+	metadata.Interface
+	parent *pagedDynamic
+}
+
+func (m *pagedMetadata) Resource(gvr schema.GroupVersionResource) metadata.Getter {
+	inner := m.Interface.Resource(gvr)
+	return &pagedMetadataGetter{Getter: inner, pager: &pagedMetadataResource{ResourceInterface: inner, parent: m.parent}}
+}
+
+type pagedMetadataGetter struct {
+	metadata.Getter
+	pager *pagedMetadataResource
+}
+
+func (g *pagedMetadataGetter) Namespace(namespace string) metadata.ResourceInterface {
+	return &pagedMetadataResource{ResourceInterface: g.Getter.Namespace(namespace), parent: g.pager.parent}
+}
+
+func (g *pagedMetadataGetter) List(ctx context.Context, opts metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	return g.pager.List(ctx, opts)
+}
+
+type pagedMetadataResource struct {
+	metadata.ResourceInterface
+	parent *pagedDynamic
+}
+
+// List serves the page starting at the offset encoded in the continue token
+func (r *pagedMetadataResource) List(ctx context.Context, opts metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	if err := r.parent.recordList(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	full, err := r.ResourceInterface.List(ctx, metav1.ListOptions{LabelSelector: opts.LabelSelector, FieldSelector: opts.FieldSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	offset, end, next, err := pageBounds(len(full.Items), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	page := full.DeepCopy()
+	page.Items = full.Items[offset:end]
+	page.ResourceVersion = r.parent.resourceVersion
+	if next != "" {
+		remaining := int64(len(full.Items) - end)
+		page.Continue = next
+		page.RemainingItemCount = &remaining
+	}
+	return page, nil
+}
+
+func TestListChunkSizeUsedAsLimit(t *testing.T) {
+	t.Setenv("LIST_CHUNK_SIZE", "2")
+
+	var objects []runtime.Object
+	for i := 0; i < 5; i++ {
+		objects = append(objects, newTestObject("v1", "ConfigMap", "default", fmt.Sprintf("cm-%d", i), nil))
+	}
+	client, paged := newPagedTestClient(objects...)
+
+	if client.listChunkSize != 2 {
+		t.Fatalf("expected listChunkSize 2 from LIST_CHUNK_SIZE, got %d", client.listChunkSize)
+	}
+
+	listed, err := client.GetResourceObjects(context.Background(), "default", "configmaps")
+	if err != nil {
+		t.Fatalf("GetResourceObjects returned error: %v", err)
+	}
+	if len(listed) != 5 {
+		t.Errorf("expected all 5 objects across pages, got %d", len(listed))
+	}
+
+	total, err := client.GetResourceTotal(context.Background(), "configmaps")
+	if err != nil {
+		t.Fatalf("GetResourceTotal returned error: %v", err)
+	}
+	if total.Count != 5 {
+		t.Errorf("expected total 5 across pages, got %d", total.Count)
+	}
+
+	calls := paged.recordedListOptions()
+	if len(calls) != 6 {
+		t.Errorf("expected 3 pages for each of the 2 operations, got %d List calls", len(calls))
+	}
+	for _, opts := range calls {
+		if opts.Limit != 2 {
+			t.Errorf("expected list Limit 2, got %d", opts.Limit)
+		}
+	}
+}
+
+func TestListChunkSizeDefault(t *testing.T) {
+	t.Setenv("LIST_CHUNK_SIZE", "")
+	if client := newTestClient(); client.listChunkSize != defaultListChunkSize {
+		t.Errorf("expected default chunk size %d, got %d", defaultListChunkSize, client.listChunkSize)
+	}
+
+	t.Setenv("LIST_CHUNK_SIZE", "not-a-number")
+	if client := newTestClient(); client.listChunkSize != defaultListChunkSize {
+		t.Errorf("expected default chunk size for invalid value, got %d", client.listChunkSize)
+	}
+}
+
+func TestTimeoutsFromEnv(t *testing.T) {
+	t.Setenv("COUNT_TIMEOUT", "7s")
+	t.Setenv("LIST_TIMEOUT", "2m")
+	client, paged := newPagedTestClient(newTestObject("v1", "Pod", "default", "web", nil))
+
+	if client.countTimeout != 7*time.Second || client.listTimeout != 2*time.Minute {
+		t.Fatalf("expected timeouts 7s and 2m, got %s and %s", client.countTimeout, client.listTimeout)
+	}
+	client.jitter = func() float64 { return 0 }
+
+	pods, err := client.findResource("pods", true)
+	if err != nil {
+		t.Fatalf("findResource returned error: %v", err)
+	}
+	if _, _, err := client.countResourceObjects(context.Background(), "default", *pods); err != nil {
+		t.Fatalf("countResourceObjects returned error: %v", err)
+	}
+	if _, err := client.GetResourceObjects(context.Background(), "default", "pods"); err != nil {
+		t.Fatalf("GetResourceObjects returned error: %v", err)
+	}
+
+	paged.mu.Lock()
+	timeouts := append([]time.Duration(nil), paged.timeouts...)
+	paged.mu.Unlock()
+	if len(timeouts) != 2 {
+		t.Fatalf("expected 2 List calls with a deadline, got %d", len(timeouts))
+	}
+	if timeouts[0] > 7*time.Second || timeouts[0] < 6*time.Second {
+		t.Errorf("expected the count to run with the 7s COUNT_TIMEOUT, %s was left", timeouts[0])
+	}
+	if timeouts[1] > 2*time.Minute || timeouts[1] < 119*time.Second {
+		t.Errorf("expected the listing to run with the 2m LIST_TIMEOUT, %s was left", timeouts[1])
+	}
+}
+
+func TestTimeoutsDefault(t *testing.T) {
+	t.Setenv("COUNT_TIMEOUT", "")
+	t.Setenv("LIST_TIMEOUT", "soon")
+	client := newTestClient()
+	if client.countTimeout != defaultCountTimeout || client.listTimeout != defaultListTimeout {
+		t.Errorf("expected default timeouts, got %s and %s", client.countTimeout, client.listTimeout)
+	}
+}
+
+func TestClientRateLimitsFromEnv(t *testing.T) {
+	t.Setenv("K8S_QPS", "25.5")
+	t.Setenv("K8S_BURST", "40")
+	client, err := newClientForConfig(&rest.Config{Host: "https://cluster.example.com"})
+	if err != nil {
+		t.Fatalf("newClientForConfig returned error: %v", err)
+	}
+	if client.config.QPS != 25.5 || client.config.Burst != 40 {
+		t.Errorf("expected QPS 25.5 and burst 40, got QPS %g and burst %d", client.config.QPS, client.config.Burst)
+	}
+
+	t.Setenv("K8S_QPS", "")
+	t.Setenv("K8S_BURST", "-1")
+	client, err = newClientForConfig(&rest.Config{Host: "https://cluster.example.com"})
+	if err != nil {
+		t.Fatalf("newClientForConfig returned error: %v", err)
+	}
+	if client.config.QPS != defaultQPS || int64(client.config.Burst) != defaultBurst {
+		t.Errorf("expected default QPS %d and burst %d, got QPS %g and burst %d", defaultQPS, defaultBurst, client.config.QPS, client.config.Burst)
+	}
+}
+
+func TestResourceVersionAppliedToListOptions(t *testing.T) {
+	t.Setenv("LIST_CHUNK_SIZE", "2")
+
+	var objects []runtime.Object
+	for i := 0; i < 5; i++ {
+		objects = append(objects, newTestObject("v1", "ConfigMap", "default", fmt.Sprintf("cm-%d", i), nil))
+	}
+	client, paged := newPagedTestClient(objects...)
+
+	list, err := client.GetResourceObjectList(context.Background(), "default", "configmaps", ListOptions{ResourceVersion: "42"})
+	if err != nil {
+		t.Fatalf("GetResourceObjectList returned error: %v", err)
+	}
+	if len(list.Items) != 5 {
+		t.Errorf("expected 5 objects, got %d", len(list.Items))
+	}
+	if list.ResourceVersion != "100" {
+		t.Errorf("expected resourceVersion 100, got %q", list.ResourceVersion)
+	}
+
+	calls := paged.recordedListOptions()
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 paged List calls, got %d", len(calls))
+	}
+	if calls[0].ResourceVersion != "42" || calls[0].Continue != "" {
+		t.Errorf("first page: expected resourceVersion 42 without continue, got %+v", calls[0])
+	}
+	for i, opts := range calls[1:] {
+		// The API server rejects a resourceVersion together with a continue token
+		if opts.ResourceVersion != "" || opts.Continue == "" {
+			t.Errorf("page %d: expected continue token only, got %+v", i+2, opts)
+		}
+	}
+}
+
+func TestResourceVersionExpiredRetriesLatest(t *testing.T) {
+	client, paged := newPagedTestClient(newTestObject("v1", "ConfigMap", "default", "cm", nil))
+	paged.expiredResourceVersion = "1"
+
+	list, err := client.GetResourceObjectList(context.Background(), "default", "configmaps", ListOptions{ResourceVersion: "1"})
+	if err != nil {
+		t.Fatalf("GetResourceObjectList returned error: %v", err)
+	}
+	if len(list.Items) != 1 || list.ResourceVersion != "100" {
+		t.Errorf("expected the latest state at resourceVersion 100, got %d items at %q", len(list.Items), list.ResourceVersion)
+	}
+
+	calls := paged.recordedListOptions()
+	if len(calls) != 2 || calls[1].ResourceVersion != "" {
+		t.Errorf("expected a retry without resourceVersion, got %+v", calls)
+	}
+}
+
+func TestNamespaceHealthPinsResourceVersion(t *testing.T) {
+	client, paged := newPagedTestClient(
+		newTestObject("apps/v1", "Deployment", "default", "web", nil),
+		newTestObject("v1", "Pod", "default", "web-1", nil),
+	)
+
+	if _, err := client.GetNamespaceHealth(context.Background(), "default"); err != nil {
+		t.Fatalf("GetNamespaceHealth returned error: %v", err)
+	}
+
+	calls := paged.recordedListOptions()
+	if len(calls) < 2 {
+		t.Fatalf("expected several List calls, got %d", len(calls))
+	}
+	if calls[0].ResourceVersion != "" {
+		t.Errorf("first list: expected latest state, got resourceVersion %q", calls[0].ResourceVersion)
+	}
+	for i, opts := range calls[1:] {
+		if opts.ResourceVersion != "100" {
+			t.Errorf("list %d: expected resourceVersion pinned to 100, got %q", i+2, opts.ResourceVersion)
+		}
+	}
+}
+
+func TestUseWatchCache(t *testing.T) {
+	t.Setenv("LIST_CHUNK_SIZE", "1")
+	t.Setenv("USE_WATCH_CACHE", "true")
+	client, paged := newPagedTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", nil),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+	)
+	pods, err := client.findResource("pods", true)
+	if err != nil {
+		t.Fatalf("findResource returned error: %v", err)
+	}
+
+	count, _, err := client.countResourceObjects(context.Background(), "default", *pods)
+	if err != nil || count != 2 {
+		t.Fatalf("countResourceObjects returned %d, error: %v", count, err)
+	}
+	calls := paged.recordedListOptions()
+	if len(calls) != 2 || calls[0].ResourceVersion != "0" {
+		t.Fatalf("expected the first page to be read with resourceVersion=0, got %+v", calls)
+	}
+	if calls[1].ResourceVersion != "" || calls[1].Continue == "" {
+		t.Errorf("expected the next page to be read by continue token only, got %+v", calls[1])
+	}
+
+	if _, err := client.GetResourceObjectList(context.Background(), "default", "pods", ListOptions{}); err != nil {
+		t.Fatalf("GetResourceObjectList returned error: %v", err)
+	}
+	if calls := paged.recordedListOptions(); calls[2].ResourceVersion != "0" {
+		t.Errorf("expected the listing to be read with resourceVersion=0, got %+v", calls[2])
+	}
+
+	// A server that rejects the watch cache read is asked for a quorum read instead
+	paged.expiredResourceVersion = "0"
+	count, _, err = client.countResourceObjects(context.Background(), "default", *pods)
+	if err != nil || count != 2 {
+		t.Fatalf("countResourceObjects after rejection returned %d, error: %v", count, err)
+	}
+	calls = paged.recordedListOptions()
+	if last := calls[len(calls)-2]; last.ResourceVersion != "" || last.Continue != "" {
+		t.Errorf("expected a quorum read after the rejection, got %+v", last)
+	}
+
+	// Without USE_WATCH_CACHE counts are quorum reads
+	t.Setenv("USE_WATCH_CACHE", "")
+	client, paged = newPagedTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+	if _, _, err := client.countResourceObjects(context.Background(), "default", *pods); err != nil {
+		t.Fatalf("countResourceObjects returned error: %v", err)
+	}
+	if calls := paged.recordedListOptions(); calls[0].ResourceVersion != "" {
+		t.Errorf("expected a quorum read without USE_WATCH_CACHE, got %+v", calls[0])
+	}
+}
+
+func TestFieldSelectorPassedThrough(t *testing.T) {
+	t.Setenv("LIST_CHUNK_SIZE", "1")
+	client, paged := newPagedTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", nil),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+	)
+
+	if _, err := client.GetResourceObjectList(context.Background(), "default", "pods", ListOptions{FieldSelector: "status.phase=Running"}); err != nil {
+		t.Fatalf("GetResourceObjectList returned error: %v", err)
+	}
+
+	calls := paged.recordedListOptions()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 paged List calls, got %d", len(calls))
+	}
+	for i, opts := range calls {
+		if opts.FieldSelector != "status.phase=Running" {
+			t.Errorf("page %d: expected field selector status.phase=Running, got %q", i+1, opts.FieldSelector)
+		}
+	}
+}
+
+func TestFieldSelectorInvalid(t *testing.T) {
+	client := newTestClient()
+
+	_, err := client.GetResourceObjectList(context.Background(), "default", "pods", ListOptions{FieldSelector: "status.phase"})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("malformed selector: expected ErrInvalidArgument, got %v", err)
+	}
+
+	// Fields the resource does not support are rejected by the API server with 400 Bad Request
+	client.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("list", "configmaps",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewBadRequest(`field label not supported: spec.nodeName`)
+		})
+	_, err = client.GetResourceObjectList(context.Background(), "default", "configmaps", ListOptions{FieldSelector: "spec.nodeName=node-a"})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("unsupported field: expected ErrInvalidArgument, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "field label not supported") {
+		t.Errorf("expected the server message in the error, got %v", err)
+	}
+}
+
+func TestLabelSelector(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web-prod", map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"env": "prod", "tier": "frontend"}}}),
+		newTestObject("v1", "Pod", "default", "api-prod", map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"env": "prod", "tier": "backend"}}}),
+		newTestObject("v1", "Pod", "default", "api-dev", map[string]interface{}{"metadata": map[string]interface{}{"labels": map[string]interface{}{"env": "dev", "tier": "backend"}}}),
+	)
+
+	list, err := client.GetResourceObjectList(context.Background(), "default", "pods", ListOptions{LabelSelector: "env in (prod,staging),tier!=frontend"})
+	if err != nil {
+		t.Fatalf("GetResourceObjectList returned error: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Name != "api-prod" {
+		t.Errorf("expected only api-prod, got %+v", list.Items)
+	}
+
+	_, err = client.GetResourceObjectList(context.Background(), "default", "pods", ListOptions{LabelSelector: "env in (prod"})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("malformed selector: expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestGetResourceObjectListPages(t *testing.T) {
+	var objects []runtime.Object
+	for i := 0; i < 5; i++ {
+		objects = append(objects, newTestObject("v1", "ConfigMap", "default", fmt.Sprintf("cm-%d", i), nil))
+	}
+	client, paged := newPagedTestClient(objects...)
+
+	var names []string
+	var remaining []int64
+	options := ListOptions{Limit: 2}
+	for pages := 1; ; pages++ {
+		list, err := client.GetResourceObjectList(context.Background(), "default", "configmaps", options)
+		if err != nil {
+			t.Fatalf("page %d: GetResourceObjectList returned error: %v", pages, err)
+		}
+		if len(list.Items) > 2 {
+			t.Errorf("page %d: expected at most 2 objects, got %d", pages, len(list.Items))
+		}
+		for _, object := range list.Items {
+			names = append(names, object.Name)
+		}
+		if list.Continue == "" {
+			if list.RemainingItemCount != nil {
+				t.Errorf("last page: expected no remaining item count, got %d", *list.RemainingItemCount)
+			}
+			break
+		}
+		if list.RemainingItemCount == nil {
+			t.Fatalf("page %d: expected a remaining item count", pages)
+		}
+		remaining = append(remaining, *list.RemainingItemCount)
+		options.Continue = list.Continue
+	}
+
+	if len(names) != 5 {
+		t.Errorf("expected all 5 objects across pages, got %v", names)
+	}
+	if len(remaining) != 2 || remaining[0] != 3 || remaining[1] != 1 {
+		t.Errorf("expected remaining item counts [3 1], got %v", remaining)
+	}
+
+	// Each page is a single List call with the caller's limit
+	calls := paged.recordedListOptions()
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 List calls, got %d", len(calls))
+	}
+	for i, opts := range calls {
+		if opts.Limit != 2 {
+			t.Errorf("call %d: expected limit 2, got %d", i+1, opts.Limit)
+		}
+	}
+}
+
+func TestGetResourceObjectListContinueRequiresLimit(t *testing.T) {
+	client := newTestClient()
+
+	_, err := client.GetResourceObjectList(context.Background(), "default", "configmaps", ListOptions{Continue: "2"})
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+// fakeMetadataClient returns the fake behind the client's metadata client, unwrapping the pager if there is one
+func fakeMetadataClient(client *Client) *metadatafake.FakeMetadataClient {
+	if paged, ok := client.metadataClient.(*pagedMetadata); ok {
+		return paged.Interface.(*metadatafake.FakeMetadataClient)
+	}
+	return client.metadataClient.(*metadatafake.FakeMetadataClient)
+}
@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ClusterInfo describes the cluster the client is connected to
+type ClusterInfo struct {
+	GitVersion string `json:"gitVersion"` // e.g. v1.28.2 or v1.28.2-eks-a5df82a
+	Major      string `json:"major"`
+	Minor      string `json:"minor"`
+	Platform   string `json:"platform"` // e.g. linux/amd64
+	GoVersion  string `json:"goVersion"`
+	BuildDate  string `json:"buildDate"`
+	// Host is the API server URL without credentials, empty for clients without a REST config
+	Host string `json:"host"`
+}
+
+// GetClusterInfo returns the Kubernetes version of the API server and the endpoint it is reached at
+func (c *Client) GetClusterInfo() (ClusterInfo, error) {
+	if c.discoveryClient == nil {
+		return ClusterInfo{}, fmt.Errorf("%w: no discovery client", ErrNoClient)
+	}
+
+	serverVersion, err := c.discoveryClient.ServerVersion()
+	if err != nil {
+		return ClusterInfo{}, wrapAPIError(err)
+	}
+
+	info := ClusterInfo{
+		GitVersion: serverVersion.GitVersion,
+		Major:      serverVersion.Major,
+		Minor:      serverVersion.Minor,
+		Platform:   serverVersion.Platform,
+		GoVersion:  serverVersion.GoVersion,
+		BuildDate:  serverVersion.BuildDate,
+	}
+	if c.config != nil {
+		info.Host = redactHost(c.config.Host)
+	}
+	return info, nil
+}
+
+// redactHost strips credentials from an API server address: the user info of a URL, and its query,
+// which some proxies use to carry tokens
+func redactHost(host string) string {
+	u, err := url.Parse(host)
+	if err != nil || u.Host == "" {
+		// A bare host:port; credentials would precede an @
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			return host[i+1:]
+		}
+		return host
+	}
+	u.User = nil
+	u.RawQuery = ""
+	return u.String()
+}
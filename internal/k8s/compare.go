@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ResourceComparison holds the counts of one resource type in the two namespaces of a comparison
+type ResourceComparison struct {
+	Name        string `json:"name"`
+	FullName    string `json:"fullName"`
+	DisplayName string `json:"displayName"`
+	Kind        string `json:"kind"`
+	APIGroup    string `json:"apiGroup"`
+	CountA      int    `json:"countA"`
+	CountB      int    `json:"countB"`
+	Delta       int    `json:"delta"` // CountB - CountA
+	// OnlyIn is "a" or "b" when only that namespace has objects of the type
+	OnlyIn string `json:"onlyIn,omitempty"`
+	// Error is set when the type could not be counted in either namespace, whose count is then 0
+	Error string `json:"error,omitempty"`
+}
+
+// CompareNamespaces compares the resource counts of namespaces a and b, which are read through
+// GetResourcesInNamespace and so come from the cache when fresh. Types without objects in either
+// namespace are left out unless counting them failed. Results are sorted by full name.
+func (c *Client) CompareNamespaces(ctx context.Context, a, b string) ([]ResourceComparison, error) {
+	var resourcesA, resourcesB []ResourceInfo
+	var errA, errB error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		resourcesA, errA = c.GetResourcesInNamespace(ctx, a)
+	}()
+	go func() {
+		defer wg.Done()
+		resourcesB, errB = c.GetResourcesInNamespace(ctx, b)
+	}()
+	wg.Wait()
+	if errA != nil {
+		return nil, fmt.Errorf("namespace %s: %w", a, errA)
+	}
+	if errB != nil {
+		return nil, fmt.Errorf("namespace %s: %w", b, errB)
+	}
+
+	comparisons := make(map[string]*ResourceComparison)
+	entry := func(resource ResourceInfo) *ResourceComparison {
+		comparison, exists := comparisons[resource.FullName]
+		if !exists {
+			comparison = &ResourceComparison{
+				Name:        resource.Name,
+				FullName:    resource.FullName,
+				DisplayName: resource.DisplayName,
+				Kind:        resource.Kind,
+				APIGroup:    resource.APIGroup,
+			}
+			comparisons[resource.FullName] = comparison
+		}
+		if resource.Error != "" && comparison.Error == "" {
+			comparison.Error = resource.Error
+		}
+		return comparison
+	}
+	for _, resource := range resourcesA {
+		entry(resource).CountA = resource.Count
+	}
+	for _, resource := range resourcesB {
+		entry(resource).CountB = resource.Count
+	}
+
+	result := make([]ResourceComparison, 0, len(comparisons))
+	for _, comparison := range comparisons {
+		if comparison.CountA == 0 && comparison.CountB == 0 && comparison.Error == "" {
+			continue
+		}
+		comparison.Delta = comparison.CountB - comparison.CountA
+		if comparison.Error == "" {
+			switch {
+			case comparison.CountB == 0:
+				comparison.OnlyIn = "a"
+			case comparison.CountA == 0:
+				comparison.OnlyIn = "b"
+			}
+		}
+		result = append(result, *comparison)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].FullName < result[j].FullName })
+	return result, nil
+}
@@ -0,0 +1,64 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompareNamespaces(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "staging", "web-1", nil),
+		newTestObject("v1", "Pod", "prod", "web-1", nil),
+		newTestObject("v1", "Pod", "prod", "web-2", nil),
+		newTestObject("v1", "ConfigMap", "staging", "settings", nil),
+		newTestObject("v1", "Service", "prod", "web", nil),
+	)
+
+	comparisons, err := client.CompareNamespaces(context.Background(), "staging", "prod")
+	if err != nil {
+		t.Fatalf("CompareNamespaces returned error: %v", err)
+	}
+
+	expected := []ResourceComparison{
+		{Name: "configmaps", CountA: 1, CountB: 0, Delta: -1, OnlyIn: "a"},
+		{Name: "pods", CountA: 1, CountB: 2, Delta: 1},
+		{Name: "services", CountA: 0, CountB: 1, Delta: 1, OnlyIn: "b"},
+	}
+	if len(comparisons) != len(expected) {
+		t.Fatalf("expected %d compared types, got %+v", len(expected), comparisons)
+	}
+	for i, want := range expected {
+		got := comparisons[i]
+		if got.Name != want.Name || got.CountA != want.CountA || got.CountB != want.CountB ||
+			got.Delta != want.Delta || got.OnlyIn != want.OnlyIn {
+			t.Errorf("comparison %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	// Both namespaces are now cached, so the comparison is served from the cache
+	for _, namespace := range []string{"staging", "prod"} {
+		if client.NamespaceCacheTime(namespace).IsZero() {
+			t.Errorf("expected %s to be cached", namespace)
+		}
+	}
+}
+
+func TestCompareNamespacesDisjoint(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "team-a", "web", nil),
+		newTestObject("v1", "Secret", "team-b", "token", nil),
+	)
+
+	comparisons, err := client.CompareNamespaces(context.Background(), "team-a", "team-b")
+	if err != nil {
+		t.Fatalf("CompareNamespaces returned error: %v", err)
+	}
+
+	onlyIn := make(map[string]string)
+	for _, comparison := range comparisons {
+		onlyIn[comparison.Name] = comparison.OnlyIn
+	}
+	if len(onlyIn) != 2 || onlyIn["pods"] != "a" || onlyIn["secrets"] != "b" {
+		t.Errorf("expected pods only in a and secrets only in b, got %+v", comparisons)
+	}
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envInt64 reads a positive integer from the environment, falling back to def when unset or invalid
+func envInt64(name string, def int64) int64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || value <= 0 {
+		slog.Warn("Invalid environment variable, using default", "name", name, "value", raw, "default", def)
+		return def
+	}
+	return value
+}
+
+// envLimit reads a non-negative integer from the environment for settings where 0 disables the
+// limit, falling back to def when unset or invalid
+func envLimit(name string, def int64) int64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || value < 0 {
+		slog.Warn("Invalid environment variable, using default", "name", name, "value", raw, "default", def)
+		return def
+	}
+	return value
+}
+
+// envFloat reads a positive number from the environment, falling back to def when unset or invalid
+func envFloat(name string, def float64) float64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value <= 0 {
+		slog.Warn("Invalid environment variable, using default", "name", name, "value", raw, "default", def)
+		return def
+	}
+	return value
+}
+
+// envFraction reads a number between 0 and 1 inclusive from the environment, falling back to def
+// when unset, invalid or out of range
+func envFraction(name string, def float64) float64 {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || !(value >= 0 && value <= 1) {
+		slog.Warn("Invalid environment variable, must be between 0 and 1, using default", "name", name, "value", raw, "default", def)
+		return def
+	}
+	return value
+}
+
+// envDuration reads a positive Go duration such as "10s" from the environment, falling back to def when unset or invalid
+func envDuration(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		slog.Warn("Invalid environment variable, using default", "name", name, "value", raw, "default", def)
+		return def
+	}
+	return value
+}
+
+// envBool reads a boolean flag from the environment; "true", "1" and "yes" enable it
+func envBool(name string) bool {
+	value := strings.ToLower(os.Getenv(name))
+	return value == "true" || value == "1" || value == "yes"
+}
+
+// envSet reads a comma-separated list from the environment, falling back to def when unset
+func envSet(name, def string) map[string]bool {
+	raw := os.Getenv(name)
+	if raw == "" {
+		raw = def
+	}
+
+	set := make(map[string]bool)
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
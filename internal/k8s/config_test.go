@@ -0,0 +1,67 @@
+package k8s
+
+import "testing"
+
+func TestEnvLimit(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want int64
+	}{
+		{"", 7},
+		{"25", 25},
+		{"0", 0}, // disables the limit
+		{"-1", 7},
+		{"many", 7},
+	}
+	for _, tt := range tests {
+		t.Setenv("TEST_LIMIT", tt.raw)
+		if got := envLimit("TEST_LIMIT", 7); got != tt.want {
+			t.Errorf("TEST_LIMIT=%q: got %d, want %d", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestEnvFraction(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want float64
+	}{
+		{"", 0.2},
+		{"0.5", 0.5},
+		{"0", 0}, // disables jitter
+		{"1", 1},
+		{"1.5", 0.2},
+		{"-0.1", 0.2},
+		{"NaN", 0.2},
+		{"lots", 0.2},
+	}
+	for _, tt := range tests {
+		t.Setenv("TEST_FRACTION", tt.raw)
+		if got := envFraction("TEST_FRACTION", 0.2); got != tt.want {
+			t.Errorf("TEST_FRACTION=%q: got %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestCountTimeoutJitterDisabled(t *testing.T) {
+	t.Setenv("COUNT_TIMEOUT_JITTER", "0")
+	client := newTestClient()
+	client.jitter = func() float64 { return 0.999 }
+
+	if got := client.jitteredCountTimeout(); got != client.countTimeout {
+		t.Errorf("COUNT_TIMEOUT_JITTER=0: got timeout %v, want exactly %v", got, client.countTimeout)
+	}
+}
+
+func TestZeroDisablesObjectAndResourceTypeLimits(t *testing.T) {
+	t.Setenv("MAX_RESOURCE_TYPES", "0")
+	t.Setenv("MAX_OBJECTS", "0")
+	client := newTestClient()
+
+	if client.maxResourceTypes != 0 {
+		t.Errorf("MAX_RESOURCE_TYPES=0: got %d, want no cap", client.maxResourceTypes)
+	}
+	if client.maxObjects != 0 {
+		t.Errorf("MAX_OBJECTS=0: got %d, want no cap instead of the default %d", client.maxObjects, defaultMaxObjects)
+	}
+}
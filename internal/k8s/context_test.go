@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestListAbortedWhenContextCancelled(t *testing.T) {
+	client, paged := newPagedTestClient(newTestObject("v1", "ConfigMap", "default", "settings", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.GetResourceObjects(ctx, "default", "configmaps"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetResourceObjects: expected context.Canceled, got %v", err)
+	}
+	if _, err := client.GetResourceTotal(ctx, "configmaps"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetResourceTotal: expected context.Canceled, got %v", err)
+	}
+	if calls := paged.recordedListOptions(); len(calls) != 0 {
+		t.Errorf("expected no List calls to complete, got %d", len(calls))
+	}
+}
+
+func TestCountAbortedWhenContextCancelled(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+	client.countConcurrency = 1
+
+	// Cancel the request while the first resource is being counted
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	lists := 0
+	fakeMetadataClient(client).PrependReactor("list", "*",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			mu.Lock()
+			lists++
+			first := lists == 1
+			mu.Unlock()
+			if first {
+				cancel()
+			}
+			return false, nil, nil
+		})
+
+	_, err := client.GetResourcesInNamespace(ctx, "default")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	all, _ := client.GetAPIResources()
+	mu.Lock()
+	defer mu.Unlock()
+	if lists >= len(all) {
+		t.Errorf("expected counting to stop after the cancellation, %d resources were listed", lists)
+	}
+
+	// Incomplete counts are neither cached nor reported as complete
+	if _, _, cached := client.cachedNamespaceResources("default"); cached {
+		t.Error("expected the aborted count not to be cached")
+	}
+	if status := client.GetNamespaceStatus("default"); status.State != CountStateError {
+		t.Errorf("expected error status after the aborted count, got %s", status.State)
+	}
+}
+
+func TestSharedCountSurvivesCancelledLeader(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+
+	// Hold the first pods count so a second request joins it before the first one is cancelled
+	var mu sync.Mutex
+	podLists := 0
+	listing := make(chan struct{})
+	release := make(chan struct{})
+	fakeMetadataClient(client).PrependReactor("list", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			mu.Lock()
+			podLists++
+			first := podLists == 1
+			mu.Unlock()
+			if first {
+				close(listing)
+				<-release
+			}
+			return false, nil, nil
+		})
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetResourcesInNamespace(leaderCtx, "default")
+		leaderErr <- err
+	}()
+	<-listing
+
+	type result struct {
+		resources []ResourceInfo
+		err       error
+	}
+	follower := make(chan result, 1)
+	go func() {
+		resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+		follower <- result{resources, err}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancelLeader()
+	close(release)
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader: expected context.Canceled, got %v", err)
+	}
+	got := <-follower
+	if got.err != nil {
+		t.Fatalf("follower: expected its own count to succeed, got %v", got.err)
+	}
+	counts := make(map[string]int)
+	for _, resource := range got.resources {
+		counts[resource.FullName] = resource.Count
+	}
+	if counts["pods"] != 1 {
+		t.Errorf("follower: expected 1 pod, got %s", fmt.Sprint(counts["pods"]))
+	}
+}
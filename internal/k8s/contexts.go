@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// loadingRules resolves the kubeconfig like kubectl does: an explicit path wins, otherwise
+// $KUBECONFIG and then ~/.kube/config are used
+func loadingRules(kubeconfig string) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	return rules
+}
+
+// NewClientForContext creates a Kubernetes client for a named context of the kubeconfig
+func NewClientForContext(kubeconfig, contextName string) (*Client, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules(kubeconfig),
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	)
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes config for context %s: %w", contextName, err)
+	}
+	client, err := newClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client.namespace = kubeconfigNamespace(kubeconfig, contextName)
+	return client, nil
+}
+
+// kubeconfigNamespace returns the namespace of a kubeconfig context, the current one when
+// contextName is empty: the context's namespace, "default" when it sets none, or the namespace of
+// the pod's ServiceAccount when running in-cluster without a kubeconfig. It returns "" when the
+// kubeconfig cannot be read.
+func kubeconfigNamespace(kubeconfig, contextName string) string {
+	namespace, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules(kubeconfig),
+		&clientcmd.ConfigOverrides{CurrentContext: contextName},
+	).Namespace()
+	if err != nil {
+		return ""
+	}
+	return namespace
+}
+
+// CurrentNamespace returns the namespace of the kubeconfig context the client was created for, as
+// kubectl would default to it, or "" when it is unknown
+func (c *Client) CurrentNamespace() string {
+	return c.namespace
+}
+
+// ListContexts returns the context names of the kubeconfig, sorted, and the current context
+func ListContexts(kubeconfig string) ([]string, string, error) {
+	rawConfig, err := loadingRules(kubeconfig).Load()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	contexts := make([]string, 0, len(rawConfig.Contexts))
+	for name := range rawConfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	return contexts, rawConfig.CurrentContext, nil
+}
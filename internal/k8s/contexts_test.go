@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com:6443
+- name: production
+  cluster:
+    server: https://production.example.com:6443
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    namespace: team-a
+    user: admin
+- name: production
+  context:
+    cluster: production
+    user: admin
+users:
+- name: admin
+  user:
+    token: test-token
+`
+
+// writeTestKubeconfig writes testKubeconfig to a temporary file and returns its path
+func writeTestKubeconfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+	return path
+}
+
+func TestListContexts(t *testing.T) {
+	kubeconfig := writeTestKubeconfig(t)
+
+	contexts, current, err := ListContexts(kubeconfig)
+	if err != nil {
+		t.Fatalf("ListContexts returned error: %v", err)
+	}
+	if len(contexts) != 2 || contexts[0] != "production" || contexts[1] != "staging" {
+		t.Errorf("expected [production staging], got %v", contexts)
+	}
+	if current != "staging" {
+		t.Errorf("expected current context staging, got %q", current)
+	}
+}
+
+func TestNewClientForContext(t *testing.T) {
+	kubeconfig := writeTestKubeconfig(t)
+
+	for contextName, host := range map[string]string{
+		"production": "https://production.example.com:6443",
+		"staging":    "https://staging.example.com:6443",
+	} {
+		client, err := NewClientForContext(kubeconfig, contextName)
+		if err != nil {
+			t.Fatalf("NewClientForContext(%s) returned error: %v", contextName, err)
+		}
+		if client.config.Host != host {
+			t.Errorf("context %s: expected host %s, got %s", contextName, host, client.config.Host)
+		}
+	}
+
+	if _, err := NewClientForContext(kubeconfig, "missing"); err == nil {
+		t.Error("expected an error for an unknown context")
+	}
+}
+
+func TestRestConfigFromKubeconfigEnv(t *testing.T) {
+	// KUBECONFIG may list several files; missing ones are skipped like kubectl does
+	missing := filepath.Join(t.TempDir(), "missing")
+	t.Setenv("KUBECONFIG", missing+string(filepath.ListSeparator)+writeTestKubeconfig(t))
+
+	config, err := restConfig("")
+	if err != nil {
+		t.Fatalf("restConfig returned error: %v", err)
+	}
+	if config.Host != "https://staging.example.com:6443" {
+		t.Errorf("expected the staging server from KUBECONFIG, got %s", config.Host)
+	}
+}
+
+func TestRestConfigExplicitPathWins(t *testing.T) {
+	t.Setenv("KUBECONFIG", writeTestKubeconfig(t))
+
+	explicit := filepath.Join(t.TempDir(), "explicit")
+	production := strings.Replace(testKubeconfig, "current-context: staging", "current-context: production", 1)
+	if err := os.WriteFile(explicit, []byte(production), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	config, err := restConfig(explicit)
+	if err != nil {
+		t.Fatalf("restConfig returned error: %v", err)
+	}
+	if config.Host != "https://production.example.com:6443" {
+		t.Errorf("expected the production server from the explicit path, got %s", config.Host)
+	}
+}
+
+func TestCurrentNamespace(t *testing.T) {
+	kubeconfig := writeTestKubeconfig(t)
+
+	client, err := NewClient(kubeconfig)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	if got := client.CurrentNamespace(); got != "team-a" {
+		t.Errorf("current context namespace = %q, want team-a", got)
+	}
+
+	// A context without a namespace defaults to "default", like kubectl
+	production, err := NewClientForContext(kubeconfig, "production")
+	if err != nil {
+		t.Fatalf("NewClientForContext returned error: %v", err)
+	}
+	if got := production.CurrentNamespace(); got != "default" {
+		t.Errorf("production namespace = %q, want default", got)
+	}
+}
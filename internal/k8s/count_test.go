@@ -0,0 +1,325 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCountResourcesPreservesOrderAndZeroesFailures(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", nil),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+		newTestObject("v1", "ConfigMap", "default", "settings", nil),
+		newTestObject("apps/v1", "Deployment", "default", "web", nil),
+	)
+	client.countConcurrency = 4
+	fakeMetadata := fakeMetadataClient(client)
+	fakeMetadata.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", errors.New("rbac"))
+	})
+	fakeMetadata.PrependReactor("list", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection reset")
+	})
+
+	all, err := client.GetAPIResources()
+	if err != nil {
+		t.Fatalf("GetAPIResources returned error: %v", err)
+	}
+	var expectedOrder []string
+	for _, resource := range all {
+		if resource.Namespaced {
+			expectedOrder = append(expectedOrder, resource.FullName)
+		}
+	}
+
+	resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	if len(resources) != len(expectedOrder) {
+		t.Fatalf("expected %d resources, got %d", len(expectedOrder), len(resources))
+	}
+
+	expectedCounts := map[string]int{"pods": 2, "configmaps": 1, "deployments.apps": 1, "secrets": 0, "services": 0}
+	for i, resource := range resources {
+		if resource.FullName != expectedOrder[i] {
+			t.Errorf("position %d: expected %s, got %s", i, expectedOrder[i], resource.FullName)
+		}
+		if expected, ok := expectedCounts[resource.FullName]; ok && resource.Count != expected {
+			t.Errorf("%s: expected count %d, got %d", resource.FullName, expected, resource.Count)
+		}
+	}
+}
+
+func TestCountStatus(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web", nil))
+	fakeMetadata := fakeMetadataClient(client)
+	fakeMetadata.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", errors.New("rbac"))
+	})
+	fakeMetadata.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, context.DeadlineExceeded
+	})
+	fakeMetadata.PrependReactor("list", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection reset")
+	})
+
+	resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+
+	expected := map[string]struct {
+		count  int
+		status CountStatus
+		failed bool
+	}{
+		"pods":             {1, CountStatusOK, false},
+		"deployments.apps": {0, CountStatusOK, false},
+		"secrets":          {0, CountStatusForbidden, true},
+		"configmaps":       {0, CountStatusTimeout, true},
+		"services":         {0, CountStatusError, true},
+	}
+	for _, resource := range resources {
+		e, ok := expected[resource.FullName]
+		if !ok {
+			continue
+		}
+		if resource.Count != e.count || resource.CountStatus != e.status {
+			t.Errorf("%s: count %d with status %q, expected %d with %q", resource.FullName, resource.Count, resource.CountStatus, e.count, e.status)
+		}
+		if (resource.Error != "") != e.failed {
+			t.Errorf("%s: unexpected error %q", resource.FullName, resource.Error)
+		}
+	}
+}
+
+func TestCountConcurrencyFromEnv(t *testing.T) {
+	t.Setenv("COUNT_CONCURRENCY", "3")
+	if client := newTestClient(); client.countConcurrency != 3 {
+		t.Errorf("expected countConcurrency 3, got %d", client.countConcurrency)
+	}
+}
+
+// BenchmarkCountResources compares sequential and parallel counting against an API server
+// that takes 2ms per list request
+func BenchmarkCountResources(b *testing.B) {
+	var objects []runtime.Object
+	for i := 0; i < 10; i++ {
+		objects = append(objects, newTestObject("v1", "Pod", "default", fmt.Sprintf("pod-%d", i), nil))
+	}
+
+	for _, concurrency := range []int{1, 8} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			client, paged := newPagedTestClient(objects...)
+			paged.latency = 2 * time.Millisecond
+			client.countConcurrency = concurrency
+
+			resources, err := client.GetAPIResources()
+			if err != nil {
+				b.Fatalf("GetAPIResources returned error: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				scan := make([]ResourceInfo, len(resources))
+				copy(scan, resources)
+				client.countResources(context.Background(), "default", scan, nil)
+			}
+		})
+	}
+}
+
+func TestJitteredCountTimeout(t *testing.T) {
+	t.Setenv("COUNT_TIMEOUT", "3s")
+	t.Setenv("COUNT_TIMEOUT_JITTER", "0.5")
+	client := newTestClient()
+
+	for _, tc := range []struct {
+		random   float64
+		expected time.Duration
+	}{
+		{0, 3 * time.Second},
+		{0.5, 3750 * time.Millisecond},
+		{0.999, 4498500 * time.Microsecond},
+	} {
+		client.jitter = func() float64 { return tc.random }
+		if timeout := client.jitteredCountTimeout(); timeout != tc.expected {
+			t.Errorf("jitter %v: timeout = %v, expected %v", tc.random, timeout, tc.expected)
+		}
+	}
+
+	// The real source stays within [COUNT_TIMEOUT, COUNT_TIMEOUT * 1.5)
+	client = newTestClient()
+	for i := 0; i < 1000; i++ {
+		timeout := client.jitteredCountTimeout()
+		if timeout < 3*time.Second || timeout >= 4500*time.Millisecond {
+			t.Fatalf("timeout %v outside [3s, 4.5s)", timeout)
+		}
+	}
+}
+
+func TestGetResourceCountsAllNamespaces(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", nil),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+		newTestObject("v1", "Pod", "kube-system", "coredns", nil),
+		newTestObject("apps/v1", "Deployment", "monitoring", "grafana", nil),
+		newTestObject("v1", "Node", "", "node-a", nil),
+	)
+	fakeMetadata := fakeMetadataClient(client)
+	fakeMetadata.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "", errors.New("rbac"))
+	})
+
+	resources, err := client.GetResourceCountsAllNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("GetResourceCountsAllNamespaces returned error: %v", err)
+	}
+
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		counts[resource.FullName] = resource.Count
+	}
+	want := map[string]int{"pods": 3, "deployments.apps": 1, "nodes": 1, "secrets": 0, "configmaps": 0}
+	for fullName, count := range want {
+		if got, found := counts[fullName]; !found || got != count {
+			t.Errorf("%s: expected %d objects across namespaces, got %d (listed: %t)", fullName, count, got, found)
+		}
+	}
+
+	// The aggregate is cached under its own key, separate from the per-namespace caches
+	if _, _, cached := client.cachedNamespaceResources(AllNamespaces); !cached {
+		t.Error("expected the aggregate to be cached under AllNamespaces")
+	}
+	if _, _, cached := client.cachedNamespaceResources("default"); cached {
+		t.Error("expected the aggregate not to populate the default namespace cache")
+	}
+}
+
+// BenchmarkCountPayload compares the response size of a full list against the metadata-only list
+// used for counting, for a page of ConfigMaps carrying sizeable data
+func BenchmarkCountPayload(b *testing.B) {
+	data := map[string]interface{}{"config.yaml": strings.Repeat("key: value\n", 400)}
+	full := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMapList"}}
+	partial := &metav1.PartialObjectMetadataList{TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadataList"}}
+	for i := 0; i < 500; i++ {
+		obj := newTestObject("v1", "ConfigMap", "default", fmt.Sprintf("cm-%d", i), map[string]interface{}{"data": data})
+		obj.SetLabels(map[string]string{"app": "web"})
+		full.Items = append(full.Items, *obj)
+		partial.Items = append(partial.Items, metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadata"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: obj.GetNamespace(), Name: obj.GetName(), Labels: obj.GetLabels()},
+		})
+	}
+
+	for _, bc := range []struct {
+		name string
+		list interface{}
+	}{{"full", full}, {"metadata", partial}} {
+		b.Run(bc.name, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				encoded, err := json.Marshal(bc.list)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(encoded)
+			}
+			b.ReportMetric(float64(size), "bytes/list")
+		})
+	}
+}
+
+func TestCountResourceObjectsPages(t *testing.T) {
+	t.Setenv("LIST_CHUNK_SIZE", "3")
+
+	var objects []runtime.Object
+	for i := 0; i < 7; i++ {
+		objects = append(objects, newTestObject("v1", "Pod", "default", fmt.Sprintf("web-%d", i), nil))
+	}
+	client, paged := newPagedTestClient(objects...)
+	pods, err := client.ResolveResource("pods")
+	if err != nil {
+		t.Fatalf("ResolveResource returned error: %v", err)
+	}
+
+	count, resourceVersion, err := client.countResourceObjects(context.Background(), "default", *pods)
+	if err != nil {
+		t.Fatalf("countResourceObjects returned error: %v", err)
+	}
+	if count != 7 {
+		t.Errorf("expected 7 pods across pages, got %d", count)
+	}
+	if resourceVersion != "100" {
+		t.Errorf("expected the resourceVersion of the first page, got %q", resourceVersion)
+	}
+
+	// Each page is fetched once, in order, and never with an unbounded Limit
+	calls := paged.recordedListOptions()
+	wantContinue := []string{"", "3", "6"}
+	if len(calls) != len(wantContinue) {
+		t.Fatalf("expected %d List calls, got %d: %+v", len(wantContinue), len(calls), calls)
+	}
+	for i, opts := range calls {
+		if opts.Limit != 3 {
+			t.Errorf("call %d: expected Limit 3, got %d", i, opts.Limit)
+		}
+		if opts.Continue != wantContinue[i] {
+			t.Errorf("call %d: expected continue token %q, got %q", i, wantContinue[i], opts.Continue)
+		}
+	}
+}
+
+func TestCountResourceObjectsRestartsOnExpiredContinue(t *testing.T) {
+	t.Setenv("LIST_CHUNK_SIZE", "3")
+
+	var objects []runtime.Object
+	for i := 0; i < 7; i++ {
+		objects = append(objects, newTestObject("v1", "Pod", "default", fmt.Sprintf("web-%d", i), nil))
+	}
+	client, paged := newPagedTestClient(objects...)
+	pods, err := client.ResolveResource("pods")
+	if err != nil {
+		t.Fatalf("ResolveResource returned error: %v", err)
+	}
+
+	// The token of the third page expires once: counting starts over instead of adding up pages of
+	// two snapshots, and the pages of the abandoned pass are not counted twice
+	paged.expiredContinue, paged.expiredContinueTimes = "6", 1
+	count, _, err := client.countResourceObjects(context.Background(), "default", *pods)
+	if err != nil {
+		t.Fatalf("countResourceObjects returned error: %v", err)
+	}
+	if count != 7 {
+		t.Errorf("expected 7 pods after restarting, got %d", count)
+	}
+	var continues []string
+	for _, opts := range paged.recordedListOptions() {
+		continues = append(continues, opts.Continue)
+	}
+	if got := strings.Join(continues, ","); got != ",3,6,,3,6" {
+		t.Errorf("expected the pages to be listed again from the first, got continue tokens %q", got)
+	}
+
+	// A token that keeps expiring fails the count after maxExpiredCountRestarts restarts
+	paged.expiredContinueTimes = maxExpiredCountRestarts + 1
+	if _, _, err := client.countResourceObjects(context.Background(), "default", *pods); !isResourceExpired(err) {
+		t.Errorf("expected the expired continue token to fail the count, got %v", err)
+	}
+	if paged.expiredContinueTimes != 0 {
+		t.Errorf("expected %d attempts at the expired page, %d left", maxExpiredCountRestarts+1, paged.expiredContinueTimes)
+	}
+}
@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdGVR is the apiextensions resource of CustomResourceDefinitions. Like the metrics API it is read
+// with the dynamic client, so the apiextensions clientset is not needed.
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// CRDVersion is one version of a custom resource
+type CRDVersion struct {
+	Name    string `json:"name"`
+	Served  bool   `json:"served"`
+	Storage bool   `json:"storage"` // the version objects are persisted in
+	// HasStatus reports a status subresource, whose updates leave spec alone
+	HasStatus bool `json:"hasStatus"`
+}
+
+// CRDInfo describes a CustomResourceDefinition
+type CRDInfo struct {
+	Name     string       `json:"name"` // plural.group, e.g. certificates.cert-manager.io
+	Group    string       `json:"group"`
+	Kind     string       `json:"kind"`
+	Plural   string       `json:"plural"`
+	Scope    string       `json:"scope"` // Namespaced or Cluster
+	Versions []CRDVersion `json:"versions"`
+	// StoredVersions lists every version objects may still be stored in, from the CRD status
+	StoredVersions    []string  `json:"storedVersions"`
+	HasStatus         bool      `json:"hasStatus"` // any version has a status subresource
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+// GetCRDs lists the CustomResourceDefinitions of the cluster, sorted by name
+func (c *Client) GetCRDs(ctx context.Context) ([]CRDInfo, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	list, err := c.dynamicClient.Resource(crdGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	crds := make([]CRDInfo, 0, len(list.Items))
+	for i := range list.Items {
+		crds = append(crds, toCRDInfo(&list.Items[i]))
+	}
+	sort.Slice(crds, func(i, j int) bool { return crds[i].Name < crds[j].Name })
+
+	slog.DebugContext(ctx, "Listed CustomResourceDefinitions", "count", len(crds))
+	return crds, nil
+}
+
+// toCRDInfo reads the fields of CRDInfo from an apiextensions.k8s.io/v1 CustomResourceDefinition
+func toCRDInfo(crd *unstructured.Unstructured) CRDInfo {
+	info := CRDInfo{
+		Name:              crd.GetName(),
+		Versions:          []CRDVersion{},
+		CreationTimestamp: crd.GetCreationTimestamp().Time,
+	}
+	info.Group, _, _ = unstructured.NestedString(crd.Object, "spec", "group")
+	info.Kind, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	info.Plural, _, _ = unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	info.Scope, _, _ = unstructured.NestedString(crd.Object, "spec", "scope")
+	info.StoredVersions, _, _ = unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	if info.StoredVersions == nil {
+		info.StoredVersions = []string{}
+	}
+
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		crdVersion := CRDVersion{}
+		crdVersion.Name, _, _ = unstructured.NestedString(version, "name")
+		crdVersion.Served, _, _ = unstructured.NestedBool(version, "served")
+		crdVersion.Storage, _, _ = unstructured.NestedBool(version, "storage")
+		_, crdVersion.HasStatus, _ = unstructured.NestedMap(version, "subresources", "status")
+
+		info.Versions = append(info.Versions, crdVersion)
+		info.HasStatus = info.HasStatus || crdVersion.HasStatus
+	}
+	return info
+}
@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newTestCRD(group, kind, plural, scope string, versions ...interface{}) *unstructured.Unstructured {
+	return newTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", plural+"."+group, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"group":    group,
+			"scope":    scope,
+			"names":    map[string]interface{}{"kind": kind, "plural": plural},
+			"versions": versions,
+		},
+		"status": map[string]interface{}{"storedVersions": []interface{}{"v1"}},
+	})
+}
+
+func crdVersion(name string, served, storage, status bool) map[string]interface{} {
+	version := map[string]interface{}{"name": name, "served": served, "storage": storage}
+	if status {
+		version["subresources"] = map[string]interface{}{"status": map[string]interface{}{}}
+	}
+	return version
+}
+
+func TestGetCRDs(t *testing.T) {
+	client := newTestClient()
+	listKinds := testListKinds()
+	listKinds[crdGVR] = "CustomResourceDefinitionList"
+	client.dynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		newTestCRD("stable.example.com", "Widget", "widgets", "Namespaced",
+			crdVersion("v1", true, true, true), crdVersion("v1beta1", false, false, false)),
+		newTestCRD("cert-manager.io", "ClusterIssuer", "clusterissuers", "Cluster",
+			crdVersion("v1", true, true, false)),
+	)
+
+	crds, err := client.GetCRDs(context.Background())
+	if err != nil {
+		t.Fatalf("GetCRDs returned error: %v", err)
+	}
+	if len(crds) != 2 || crds[0].Name != "clusterissuers.cert-manager.io" || crds[1].Name != "widgets.stable.example.com" {
+		t.Fatalf("expected both CRDs sorted by name, got %+v", crds)
+	}
+
+	issuer := crds[0]
+	if issuer.Group != "cert-manager.io" || issuer.Kind != "ClusterIssuer" || issuer.Scope != "Cluster" || issuer.HasStatus {
+		t.Errorf("unexpected ClusterIssuer CRD %+v", issuer)
+	}
+
+	widget := crds[1]
+	if widget.Kind != "Widget" || widget.Plural != "widgets" || widget.Scope != "Namespaced" || !widget.HasStatus {
+		t.Errorf("unexpected Widget CRD %+v", widget)
+	}
+	if len(widget.Versions) != 2 {
+		t.Fatalf("expected 2 Widget versions, got %+v", widget.Versions)
+	}
+	if v := widget.Versions[0]; v.Name != "v1" || !v.Served || !v.Storage || !v.HasStatus {
+		t.Errorf("unexpected v1 %+v", v)
+	}
+	if v := widget.Versions[1]; v.Name != "v1beta1" || v.Served || v.Storage || v.HasStatus {
+		t.Errorf("unexpected v1beta1 %+v", v)
+	}
+	if len(widget.StoredVersions) != 1 || widget.StoredVersions[0] != "v1" {
+		t.Errorf("expected stored versions [v1], got %v", widget.StoredVersions)
+	}
+
+	if _, err := (&Client{}).GetCRDs(context.Background()); !errors.Is(err, ErrNoClient) {
+		t.Errorf("expected ErrNoClient without a dynamic client, got %v", err)
+	}
+}
@@ -0,0 +1,258 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change in the unified diff
+const diffContextLines = 3
+
+// ObjectPath identifies a namespaced object by namespace, resource and name
+type ObjectPath struct {
+	Namespace string `json:"namespace"`
+	Resource  string `json:"resource"`
+	Name      string `json:"name"`
+}
+
+func (p ObjectPath) String() string {
+	return p.Namespace + "/" + p.Resource + "/" + p.Name
+}
+
+// DiffChange is a single field that differs between two objects
+type DiffChange struct {
+	Path string      `json:"path"`
+	Type string      `json:"type"` // added, removed or changed
+	A    interface{} `json:"a,omitempty"`
+	B    interface{} `json:"b,omitempty"`
+}
+
+// ObjectDiff is the difference between two objects, both as a list of changed fields and as a
+// unified diff of their YAML
+type ObjectDiff struct {
+	A         ObjectPath   `json:"a"`
+	B         ObjectPath   `json:"b"`
+	Identical bool         `json:"identical"`
+	Changes   []DiffChange `json:"changes"`
+	Unified   string       `json:"unified"`
+}
+
+// volatileMetadataFields are set by the API server and differ between any two objects, so they are
+// left out of diffs
+var volatileMetadataFields = []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation", "selfLink"}
+
+// DiffObjects fetches two objects and compares them, ignoring server-populated metadata and status
+func (c *Client) DiffObjects(ctx context.Context, a, b ObjectPath) (*ObjectDiff, error) {
+	objectA, err := c.GetRawResourceObject(ctx, a.Namespace, a.Resource, a.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", a, err)
+	}
+	objectB, err := c.GetRawResourceObject(ctx, b.Namespace, b.Resource, b.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b, err)
+	}
+
+	diff, err := diffObjects(objectA, objectB, "a/"+a.String(), "b/"+b.String())
+	if err != nil {
+		return nil, err
+	}
+	diff.A = a
+	diff.B = b
+	return diff, nil
+}
+
+// diffObjects compares two raw objects after stripping their volatile fields. Both are rendered as
+// YAML with sorted keys, so key order never shows up as a change.
+func diffObjects(a, b map[string]interface{}, labelA, labelB string) (*ObjectDiff, error) {
+	stripVolatileFields(a)
+	stripVolatileFields(b)
+
+	yamlA, err := yaml.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s as YAML: %w", labelA, err)
+	}
+	yamlB, err := yaml.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s as YAML: %w", labelB, err)
+	}
+
+	changes := []DiffChange{}
+	collectChanges("", a, b, &changes)
+
+	return &ObjectDiff{
+		Identical: len(changes) == 0,
+		Changes:   changes,
+		Unified:   unifiedDiff(string(yamlA), string(yamlB), labelA, labelB),
+	}, nil
+}
+
+// stripVolatileFields removes status and the metadata the API server maintains
+func stripVolatileFields(object map[string]interface{}) {
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		for _, field := range volatileMetadataFields {
+			delete(metadata, field)
+		}
+	}
+}
+
+// collectChanges walks two values in parallel and records every leaf that differs. Maps are walked
+// in key order and lists by index; a value whose type differs is recorded as a single change.
+func collectChanges(path string, a, b interface{}, changes *[]DiffChange) {
+	mapA, aIsMap := a.(map[string]interface{})
+	mapB, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := make(map[string]bool)
+		for key := range mapA {
+			keys[key] = true
+		}
+		for key := range mapB {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			valueA, inA := mapA[key]
+			valueB, inB := mapB[key]
+			switch {
+			case !inA:
+				*changes = append(*changes, DiffChange{Path: childPath, Type: "added", B: valueB})
+			case !inB:
+				*changes = append(*changes, DiffChange{Path: childPath, Type: "removed", A: valueA})
+			default:
+				collectChanges(childPath, valueA, valueB, changes)
+			}
+		}
+		return
+	}
+
+	listA, aIsList := a.([]interface{})
+	listB, bIsList := b.([]interface{})
+	if aIsList && bIsList {
+		for i := 0; i < len(listA) || i < len(listB); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(listA):
+				*changes = append(*changes, DiffChange{Path: childPath, Type: "added", B: listB[i]})
+			case i >= len(listB):
+				*changes = append(*changes, DiffChange{Path: childPath, Type: "removed", A: listA[i]})
+			default:
+				collectChanges(childPath, listA[i], listB[i], changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, DiffChange{Path: path, Type: "changed", A: a, B: b})
+	}
+}
+
+// unifiedDiff returns a unified diff of two texts, or an empty string when they are equal
+func unifiedDiff(a, b, labelA, labelB string) string {
+	if a == b {
+		return ""
+	}
+	linesA := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	linesB := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// Longest common subsequence table; lcs[i][j] covers linesA[i:] and linesB[j:]
+	lcs := make([][]int, len(linesA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(linesB)+1)
+	}
+	for i := len(linesA) - 1; i >= 0; i-- {
+		for j := len(linesB) - 1; j >= 0; j-- {
+			if linesA[i] == linesB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Edit script: ' ' keeps a line, '-' removes a line of a, '+' adds a line of b
+	type edit struct {
+		op   byte
+		line string
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(linesA) || j < len(linesB) {
+		switch {
+		case i < len(linesA) && j < len(linesB) && linesA[i] == linesB[j]:
+			edits = append(edits, edit{' ', linesA[i]})
+			i++
+			j++
+		case i < len(linesA) && (j == len(linesB) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', linesA[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', linesB[j]})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", labelA, labelB)
+
+	// Group changes that are close together into hunks with surrounding context
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			start++
+			continue
+		}
+		hunkStart := max(start-diffContextLines, 0)
+		end := start
+		for k := start; k < len(edits); k++ {
+			if edits[k].op != ' ' {
+				end = k
+			} else if k-end > 2*diffContextLines {
+				break
+			}
+		}
+		hunkEnd := min(end+diffContextLines+1, len(edits))
+
+		// Line numbers of the hunk start in a and b are the lines consumed before it, plus one
+		lineA, lineB := 1, 1
+		for _, e := range edits[:hunkStart] {
+			if e.op != '+' {
+				lineA++
+			}
+			if e.op != '-' {
+				lineB++
+			}
+		}
+		countA, countB := 0, 0
+		for _, e := range edits[hunkStart:hunkEnd] {
+			if e.op != '+' {
+				countA++
+			}
+			if e.op != '-' {
+				countB++
+			}
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", lineA, countA, lineB, countB)
+		for _, e := range edits[hunkStart:hunkEnd] {
+			out.WriteByte(e.op)
+			out.WriteString(e.line)
+			out.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+	return out.String()
+}
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func newDiffTestDeployment(image string, replicas int64) map[string]interface{} {
+	return map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "web", "image": image}},
+				},
+			},
+		},
+		"status": map[string]interface{}{"readyReplicas": replicas},
+	}
+}
+
+func TestDiffObjectsIdentical(t *testing.T) {
+	a := newTestObject("apps/v1", "Deployment", "staging", "web", newDiffTestDeployment("nginx:1.25", 2))
+	a.SetResourceVersion("100")
+	a.SetUID("uid-a")
+	b := newTestObject("apps/v1", "Deployment", "staging", "web-copy", newDiffTestDeployment("nginx:1.25", 2))
+	b.SetResourceVersion("200")
+	b.SetUID("uid-b")
+	b.Object["status"] = map[string]interface{}{"readyReplicas": int64(0)}
+
+	diff, err := diffObjects(a.Object, b.Object, "a", "b")
+	if err != nil {
+		t.Fatalf("diffObjects returned error: %v", err)
+	}
+	// Only the name differs once volatile metadata and status are stripped
+	if len(diff.Changes) != 1 || diff.Changes[0].Path != "metadata.name" {
+		t.Errorf("expected only metadata.name to differ, got %+v", diff.Changes)
+	}
+
+	b.SetName("web")
+	diff, err = diffObjects(a.Object, b.Object, "a", "b")
+	if err != nil {
+		t.Fatalf("diffObjects returned error: %v", err)
+	}
+	if !diff.Identical || len(diff.Changes) != 0 || diff.Unified != "" {
+		t.Errorf("expected an empty diff, got %+v", diff)
+	}
+}
+
+func TestDiffObjectsDifferentSpecs(t *testing.T) {
+	client := newTestClient(
+		newTestObject("apps/v1", "Deployment", "staging", "web", newDiffTestDeployment("nginx:1.25", 2)),
+		newTestObject("apps/v1", "Deployment", "production", "web", newDiffTestDeployment("nginx:1.24", 5)),
+	)
+
+	diff, err := client.DiffObjects(context.Background(),
+		ObjectPath{Namespace: "staging", Resource: "deployments", Name: "web"},
+		ObjectPath{Namespace: "production", Resource: "deployments", Name: "web"},
+	)
+	if err != nil {
+		t.Fatalf("DiffObjects returned error: %v", err)
+	}
+	if diff.Identical {
+		t.Fatal("expected the objects to differ")
+	}
+
+	changed := make(map[string]DiffChange)
+	for _, change := range diff.Changes {
+		changed[change.Path] = change
+	}
+	want := []string{"metadata.namespace", "spec.replicas", "spec.template.spec.containers[0].image"}
+	if len(diff.Changes) != len(want) {
+		t.Errorf("expected %d changes, got %+v", len(want), diff.Changes)
+	}
+	for _, path := range want {
+		if change, found := changed[path]; !found || change.Type != "changed" {
+			t.Errorf("expected %s to be changed, got %+v", path, change)
+		}
+	}
+	if image := changed["spec.template.spec.containers[0].image"]; image.A != "nginx:1.25" || image.B != "nginx:1.24" {
+		t.Errorf("unexpected image change %+v", image)
+	}
+
+	for _, line := range []string{
+		"--- a/staging/deployments/web",
+		"+++ b/production/deployments/web",
+		"-  namespace: staging",
+		"+  namespace: production",
+		"-  replicas: 2",
+		"+  replicas: 5",
+		"-      - image: nginx:1.25",
+		"+      - image: nginx:1.24",
+	} {
+		if !strings.Contains(diff.Unified, line+"\n") {
+			t.Errorf("expected unified diff to contain %q:\n%s", line, diff.Unified)
+		}
+	}
+}
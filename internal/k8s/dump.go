@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DumpOptions control what GetNamespaceDump includes
+type DumpOptions struct {
+	// Compact leaves spec, status and annotations out of the objects
+	Compact bool
+	// OnlyPopulated leaves out resource types without objects
+	OnlyPopulated bool
+	// MaxObjects caps the total number of objects in the dump, 0 for no cap
+	MaxObjects int
+}
+
+// ResourceDump is one resource type of a namespace with its objects
+type ResourceDump struct {
+	Resource ResourceInfo `json:"resource"`
+	Objects  []ObjectInfo `json:"objects"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// NamespaceDump is the resources of a namespace with their objects inline
+type NamespaceDump struct {
+	Namespace    string         `json:"namespace"`
+	Resources    []ResourceDump `json:"resources"`
+	TotalObjects int            `json:"totalObjects"`
+	// Truncated is set when MaxObjects cut objects from the dump
+	Truncated bool `json:"truncated"`
+}
+
+// GetNamespaceDump returns the resources of a namespace together with their objects, so a client can
+// render the whole namespace from one response. Objects are listed concurrently, bounded by
+// countConcurrency; resource types whose objects cannot be listed carry the error instead.
+func (c *Client) GetNamespaceDump(ctx context.Context, namespace string, options DumpOptions) (*NamespaceDump, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	resources, err := c.GetResourcesInNamespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	dump := &NamespaceDump{Namespace: namespace, Resources: []ResourceDump{}}
+	for _, resource := range resources {
+		if options.OnlyPopulated && resource.Count == 0 {
+			continue
+		}
+		dump.Resources = append(dump.Resources, ResourceDump{Resource: resource, Objects: []ObjectInfo{}})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	workers := c.countConcurrency
+	if workers > len(dump.Resources) {
+		workers = len(dump.Resources)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				entry := &dump.Resources[i]
+				// Counting found nothing, so there is nothing to list
+				if entry.Resource.Count == 0 {
+					continue
+				}
+
+				list, err := c.listAllPages(ctx, c.resourceClient(namespace, entry.Resource), metav1.ListOptions{}, 0)
+				if err != nil {
+					entry.Error = wrapAPIError(err).Error()
+					continue
+				}
+				for j := range list.Items {
+					object := toObjectInfo(&list.Items[j])
+					if options.Compact {
+						object.Spec, object.Status, object.Annotations = nil, nil, nil
+					}
+					entry.Objects = append(entry.Objects, object)
+				}
+			}
+		}()
+	}
+
+	for i := range dump.Resources {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Apply the cap in resource order so the result does not depend on which list finished first
+	for i := range dump.Resources {
+		entry := &dump.Resources[i]
+		if options.MaxObjects > 0 && dump.TotalObjects+len(entry.Objects) > options.MaxObjects {
+			entry.Objects = entry.Objects[:options.MaxObjects-dump.TotalObjects]
+			dump.Truncated = true
+		}
+		dump.TotalObjects += len(entry.Objects)
+	}
+
+	return dump, nil
+}
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetNamespaceDump(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", map[string]interface{}{"spec": map[string]interface{}{"nodeName": "node-a"}}),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+		newTestObject("v1", "ConfigMap", "default", "settings", nil),
+		newTestObject("v1", "Pod", "other", "elsewhere", nil),
+	)
+
+	dump, err := client.GetNamespaceDump(context.Background(), "default", DumpOptions{OnlyPopulated: true})
+	if err != nil {
+		t.Fatalf("GetNamespaceDump returned error: %v", err)
+	}
+
+	objects := make(map[string][]ObjectInfo)
+	for _, entry := range dump.Resources {
+		if entry.Resource.Count == 0 {
+			t.Errorf("onlyPopulated returned empty resource %s", entry.Resource.FullName)
+		}
+		if entry.Resource.Count != len(entry.Objects) {
+			t.Errorf("%s: count %d does not match %d inline objects", entry.Resource.FullName, entry.Resource.Count, len(entry.Objects))
+		}
+		objects[entry.Resource.Name] = entry.Objects
+	}
+	if len(objects) != 2 || len(objects["pods"]) != 2 || len(objects["configmaps"]) != 1 {
+		t.Fatalf("expected 2 pods and 1 configmap, got %+v", objects)
+	}
+	if dump.TotalObjects != 3 || dump.Truncated {
+		t.Errorf("expected 3 objects without truncation, got %d (truncated=%t)", dump.TotalObjects, dump.Truncated)
+	}
+	for _, pod := range objects["pods"] {
+		if pod.Name == "web-1" && pod.Spec["nodeName"] != "node-a" {
+			t.Errorf("expected full objects to keep the spec, got %+v", pod.Spec)
+		}
+	}
+}
+
+func TestGetNamespaceDumpCompactAndCapped(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", map[string]interface{}{"spec": map[string]interface{}{"nodeName": "node-a"}}),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+		newTestObject("v1", "ConfigMap", "default", "settings", nil),
+	)
+
+	dump, err := client.GetNamespaceDump(context.Background(), "default", DumpOptions{Compact: true, MaxObjects: 2})
+	if err != nil {
+		t.Fatalf("GetNamespaceDump returned error: %v", err)
+	}
+
+	if dump.TotalObjects != 2 || !dump.Truncated {
+		t.Errorf("expected 2 objects and truncation, got %d (truncated=%t)", dump.TotalObjects, dump.Truncated)
+	}
+	empty := 0
+	for _, entry := range dump.Resources {
+		if entry.Resource.Count == 0 {
+			empty++
+		}
+		for _, object := range entry.Objects {
+			if object.Spec != nil || object.Status != nil {
+				t.Errorf("compact dump kept spec or status of %s/%s", entry.Resource.Name, object.Name)
+			}
+			if object.Name == "" || object.Kind == "" {
+				t.Errorf("compact dump lost identifying fields: %+v", object)
+			}
+		}
+	}
+	if empty == 0 {
+		t.Error("expected empty resource types without onlyPopulated")
+	}
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Errors returned by Client methods. They wrap the underlying API error where there is one,
+// so callers can use errors.Is for the category and errors.As / apierrors helpers for details.
+var (
+	ErrNoClient         = errors.New("no kubernetes client available")
+	ErrResourceNotFound = errors.New("resource not found")
+	ErrForbidden        = errors.New("forbidden")
+	ErrTimeout          = errors.New("request timed out")
+	ErrInvalidArgument  = errors.New("invalid argument")
+
+	// ErrWritesDisabled is returned by write operations unless ALLOW_WRITES is enabled
+	ErrWritesDisabled = fmt.Errorf("%w: writes are disabled, set ALLOW_WRITES=true", ErrForbidden)
+)
+
+// AmbiguousResourceError is returned when a bare resource name matches resources in several API groups
+type AmbiguousResourceError struct {
+	Identifier string
+	Candidates []string // FullNames that match the identifier
+}
+
+func (e *AmbiguousResourceError) Error() string {
+	return fmt.Sprintf("resource %s is ambiguous, use one of: %s", e.Identifier, strings.Join(e.Candidates, ", "))
+}
+
+// ConfigError is returned by NewClient when neither a kubeconfig nor the in-cluster config can be loaded
+type ConfigError struct {
+	Kubeconfig error // why loading the kubeconfig failed
+	InCluster  error // why the in-cluster config is unavailable
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("failed to create kubernetes config: %v", e.Kubeconfig)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Kubeconfig
+}
+
+// Reason summarizes the error without paths or server details, for showing to users
+func (e *ConfigError) Reason() string {
+	var kubeconfig string
+	switch {
+	case clientcmd.IsEmptyConfig(e.Kubeconfig), errors.Is(e.Kubeconfig, fs.ErrNotExist):
+		kubeconfig = "kubeconfig not found"
+	case clientcmd.IsConfigurationInvalid(e.Kubeconfig):
+		kubeconfig = "kubeconfig invalid"
+	default:
+		kubeconfig = "kubeconfig could not be loaded"
+	}
+
+	inCluster := "in-cluster config unavailable"
+	if errors.Is(e.InCluster, rest.ErrNotInCluster) {
+		inCluster = "not running in a cluster"
+	}
+	return kubeconfig + ", and " + inCluster
+}
+
+// TooManyObjectsError is returned when listing all objects of a resource would exceed MAX_OBJECTS
+type TooManyObjectsError struct {
+	Resource string
+	Count    int // objects of the resource, at least Limit+1 when the total could not be counted
+	Limit    int
+}
+
+func (e *TooManyObjectsError) Error() string {
+	return fmt.Sprintf("%s has %d objects, more than MAX_OBJECTS (%d); narrow the listing with a label or field selector, or paginate with limit and continue",
+		e.Resource, e.Count, e.Limit)
+}
+
+// ContainerError is returned for pod logs when the container is missing for a multi-container
+// pod or names no container of the pod
+type ContainerError struct {
+	Pod        string
+	Container  string   // requested container, empty when none was given
+	Containers []string // init containers and containers of the pod
+}
+
+func (e *ContainerError) Error() string {
+	if e.Container == "" {
+		return fmt.Sprintf("pod %s has several containers, choose one of: %s", e.Pod, strings.Join(e.Containers, ", "))
+	}
+	return fmt.Sprintf("pod %s has no container %s, choose one of: %s", e.Pod, e.Container, strings.Join(e.Containers, ", "))
+}
+
+// wrapAPIError classifies an error from the Kubernetes API into one of the typed errors
+func wrapAPIError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNoClient), errors.Is(err, ErrResourceNotFound),
+		errors.Is(err, ErrForbidden), errors.Is(err, ErrTimeout), errors.Is(err, ErrInvalidArgument):
+		return err
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("%w: %w", ErrResourceNotFound, err)
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return fmt.Errorf("%w: %w", ErrForbidden, err)
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case apierrors.IsBadRequest(err), apierrors.IsInvalid(err):
+		// e.g. a field selector the resource does not support
+		return fmt.Errorf("%w: %w", ErrInvalidArgument, err)
+	default:
+		return err
+	}
+}
+
+// isPermissionError reports errors that are expected while counting, such as resources
+// the user may not list or resources that do not support the list verb
+func isPermissionError(err error) bool {
+	return errors.Is(err, ErrForbidden) || apierrors.IsForbidden(err) || apierrors.IsMethodNotSupported(err)
+}
+
+// isResourceExpired reports 410 Gone errors, returned when a requested resourceVersion or
+// continue token refers to a snapshot that has been compacted away
+func isResourceExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// watchCacheRejected reports errors of a resourceVersion=0 read that a quorum read may not hit, such
+// as aggregated APIs refusing the resourceVersion
+func watchCacheRejected(err error) bool {
+	return apierrors.IsBadRequest(err) || apierrors.IsInvalid(err) || isResourceExpired(err)
+}
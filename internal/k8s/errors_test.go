@@ -0,0 +1,115 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestTypedErrorNoClient(t *testing.T) {
+	client := &Client{}
+
+	if _, err := client.GetNamespaces(context.Background()); !errors.Is(err, ErrNoClient) {
+		t.Errorf("GetNamespaces: expected ErrNoClient, got %v", err)
+	}
+	if _, err := client.GetResourceObjects(context.Background(), "default", "pods"); !errors.Is(err, ErrNoClient) {
+		t.Errorf("GetResourceObjects: expected ErrNoClient, got %v", err)
+	}
+}
+
+func TestTypedErrorResourceNotFound(t *testing.T) {
+	client := newTestClient()
+
+	_, err := client.GetResourceObjects(context.Background(), "default", "doesnotexist")
+	if !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("unknown resource type: expected ErrResourceNotFound, got %v", err)
+	}
+
+	_, err = client.GetResourceObject(context.Background(), "default", "pods", "missing")
+	if !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("missing object: expected ErrResourceNotFound, got %v", err)
+	}
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("missing object: expected wrapped API NotFound error, got %v", err)
+	}
+}
+
+func TestTypedErrorForbidden(t *testing.T) {
+	client := newTestClient()
+	podsGR := schema.GroupResource{Resource: "pods"}
+	client.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("list", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(podsGR, "", errors.New("rbac"))
+		})
+
+	_, err := client.GetResourceObjects(context.Background(), "default", "pods")
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+	if !apierrors.IsForbidden(err) {
+		t.Errorf("expected wrapped API Forbidden error, got %v", err)
+	}
+
+	// Forbidden resources are expected during counting and must not fail the namespace scan
+	resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	for _, resource := range resources {
+		if resource.Name == "pods" && resource.Count != 0 {
+			t.Errorf("expected forbidden pods count to be 0, got %d", resource.Count)
+		}
+	}
+}
+
+func TestTypedErrorTimeout(t *testing.T) {
+	err := wrapAPIError(context.DeadlineExceeded)
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("deadline exceeded: expected ErrTimeout wrapping the context error, got %v", err)
+	}
+
+	err = wrapAPIError(apierrors.NewTimeoutError("slow", 3))
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("server timeout: expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestConfigErrorReason(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid")
+	if err := os.WriteFile(invalid, []byte("apiVersion: v1\nkind: Config\ncurrent-context: missing\n"), 0o600); err != nil {
+		t.Fatalf("writing kubeconfig: %v", err)
+	}
+
+	tests := []struct {
+		kubeconfig string
+		want       string
+	}{
+		{filepath.Join(dir, "missing"), "kubeconfig not found, and not running in a cluster"},
+		{invalid, "kubeconfig invalid, and not running in a cluster"},
+	}
+	for _, tt := range tests {
+		_, err := NewClient(tt.kubeconfig)
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) {
+			t.Errorf("%s: expected a ConfigError, got %v", tt.kubeconfig, err)
+			continue
+		}
+		if reason := configErr.Reason(); reason != tt.want {
+			t.Errorf("%s: expected reason %q, got %q", tt.kubeconfig, tt.want, reason)
+		}
+		if strings.Contains(configErr.Reason(), dir) {
+			t.Errorf("%s: expected the reason to leave out paths", tt.kubeconfig)
+		}
+	}
+}
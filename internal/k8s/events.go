@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// EventInfo is an Event recorded for an object
+type EventInfo struct {
+	Type          string    `json:"type"`
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	LastTimestamp time.Time `json:"lastTimestamp"`
+}
+
+// ResolveResource looks up a discovered resource by FullName, or by a Name that is unique across API groups
+func (c *Client) ResolveResource(resourceIdentifier string) (*ResourceInfo, error) {
+	return c.findResource(resourceIdentifier, false)
+}
+
+// GetObjectEvents returns the Events of an object, most recent first
+func (c *Client) GetObjectEvents(ctx context.Context, namespace, kind, name string) ([]EventInfo, error) {
+	if c.clientset == nil {
+		return nil, fmt.Errorf("%w: no clientset", ErrNoClient)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	selector := fields.Set{"involvedObject.name": name, "involvedObject.kind": kind}.AsSelector().String()
+	list, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	events := []EventInfo{}
+	for _, event := range list.Items {
+		// The field selector already filters server-side; this guards against servers that ignore it
+		if event.InvolvedObject.Name != name || event.InvolvedObject.Kind != kind {
+			continue
+		}
+		events = append(events, EventInfo{
+			Type:          event.Type,
+			Reason:        event.Reason,
+			Message:       event.Message,
+			Count:         eventCount(event),
+			LastTimestamp: eventLastTimestamp(event),
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.After(events[j].LastTimestamp)
+	})
+	return events, nil
+}
+
+// eventCount returns how often an event occurred; events recorded through events.k8s.io keep it in the series
+func eventCount(event corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return event.Series.Count
+	}
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
+
+// eventLastTimestamp returns when an event last occurred, falling back through the fields that
+// events.k8s.io and older recorders fill in
+func eventLastTimestamp(event corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
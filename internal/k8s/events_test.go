@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestEvent(name, kind, objectName, reason string, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: kind, Name: objectName, Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        reason + " for " + objectName,
+		Count:          2,
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+}
+
+func TestGetObjectEvents(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	client := newTestClient()
+	client.clientset = fake.NewSimpleClientset(
+		newTestEvent("web-1.a", "Pod", "web-1", "BackOff", now.Add(-10*time.Minute)),
+		newTestEvent("web-1.b", "Pod", "web-1", "Unhealthy", now.Add(-time.Minute)),
+		newTestEvent("web-2.a", "Pod", "web-2", "BackOff", now),
+		newTestEvent("web-1.c", "Deployment", "web-1", "ScalingReplicaSet", now),
+	)
+
+	events, err := client.GetObjectEvents(context.Background(), "default", "Pod", "web-1")
+	if err != nil {
+		t.Fatalf("GetObjectEvents returned error: %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected the 2 events of Pod web-1, got %+v", events)
+	}
+	if events[0].Reason != "Unhealthy" || events[1].Reason != "BackOff" {
+		t.Errorf("expected most recent first, got %s then %s", events[0].Reason, events[1].Reason)
+	}
+	if events[0].Type != corev1.EventTypeWarning || events[0].Count != 2 || !events[0].LastTimestamp.Equal(now.Add(-time.Minute)) {
+		t.Errorf("unexpected event fields: %+v", events[0])
+	}
+}
+
+func TestGetObjectEventsNone(t *testing.T) {
+	client := newTestClient()
+
+	events, err := client.GetObjectEvents(context.Background(), "default", "Pod", "web-1")
+	if err != nil {
+		t.Fatalf("GetObjectEvents returned error: %v", err)
+	}
+	if events == nil || len(events) != 0 {
+		t.Errorf("expected an empty list, got %#v", events)
+	}
+}
@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a field path: a map key, or an array index when index >= 0
+type pathSegment struct {
+	key   string
+	index int
+}
+
+// LookupFieldPath returns the subtree of object at a dotted field path such as
+// spec.template.spec.containers[0].image. A leading dot is optional, and brackets also take map
+// keys that contain dots, as in metadata.labels[app.kubernetes.io/name]. It returns
+// ErrInvalidArgument for a malformed path and ErrResourceNotFound when the path does not exist.
+func LookupFieldPath(object map[string]interface{}, path string) (interface{}, error) {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: path %q: %v", ErrInvalidArgument, path, err)
+	}
+
+	var current interface{} = object
+	walked := ""
+	for _, segment := range segments {
+		if segment.index >= 0 {
+			items, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: %s is not an array", ErrResourceNotFound, describePath(walked))
+			}
+			if segment.index >= len(items) {
+				return nil, fmt.Errorf("%w: %s has %d items, no index %d", ErrResourceNotFound, describePath(walked), len(items), segment.index)
+			}
+			current = items[segment.index]
+			walked += "[" + strconv.Itoa(segment.index) + "]"
+			continue
+		}
+
+		fields, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an object", ErrResourceNotFound, describePath(walked))
+		}
+		value, exists := fields[segment.key]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s has no field %q", ErrResourceNotFound, describePath(walked), segment.key)
+		}
+		current = value
+		if walked != "" {
+			walked += "."
+		}
+		walked += segment.key
+	}
+	return current, nil
+}
+
+// describePath names a walked path in error messages
+func describePath(walked string) string {
+	if walked == "" {
+		return "the object"
+	}
+	return walked
+}
+
+// parseFieldPath splits a field path into its segments
+func parseFieldPath(path string) ([]pathSegment, error) {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	var segments []pathSegment
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ at position %d", i)
+			}
+			inner := path[i+1 : i+end]
+			if inner == "" {
+				return nil, fmt.Errorf("empty [] at position %d", i)
+			}
+			if index, err := strconv.Atoi(inner); err == nil {
+				if index < 0 {
+					return nil, fmt.Errorf("negative index %d", index)
+				}
+				segments = append(segments, pathSegment{index: index})
+			} else {
+				segments = append(segments, pathSegment{key: strings.Trim(inner, `'"`), index: -1})
+			}
+			i += end + 1
+			// A bracket is followed by another bracket, a dot, or the end of the path
+			if i < len(path) && path[i] == '.' {
+				i++
+				if i == len(path) {
+					return nil, fmt.Errorf("path ends with a dot")
+				}
+			}
+		case '.':
+			return nil, fmt.Errorf("empty field name at position %d", i)
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			segments = append(segments, pathSegment{key: path[i : i+end], index: -1})
+			i += end
+			if i < len(path) && path[i] == '.' {
+				i++
+				if i == len(path) {
+					return nil, fmt.Errorf("path ends with a dot")
+				}
+			}
+		}
+	}
+	return segments, nil
+}
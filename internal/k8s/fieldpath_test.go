@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLookupFieldPath(t *testing.T) {
+	container := map[string]interface{}{"name": "app", "image": "nginx:1.25"}
+	object := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":   "web",
+			"labels": map[string]interface{}{"app.kubernetes.io/name": "web"},
+		},
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{container, map[string]interface{}{"name": "proxy"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"metadata.name", "web"},
+		{".metadata.name", "web"},
+		{"spec.template.spec", object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"]},
+		{"spec.template.spec.containers[0]", container},
+		{"spec.template.spec.containers[0].image", "nginx:1.25"},
+		{"spec.template.spec.containers[1].name", "proxy"},
+		{"metadata.labels[app.kubernetes.io/name]", "web"},
+		{"metadata.labels['app.kubernetes.io/name']", "web"},
+	}
+	for _, tt := range tests {
+		value, err := LookupFieldPath(object, tt.path)
+		if err != nil {
+			t.Errorf("LookupFieldPath(%q) returned error: %v", tt.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, tt.want) {
+			t.Errorf("LookupFieldPath(%q) = %v, want %v", tt.path, value, tt.want)
+		}
+	}
+
+	for _, path := range []string{
+		"spec.replicas",
+		"spec.template.spec.containers[2]",
+		"spec.template.spec.containers.name",
+		"metadata.name[0]",
+		"metadata.labels[app]",
+	} {
+		if _, err := LookupFieldPath(object, path); !errors.Is(err, ErrResourceNotFound) {
+			t.Errorf("LookupFieldPath(%q): expected ErrResourceNotFound, got %v", path, err)
+		}
+	}
+
+	for _, path := range []string{"", ".", "spec..template", "spec.", "spec.containers[", "spec.containers[]", "spec.containers[-1]"} {
+		if _, err := LookupFieldPath(object, path); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("LookupFieldPath(%q): expected ErrInvalidArgument, got %v", path, err)
+		}
+	}
+}
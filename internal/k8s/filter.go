@@ -0,0 +1,56 @@
+package k8s
+
+import "sort"
+
+// ResourceFilters are the resource types configured to be counted (INCLUDE_RESOURCES) or left out
+// (EXCLUDE_RESOURCES), and the types that are never counted (Skip), by Name or FullName
+type ResourceFilters struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+	Skip    []string `json:"skip"`
+}
+
+// ResourceFilters returns the active include, exclude and skip lists, sorted
+func (c *Client) ResourceFilters() ResourceFilters {
+	return ResourceFilters{
+		Include: sortedKeys(c.includeResources),
+		Exclude: sortedKeys(c.excludeResources),
+		Skip:    sortedKeys(c.skipResources),
+	}
+}
+
+// skipResourceSet returns defaultSkipResources with the resources of EXTRA_SKIP_RESOURCES added
+func skipResourceSet() map[string]bool {
+	skip := envSet("EXTRA_SKIP_RESOURCES", "")
+	for name := range defaultSkipResources {
+		skip[name] = true
+	}
+	return skip
+}
+
+// countable reports whether a resource type is counted: it is not skipped and passes
+// INCLUDE_RESOURCES and EXCLUDE_RESOURCES. Every counting path filters with it.
+func (c *Client) countable(resource ResourceInfo) bool {
+	if resource.Subresource || c.skipResources[resource.Name] || c.skipResources[resource.FullName] {
+		return false
+	}
+	return c.resourceAllowed(resource)
+}
+
+// resourceAllowed applies INCLUDE_RESOURCES and EXCLUDE_RESOURCES. When an include list is set
+// only the listed resources are allowed and the exclude list is ignored.
+func (c *Client) resourceAllowed(resource ResourceInfo) bool {
+	if len(c.includeResources) > 0 {
+		return c.includeResources[resource.Name] || c.includeResources[resource.FullName]
+	}
+	return !c.excludeResources[resource.Name] && !c.excludeResources[resource.FullName]
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
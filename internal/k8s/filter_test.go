@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// countedResources returns the FullNames of the resources counted for namespace, sorted
+func countedResources(t *testing.T, client *Client, namespace string) []string {
+	t.Helper()
+	resources, err := client.GetResourcesInNamespace(context.Background(), namespace)
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	var names []string
+	for _, resource := range resources {
+		names = append(names, resource.FullName)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestResourceFilters(t *testing.T) {
+	tests := []struct {
+		name             string
+		include, exclude string
+		want             []string
+	}{
+		{
+			name:    "include only",
+			include: "pods, deployments.apps",
+			want:    []string{"deployments.apps", "pods"},
+		},
+		{
+			name:    "exclude only",
+			exclude: "events,widgets,replicasets.apps",
+			want:    []string{"configmaps", "deployments.apps", "persistentvolumeclaims", "pods", "secrets", "services", "statefulsets.apps"},
+		},
+		{
+			name:    "include wins over exclude",
+			include: "pods,configmaps",
+			exclude: "pods,secrets",
+			want:    []string{"configmaps", "pods"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INCLUDE_RESOURCES", tt.include)
+			t.Setenv("EXCLUDE_RESOURCES", tt.exclude)
+			client := newTestClient()
+
+			if got := countedResources(t, client, "default"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResourceFiltersReported(t *testing.T) {
+	t.Setenv("INCLUDE_RESOURCES", "pods,deployments.apps")
+	t.Setenv("EXCLUDE_RESOURCES", "")
+	client := newTestClient()
+
+	filters := client.ResourceFilters()
+	if !reflect.DeepEqual(filters.Include, []string{"deployments.apps", "pods"}) || len(filters.Exclude) != 0 {
+		t.Errorf("unexpected filters %+v", filters)
+	}
+}
+
+func TestSkipResourcesSharedByCountPaths(t *testing.T) {
+	t.Setenv("EXTRA_SKIP_RESOURCES", "events.events.k8s.io, secrets")
+
+	// Serve bindings like a real cluster does; it is create-only and skipped by default
+	resources := append([]*metav1.APIResourceList{}, testAPIResources...)
+	resources = append(resources, &metav1.APIResourceList{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: []string{"create"}}},
+	})
+	newClient := func() *Client {
+		client := newTestClient()
+		client.discoveryClient.(*testDiscovery).Resources = resources
+		return client
+	}
+
+	counted := countedResources(t, newClient(), "default")
+
+	var streamed []string
+	withCallback, err := newClient().GetResourcesInNamespaceWithCallback(context.Background(), "default", func(string) {})
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespaceWithCallback returned error: %v", err)
+	}
+	for _, resource := range withCallback {
+		streamed = append(streamed, resource.FullName)
+	}
+	sort.Strings(streamed)
+
+	if !reflect.DeepEqual(counted, streamed) {
+		t.Errorf("count paths disagree:\n  GetResourcesInNamespace:             %v\n  GetResourcesInNamespaceWithCallback: %v", counted, streamed)
+	}
+	for _, name := range counted {
+		if name == "bindings" || name == "secrets" || name == "events.events.k8s.io" {
+			t.Errorf("%s should be skipped", name)
+		}
+	}
+
+	skip := newClient().ResourceFilters().Skip
+	if !reflect.DeepEqual(skip[:2], []string{"bindings", "events.events.k8s.io"}) {
+		t.Errorf("expected defaults and EXTRA_SKIP_RESOURCES reported, got %v", skip)
+	}
+}
@@ -0,0 +1,155 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WorkloadHealth summarizes healthy vs unhealthy objects of one workload kind
+type WorkloadHealth struct {
+	Kind             string   `json:"kind"`
+	Resource         string   `json:"resource"`
+	Total            int      `json:"total"`
+	Healthy          int      `json:"healthy"`
+	Unhealthy        int      `json:"unhealthy"`
+	UnhealthyObjects []string `json:"unhealthyObjects,omitempty"`
+}
+
+// NamespaceHealth is the workload health summary of a namespace
+type NamespaceHealth struct {
+	Namespace string           `json:"namespace"`
+	Workloads []WorkloadHealth `json:"workloads"`
+	Healthy   int              `json:"healthy"`
+	Unhealthy int              `json:"unhealthy"`
+}
+
+// healthChecks maps workload resources (by FullName) to the function deciding whether an object is healthy
+var healthChecks = []struct {
+	resource  string
+	isHealthy func(obj map[string]interface{}) bool
+}{
+	{"deployments.apps", replicasReady},
+	{"statefulsets.apps", replicasReady},
+	{"daemonsets.apps", daemonSetReady},
+	{"jobs.batch", jobHealthy},
+	{"pods", podHealthy},
+}
+
+// GetNamespaceHealth computes healthy vs unhealthy counts for the workload kinds in a namespace.
+// Workload kinds the cluster does not serve are left out of the summary.
+func (c *Client) GetNamespaceHealth(ctx context.Context, namespace string) (*NamespaceHealth, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	health, err := c.namespaceHealthSnapshot(ctx, namespace)
+	if isResourceExpired(err) {
+		// The snapshot was compacted between lists, start over from the latest state
+		slog.WarnContext(ctx, "Health snapshot expired, retrying", "namespace", namespace)
+		health, err = c.namespaceHealthSnapshot(ctx, namespace)
+	}
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+	return health, nil
+}
+
+// namespaceHealthSnapshot lists the workload kinds of a namespace, pinning every list after the
+// first to the first list's resourceVersion so all kinds are read at the same point in time
+func (c *Client) namespaceHealthSnapshot(ctx context.Context, namespace string) (*NamespaceHealth, error) {
+	health := &NamespaceHealth{Namespace: namespace, Workloads: []WorkloadHealth{}}
+	resourceVersion := ""
+	for _, check := range healthChecks {
+		resource, err := c.findResource(check.resource, true)
+		if err != nil {
+			if errors.Is(err, ErrResourceNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		gvr := schema.GroupVersionResource{Group: resource.APIGroup, Version: resource.APIVersion, Resource: resource.Name}
+		list, err := c.listAllPages(ctx, c.dynamicClient.Resource(gvr).Namespace(namespace), metav1.ListOptions{ResourceVersion: resourceVersion}, 0)
+		if err != nil {
+			if isPermissionError(err) {
+				continue
+			}
+			return nil, err
+		}
+		if resourceVersion == "" {
+			resourceVersion = list.GetResourceVersion()
+		}
+
+		workload := WorkloadHealth{Kind: resource.Kind, Resource: resource.FullName, Total: len(list.Items)}
+		for _, item := range list.Items {
+			if check.isHealthy(item.Object) {
+				workload.Healthy++
+			} else {
+				workload.Unhealthy++
+				workload.UnhealthyObjects = append(workload.UnhealthyObjects, item.GetName())
+			}
+		}
+
+		health.Workloads = append(health.Workloads, workload)
+		health.Healthy += workload.Healthy
+		health.Unhealthy += workload.Unhealthy
+	}
+
+	return health, nil
+}
+
+// replicasReady reports Deployments and StatefulSets whose ready replicas match the desired replicas
+func replicasReady(obj map[string]interface{}) bool {
+	desired, found, _ := unstructured.NestedInt64(obj, "spec", "replicas")
+	if !found {
+		desired = 1 // the API server defaults spec.replicas to 1
+	}
+	ready, _, _ := unstructured.NestedInt64(obj, "status", "readyReplicas")
+	return ready >= desired
+}
+
+// daemonSetReady reports DaemonSets whose pods are ready on every scheduled node
+func daemonSetReady(obj map[string]interface{}) bool {
+	desired, _, _ := unstructured.NestedInt64(obj, "status", "desiredNumberScheduled")
+	ready, _, _ := unstructured.NestedInt64(obj, "status", "numberReady")
+	return ready >= desired
+}
+
+// jobHealthy reports Jobs that have not failed
+func jobHealthy(obj map[string]interface{}) bool {
+	return !hasCondition(obj, "Failed")
+}
+
+// podHealthy reports Pods that are running and ready, or have completed successfully
+func podHealthy(obj map[string]interface{}) bool {
+	phase, _, _ := unstructured.NestedString(obj, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return true
+	case "Running":
+		return hasCondition(obj, "Ready")
+	default:
+		return false
+	}
+}
+
+// hasCondition reports whether status.conditions contains conditionType with status True
+func hasCondition(obj map[string]interface{}, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == conditionType && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+)
+
+func newTestReplicaStatus(replicas, ready int64) map[string]interface{} {
+	return map[string]interface{}{
+		"spec":   map[string]interface{}{"replicas": replicas},
+		"status": map[string]interface{}{"readyReplicas": ready},
+	}
+}
+
+func newTestPodStatus(phase string, ready bool) map[string]interface{} {
+	readyStatus := "False"
+	if ready {
+		readyStatus = "True"
+	}
+	return map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":      phase,
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": readyStatus}},
+		},
+	}
+}
+
+func TestGetNamespaceHealth(t *testing.T) {
+	client := newTestClient(
+		newTestObject("apps/v1", "Deployment", "shop", "frontend", newTestReplicaStatus(3, 3)),
+		newTestObject("apps/v1", "Deployment", "shop", "backend", newTestReplicaStatus(3, 1)),
+		newTestObject("apps/v1", "Deployment", "shop", "idle", newTestReplicaStatus(0, 0)),
+		newTestObject("apps/v1", "StatefulSet", "shop", "db", newTestReplicaStatus(1, 0)),
+		newTestObject("v1", "Pod", "shop", "frontend-1", newTestPodStatus("Running", true)),
+		newTestObject("v1", "Pod", "shop", "backend-1", newTestPodStatus("Running", false)),
+		newTestObject("v1", "Pod", "shop", "backend-2", newTestPodStatus("Pending", false)),
+		newTestObject("v1", "Pod", "shop", "migrate-1", newTestPodStatus("Succeeded", false)),
+		newTestObject("v1", "Pod", "other", "elsewhere", newTestPodStatus("Failed", false)),
+	)
+
+	health, err := client.GetNamespaceHealth(context.Background(), "shop")
+	if err != nil {
+		t.Fatalf("GetNamespaceHealth returned error: %v", err)
+	}
+
+	byKind := make(map[string]WorkloadHealth)
+	for _, workload := range health.Workloads {
+		byKind[workload.Kind] = workload
+	}
+
+	expected := map[string][3]int{ // total, healthy, unhealthy
+		"Deployment":  {3, 2, 1},
+		"StatefulSet": {1, 0, 1},
+		"Pod":         {4, 2, 2},
+	}
+	for kind, counts := range expected {
+		workload, ok := byKind[kind]
+		if !ok {
+			t.Errorf("missing %s in health summary", kind)
+			continue
+		}
+		if workload.Total != counts[0] || workload.Healthy != counts[1] || workload.Unhealthy != counts[2] {
+			t.Errorf("%s: expected total/healthy/unhealthy %v, got %d/%d/%d",
+				kind, counts, workload.Total, workload.Healthy, workload.Unhealthy)
+		}
+	}
+
+	if len(byKind["Deployment"].UnhealthyObjects) != 1 || byKind["Deployment"].UnhealthyObjects[0] != "backend" {
+		t.Errorf("expected backend to be the unhealthy deployment, got %v", byKind["Deployment"].UnhealthyObjects)
+	}
+	if _, ok := byKind["DaemonSet"]; ok {
+		t.Errorf("DaemonSet is not served by the test cluster and should be left out")
+	}
+	if health.Healthy != 4 || health.Unhealthy != 4 {
+		t.Errorf("expected 4 healthy and 4 unhealthy overall, got %d/%d", health.Healthy, health.Unhealthy)
+	}
+}
@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"time"
+)
+
+// defaultCountHistorySize is the number of samples kept per resource when COUNT_HISTORY_SIZE is not set
+const defaultCountHistorySize int64 = 50
+
+// CountSample is the object count of a resource type at the time of a scan
+type CountSample struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+// countRing keeps the most recent samples of one resource type in a fixed-size ring buffer
+type countRing struct {
+	samples []CountSample
+	next    int // index the next sample is written to
+	full    bool
+}
+
+func (r *countRing) add(sample CountSample) {
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// series returns the samples oldest first
+func (r *countRing) series() []CountSample {
+	if !r.full {
+		return append([]CountSample(nil), r.samples[:r.next]...)
+	}
+	return append(append([]CountSample(nil), r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// recordCounts adds a sample for every resource of a completed scan of namespace. Only scans are
+// recorded; watch updates between scans are not.
+func (c *Client) recordCounts(namespace string, resources []ResourceInfo, at time.Time) {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	if c.countHistories == nil {
+		c.countHistories = make(map[string]map[string]*countRing)
+	}
+	rings := c.countHistories[namespace]
+	if rings == nil {
+		rings = make(map[string]*countRing)
+		c.countHistories[namespace] = rings
+	}
+	for _, resource := range resources {
+		ring := rings[resource.FullName]
+		if ring == nil {
+			ring = &countRing{samples: make([]CountSample, c.countHistorySize)}
+			rings[resource.FullName] = ring
+		}
+		ring.add(CountSample{Time: at, Count: resource.Count})
+	}
+}
+
+// GetCountHistory returns the recorded counts of each resource type of a namespace by FullName,
+// oldest first. At most COUNT_HISTORY_SIZE samples are kept per resource; the history lives in
+// memory only and survives ClearCache.
+func (c *Client) GetCountHistory(namespace string) map[string][]CountSample {
+	c.historyMu.Lock()
+	defer c.historyMu.Unlock()
+
+	history := make(map[string][]CountSample, len(c.countHistories[namespace]))
+	for fullName, ring := range c.countHistories[namespace] {
+		history[fullName] = ring.series()
+	}
+	return history
+}
+
+// CountHistorySize returns the number of samples kept per resource type (COUNT_HISTORY_SIZE)
+func (c *Client) CountHistorySize() int {
+	return c.countHistorySize
+}
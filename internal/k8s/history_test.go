@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestCountHistoryGrowsAndCaps(t *testing.T) {
+	t.Setenv("COUNT_HISTORY_SIZE", "3")
+	client := newTestClient()
+
+	// Every scan sees one pod more than the one before
+	pods := 0
+	fakeMetadataClient(client).PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		list := &metav1.List{}
+		for i := 0; i < pods; i++ {
+			list.Items = append(list.Items, runtime.RawExtension{Object: &metav1.PartialObjectMetadata{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("web-%d", i), Namespace: "default"},
+			}})
+		}
+		return true, list, nil
+	})
+
+	for scan := 1; scan <= 5; scan++ {
+		pods = scan
+		client.ClearNamespaceCache("default")
+		if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+			t.Fatalf("scan %d: GetResourcesInNamespace returned error: %v", scan, err)
+		}
+
+		samples := client.GetCountHistory("default")["pods"]
+		if want := min(scan, 3); len(samples) != want {
+			t.Fatalf("scan %d: expected %d samples, got %d", scan, want, len(samples))
+		}
+		if latest := samples[len(samples)-1]; latest.Count != scan {
+			t.Errorf("scan %d: expected latest count %d, got %d", scan, scan, latest.Count)
+		}
+	}
+
+	// Only the last 3 scans are kept, oldest first
+	samples := client.GetCountHistory("default")["pods"]
+	for i, want := range []int{3, 4, 5} {
+		if samples[i].Count != want {
+			t.Errorf("sample %d: expected count %d, got %d", i, want, samples[i].Count)
+		}
+	}
+	for i := 1; i < len(samples); i++ {
+		if samples[i].Time.Before(samples[i-1].Time) {
+			t.Errorf("samples out of order: %v", samples)
+		}
+	}
+
+	// A cached read is not a scan and records nothing
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	if got := client.GetCountHistory("default")["pods"]; got[len(got)-1] != samples[len(samples)-1] {
+		t.Errorf("cached read recorded a sample: %v", got)
+	}
+
+	if history := client.GetCountHistory("other"); len(history) != 0 {
+		t.Errorf("expected no history for an unscanned namespace, got %v", history)
+	}
+}
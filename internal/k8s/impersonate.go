@@ -0,0 +1,51 @@
+package k8s
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// Impersonate returns a client for the same cluster that makes every request as user and groups,
+// so listings and counts only show what RBAC allows that identity to see. The returned client has
+// its own caches.
+func (c *Client) Impersonate(user string, groups []string) (*Client, error) {
+	if c.config == nil {
+		return nil, fmt.Errorf("%w: no REST config", ErrNoClient)
+	}
+	if user == "" {
+		return nil, fmt.Errorf("%w: impersonating groups requires a user", ErrInvalidArgument)
+	}
+
+	config := rest.CopyConfig(c.config)
+	config.Impersonate = rest.ImpersonationConfig{UserName: user, Groups: groups}
+	client, err := newClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	// Impersonated scans hit the same API server, so they share its count limit
+	client.countLimiter = c.countLimiter
+	client.namespace = c.namespace
+	return client, nil
+}
+
+// applyEnvImpersonation impersonates IMPERSONATE_USER and the comma-separated IMPERSONATE_GROUPS
+// unless config already impersonates someone
+func applyEnvImpersonation(config *rest.Config) {
+	user := os.Getenv("IMPERSONATE_USER")
+	if user == "" || config.Impersonate.UserName != "" {
+		return
+	}
+
+	var groups []string
+	for _, group := range strings.Split(os.Getenv("IMPERSONATE_GROUPS"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	config.Impersonate = rest.ImpersonationConfig{UserName: user, Groups: groups}
+	slog.Info("Impersonating user for all Kubernetes requests", "user", user, "groups", groups)
+}
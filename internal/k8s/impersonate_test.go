@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// newImpersonationTestServer returns an API server that answers namespace lists and records the
+// impersonation headers of the last request
+func newImpersonationTestServer(t *testing.T) (*httptest.Server, func() (string, []string)) {
+	t.Helper()
+	var mu sync.Mutex
+	var user string
+	var groups []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		user, groups = r.Header.Get("Impersonate-User"), r.Header.Values("Impersonate-Group")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"apiVersion":"v1","kind":"NamespaceList","items":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() (string, []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return user, groups
+	}
+}
+
+func TestImpersonateSetsHeaders(t *testing.T) {
+	t.Setenv("IMPERSONATE_USER", "")
+	server, recorded := newImpersonationTestServer(t)
+
+	client, err := newClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("newClientForConfig returned error: %v", err)
+	}
+	if _, err := client.GetNamespaces(context.Background()); err != nil {
+		t.Fatalf("GetNamespaces returned error: %v", err)
+	}
+	if user, _ := recorded(); user != "" {
+		t.Errorf("expected no impersonation by default, got user %q", user)
+	}
+
+	impersonating, err := client.Impersonate("system:serviceaccount:team-a:viewer", []string{"team-a", "viewers"})
+	if err != nil {
+		t.Fatalf("Impersonate returned error: %v", err)
+	}
+	if _, err := impersonating.GetNamespaces(context.Background()); err != nil {
+		t.Fatalf("GetNamespaces returned error: %v", err)
+	}
+	user, groups := recorded()
+	if user != "system:serviceaccount:team-a:viewer" || len(groups) != 2 || groups[0] != "team-a" || groups[1] != "viewers" {
+		t.Errorf("unexpected impersonation headers user=%q groups=%v", user, groups)
+	}
+
+	// The original client keeps its own identity
+	if client.config.Impersonate.UserName != "" {
+		t.Errorf("Impersonate modified the original config: %+v", client.config.Impersonate)
+	}
+	// Persisted counts of one identity must never be loaded by another
+	if client.cacheFile() == impersonating.cacheFile() {
+		t.Error("expected the impersonating client to use its own cache file")
+	}
+
+	if _, err := client.Impersonate("", []string{"team-a"}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("groups without a user: expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestImpersonationFromEnv(t *testing.T) {
+	t.Setenv("IMPERSONATE_USER", "jane")
+	t.Setenv("IMPERSONATE_GROUPS", "developers, oncall")
+	server, recorded := newImpersonationTestServer(t)
+
+	client, err := newClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("newClientForConfig returned error: %v", err)
+	}
+	if _, err := client.GetNamespaces(context.Background()); err != nil {
+		t.Fatalf("GetNamespaces returned error: %v", err)
+	}
+	user, groups := recorded()
+	if user != "jane" || len(groups) != 2 || groups[0] != "developers" || groups[1] != "oncall" {
+		t.Errorf("unexpected impersonation headers user=%q groups=%v", user, groups)
+	}
+
+	// Per-request impersonation replaces the fixed identity
+	impersonating, err := client.Impersonate("bob", nil)
+	if err != nil {
+		t.Fatalf("Impersonate returned error: %v", err)
+	}
+	if _, err := impersonating.GetNamespaces(context.Background()); err != nil {
+		t.Fatalf("GetNamespaces returned error: %v", err)
+	}
+	if user, groups := recorded(); user != "bob" || len(groups) != 0 {
+		t.Errorf("expected to impersonate bob only, got user=%q groups=%v", user, groups)
+	}
+}
@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"sort"
+	"strings"
+)
+
+// builtinGroups are the API groups served by Kubernetes itself that do not end in .k8s.io
+var builtinGroups = map[string]bool{
+	"":            true,
+	"apps":        true,
+	"batch":       true,
+	"autoscaling": true,
+	"policy":      true,
+}
+
+// isBuiltin reports resources served by Kubernetes itself rather than by CRDs or aggregated APIs.
+// Groups under x-k8s.io belong to SIG projects installed separately, such as Cluster API.
+func isBuiltin(resource ResourceInfo) bool {
+	if builtinGroups[resource.APIGroup] {
+		return true
+	}
+	return strings.HasSuffix(resource.APIGroup, ".k8s.io") && !strings.HasSuffix(resource.APIGroup, ".x-k8s.io")
+}
+
+// LimitResourceTypes caps resources at MAX_RESOURCE_TYPES, keeping built-in resources first and
+// populated resources next. The kept resources stay in their original order; the number of
+// omitted resource types is returned alongside them.
+func (c *Client) LimitResourceTypes(resources []ResourceInfo) ([]ResourceInfo, int) {
+	if c.maxResourceTypes <= 0 || len(resources) <= c.maxResourceTypes {
+		return resources, 0
+	}
+
+	priority := func(resource ResourceInfo) int {
+		rank := 0
+		if !isBuiltin(resource) {
+			rank += 2
+		}
+		if resource.Count == 0 {
+			rank++
+		}
+		return rank
+	}
+
+	indexes := make([]int, len(resources))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return priority(resources[indexes[a]]) < priority(resources[indexes[b]])
+	})
+
+	kept := indexes[:c.maxResourceTypes]
+	sort.Ints(kept)
+
+	limited := make([]ResourceInfo, len(kept))
+	for i, index := range kept {
+		limited[i] = resources[index]
+	}
+	return limited, len(resources) - len(limited)
+}
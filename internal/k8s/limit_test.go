@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLimitResourceTypes(t *testing.T) {
+	t.Setenv("MAX_RESOURCE_TYPES", "5")
+	client := newTestClient()
+
+	resources := []ResourceInfo{
+		{Name: "widgets", APIGroup: "example.com", Count: 0},
+		{Name: "pods", APIGroup: "", Count: 0},
+		{Name: "gadgets", APIGroup: "example.com", Count: 3},
+		{Name: "deployments", APIGroup: "apps", Count: 2},
+		{Name: "machines", APIGroup: "cluster.x-k8s.io", Count: 0},
+		{Name: "ingresses", APIGroup: "networking.k8s.io", Count: 0},
+	}
+	for i := 0; i < 10; i++ {
+		resources = append(resources, ResourceInfo{Name: fmt.Sprintf("crd%d", i), APIGroup: "example.com"})
+	}
+
+	limited, omitted := client.LimitResourceTypes(resources)
+	if len(limited) != 5 {
+		t.Fatalf("expected 5 resources, got %d", len(limited))
+	}
+	if omitted != len(resources)-5 {
+		t.Errorf("expected %d omitted types, got %d", len(resources)-5, omitted)
+	}
+
+	// Built-ins first, then the populated CRD, then the first empty non-built-in; original order kept
+	expected := []string{"widgets", "pods", "gadgets", "deployments", "ingresses"}
+	for i, name := range expected {
+		if limited[i].Name != name {
+			t.Errorf("position %d: expected %s, got %s", i, name, limited[i].Name)
+		}
+	}
+}
+
+func TestLimitResourceTypesUnlimited(t *testing.T) {
+	t.Setenv("MAX_RESOURCE_TYPES", "")
+	client := newTestClient()
+
+	resources := make([]ResourceInfo, 50)
+	limited, omitted := client.LimitResourceTypes(resources)
+	if len(limited) != 50 || omitted != 0 {
+		t.Errorf("expected no truncation, got %d resources and %d omitted", len(limited), omitted)
+	}
+}
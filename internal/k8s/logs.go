@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxLogBytes caps the log output of one request, whatever the tail, so a chatty container cannot
+// exhaust the server's memory
+const maxLogBytes int64 = 10 << 20
+
+// GetPodLogs returns the logs of a container of a Pod, limited to the last tailLines lines when
+// tailLines is positive and to maxLogBytes. container may be empty for single-container pods;
+// otherwise, and for unknown containers, a ContainerError lists the containers to choose from.
+func (c *Client) GetPodLogs(ctx context.Context, namespace, podName, container string, tailLines int64) (string, error) {
+	if c.clientset == nil {
+		return "", ErrNoClient
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", wrapAPIError(err)
+	}
+	if container, err = podContainer(pod, container); err != nil {
+		return "", err
+	}
+
+	limitBytes := maxLogBytes
+	options := &corev1.PodLogOptions{Container: container, LimitBytes: &limitBytes}
+	if tailLines > 0 {
+		options.TailLines = &tailLines
+	}
+
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, options).Stream(ctx)
+	if err != nil {
+		return "", wrapAPIError(err)
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		return "", wrapAPIError(fmt.Errorf("reading logs of %s/%s: %w", podName, container, err))
+	}
+	return string(logs), nil
+}
+
+// podContainer resolves the container to read logs from: the requested one when the pod has it,
+// or the only container when none was requested
+func podContainer(pod *corev1.Pod, container string) (string, error) {
+	var names []string
+	for _, c := range pod.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		names = append(names, c.Name)
+	}
+
+	if container == "" {
+		if len(pod.Spec.Containers) == 1 {
+			return pod.Spec.Containers[0].Name, nil
+		}
+		return "", &ContainerError{Pod: pod.Name, Containers: names}
+	}
+	for _, name := range names {
+		if name == container {
+			return container, nil
+		}
+	}
+	return "", &ContainerError{Pod: pod.Name, Container: container, Containers: names}
+}
@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newLogsTestClient seeds the clientset with a single-container and a multi-container Pod. The fake
+// clientset answers every log request with the canned stream "fake logs".
+func newLogsTestClient(t *testing.T) (*Client, *fake.Clientset) {
+	t.Helper()
+	client := newTestClient()
+	clientset := client.clientset.(*fake.Clientset)
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "migrate"}},
+				Containers:     []corev1.Container{{Name: "app"}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "proxy"}}},
+		},
+	}
+	for _, pod := range pods {
+		if err := clientset.Tracker().Add(pod); err != nil {
+			t.Fatalf("seeding pod: %v", err)
+		}
+	}
+	return client, clientset
+}
+
+// logOptions returns the options of the log requests the fake clientset received
+func logOptions(clientset *fake.Clientset) []*corev1.PodLogOptions {
+	var options []*corev1.PodLogOptions
+	for _, action := range clientset.Actions() {
+		if action.GetSubresource() == "log" {
+			options = append(options, action.(k8stesting.GenericActionImpl).Value.(*corev1.PodLogOptions))
+		}
+	}
+	return options
+}
+
+func TestGetPodLogsSingleContainer(t *testing.T) {
+	client, clientset := newLogsTestClient(t)
+
+	logs, err := client.GetPodLogs(context.Background(), "default", "web", "", 100)
+	if err != nil {
+		t.Fatalf("GetPodLogs returned error: %v", err)
+	}
+	if logs != "fake logs" {
+		t.Errorf("logs = %q, want the canned stream", logs)
+	}
+
+	options := logOptions(clientset)
+	if len(options) != 1 {
+		t.Fatalf("expected one log request, got %d", len(options))
+	}
+	if options[0].Container != "app" {
+		t.Errorf("container = %q, want the only regular container", options[0].Container)
+	}
+	if options[0].TailLines == nil || *options[0].TailLines != 100 {
+		t.Errorf("tailLines = %v, want 100", options[0].TailLines)
+	}
+	if options[0].LimitBytes == nil || *options[0].LimitBytes != maxLogBytes {
+		t.Errorf("limitBytes = %v, want %d", options[0].LimitBytes, maxLogBytes)
+	}
+}
+
+func TestGetPodLogsMultiContainer(t *testing.T) {
+	client, clientset := newLogsTestClient(t)
+
+	_, err := client.GetPodLogs(context.Background(), "default", "api", "", 0)
+	var containerErr *ContainerError
+	if !errors.As(err, &containerErr) {
+		t.Fatalf("expected a ContainerError without a container, got %v", err)
+	}
+	if strings.Join(containerErr.Containers, ",") != "app,proxy" {
+		t.Errorf("containers = %v, want app,proxy", containerErr.Containers)
+	}
+
+	if _, err := client.GetPodLogs(context.Background(), "default", "api", "sidecar", 0); !errors.As(err, &containerErr) || containerErr.Container != "sidecar" {
+		t.Errorf("expected a ContainerError for an unknown container, got %v", err)
+	}
+	if len(logOptions(clientset)) != 0 {
+		t.Error("expected no log request without a valid container")
+	}
+
+	logs, err := client.GetPodLogs(context.Background(), "default", "api", "proxy", 0)
+	if err != nil || logs != "fake logs" {
+		t.Fatalf("GetPodLogs = %q, %v", logs, err)
+	}
+	if options := logOptions(clientset); len(options) != 1 || options[0].Container != "proxy" || options[0].TailLines != nil {
+		t.Errorf("expected one untailed request for proxy, got %+v", options)
+	}
+}
+
+func TestGetPodLogsMissingPod(t *testing.T) {
+	client, _ := newLogsTestClient(t)
+
+	if _, err := client.GetPodLogs(context.Background(), "default", "gone", "", 0); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// OwnerRef identifies the owner of an object
+type OwnerRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+	Controller bool   `json:"controller,omitempty"`
+}
+
+// ObjectMetadata is the lightweight, metadata-only view of a Kubernetes object
+type ObjectMetadata struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	OwnerReferences   []OwnerRef        `json:"ownerReferences,omitempty"`
+}
+
+// GetResourceObjectsMetadata lists objects requesting only PartialObjectMetadata, so spec and
+// status never cross the wire
+func (c *Client) GetResourceObjectsMetadata(ctx context.Context, namespace, resourceIdentifier string, options ListOptions) ([]ObjectMetadata, error) {
+	if c.metadataClient == nil {
+		return nil, fmt.Errorf("%w: no metadata client", ErrNoClient)
+	}
+
+	targetResource, err := c.findResource(resourceIdentifier, true)
+	if err != nil {
+		return nil, err
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    targetResource.APIGroup,
+		Version:  targetResource.APIVersion,
+		Resource: targetResource.Name,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	objects, err := c.listMetadataPages(ctx, gvr, namespace, options.ResourceVersion)
+	if err != nil && options.ResourceVersion != "" && isResourceExpired(err) {
+		slog.WarnContext(ctx, "resourceVersion is too old, listing the latest state instead",
+			"resource", targetResource.FullName, "resourceVersion", options.ResourceVersion)
+		objects, err = c.listMetadataPages(ctx, gvr, namespace, "")
+	}
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+	return objects, nil
+}
+
+// secretsGVR is the resource of core Secrets, whose metadata is redacted like the Secrets themselves
+var secretsGVR = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+// listMetadataPages lists object metadata page by page, like listAllPages does for full objects.
+// The items of a metadata list do not carry their own kind, so Secrets are recognized by gvr.
+func (c *Client) listMetadataPages(ctx context.Context, gvr schema.GroupVersionResource, namespace, resourceVersion string) ([]ObjectMetadata, error) {
+	secrets := gvr == secretsGVR
+	var objects []ObjectMetadata
+	opts := metav1.ListOptions{Limit: c.listChunkSize, ResourceVersion: resourceVersion}
+	for {
+		list, err := c.metadataClient.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range list.Items {
+			annotations := item.Annotations
+			if secrets {
+				annotations = redactAnnotations(annotations)
+			}
+			objects = append(objects, ObjectMetadata{
+				Name:              item.Name,
+				Namespace:         item.Namespace,
+				CreationTimestamp: item.CreationTimestamp.Time,
+				Labels:            item.Labels,
+				Annotations:       annotations,
+				OwnerReferences:   toOwnerRefs(item.OwnerReferences),
+			})
+		}
+
+		if list.Continue == "" {
+			return objects, nil
+		}
+		opts.Continue = list.Continue
+		opts.ResourceVersion = ""
+	}
+}
+
+// toOwnerRefs converts API owner references to OwnerRef
+func toOwnerRefs(refs []metav1.OwnerReference) []OwnerRef {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	ownerRefs := make([]OwnerRef, len(refs))
+	for i, ref := range refs {
+		ownerRefs[i] = OwnerRef{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Name:       ref.Name,
+			UID:        string(ref.UID),
+			Controller: ref.Controller != nil && *ref.Controller,
+		}
+	}
+	return ownerRefs
+}
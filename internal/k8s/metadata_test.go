@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+)
+
+func TestGetResourceObjectsMetadata(t *testing.T) {
+	pod := newTestObject("v1", "Pod", "default", "web-1", map[string]interface{}{
+		"spec":   map[string]interface{}{"containers": []interface{}{}},
+		"status": map[string]interface{}{"phase": "Running"},
+	})
+	pod.SetLabels(map[string]string{"app": "web"})
+	controller := true
+	pod.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "web-abc", UID: "rs-uid", Controller: &controller}})
+
+	client := newTestClient(pod)
+
+	objects, err := client.GetResourceObjectsMetadata(context.Background(), "default", "pods", ListOptions{})
+	if err != nil {
+		t.Fatalf("GetResourceObjectsMetadata returned error: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 object, got %d", len(objects))
+	}
+
+	object := objects[0]
+	if object.Name != "web-1" || object.Labels["app"] != "web" {
+		t.Errorf("unexpected metadata %+v", object)
+	}
+	if len(object.OwnerReferences) != 1 || object.OwnerReferences[0].Kind != "ReplicaSet" || !object.OwnerReferences[0].Controller {
+		t.Errorf("unexpected owner references %+v", object.OwnerReferences)
+	}
+
+	encoded, _ := json.Marshal(objects)
+	if strings.Contains(string(encoded), "spec") || strings.Contains(string(encoded), "status") {
+		t.Errorf("metadata-only response must not contain spec or status: %s", encoded)
+	}
+}
+
+func TestGetResourceObjectsMetadataContentType(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metav1.PartialObjectMetadataList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadataList"},
+			Items: []metav1.PartialObjectMetadata{{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "meta.k8s.io/v1", Kind: "PartialObjectMetadata"},
+				ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+			}},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient()
+	metadataClient, err := metadata.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create metadata client: %v", err)
+	}
+	client.metadataClient = metadataClient
+
+	objects, err := client.GetResourceObjectsMetadata(context.Background(), "default", "pods", ListOptions{})
+	if err != nil {
+		t.Fatalf("GetResourceObjectsMetadata returned error: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Name != "web-1" {
+		t.Errorf("unexpected objects %+v", objects)
+	}
+	if !strings.Contains(accept, "as=PartialObjectMetadataList") {
+		t.Errorf("expected metadata content type in Accept header, got %q", accept)
+	}
+}
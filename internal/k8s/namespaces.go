@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// UngroupedNamespaces is the group of GetNamespacesGrouped holding the namespaces without the label
+const UngroupedNamespaces = "ungrouped"
+
+// NamespaceInfo represents a namespace with its labels, annotations, phase and creation time
+type NamespaceInfo struct {
+	Name              string            `json:"name"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Phase             string            `json:"phase"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+}
+
+// GetNamespacesDetailed returns all namespaces with their metadata and status phase
+func (c *Client) GetNamespacesDetailed(ctx context.Context) ([]NamespaceInfo, error) {
+	if c.clientset == nil {
+		return nil, ErrNoClient
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	result := make([]NamespaceInfo, len(namespaces.Items))
+	for i, ns := range namespaces.Items {
+		result[i] = NamespaceInfo{
+			Name:              ns.Name,
+			Labels:            ns.Labels,
+			Annotations:       ns.Annotations,
+			Phase:             string(ns.Status.Phase),
+			CreationTimestamp: ns.CreationTimestamp.Time,
+		}
+	}
+
+	return result, nil
+}
+
+// GetNamespacesGrouped lists the namespaces by the value of their labelKey label, such as team, with
+// the namespaces lacking it, or setting it empty, under UngroupedNamespaces. Names are sorted within each group. It returns
+// ErrInvalidArgument when labelKey is not a valid label key.
+func (c *Client) GetNamespacesGrouped(ctx context.Context, labelKey string) (map[string][]string, error) {
+	if problems := validation.IsQualifiedName(labelKey); len(problems) > 0 {
+		return nil, fmt.Errorf("%w: label key %q: %s", ErrInvalidArgument, labelKey, strings.Join(problems, "; "))
+	}
+
+	namespaces, err := c.GetNamespacesDetailed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]string)
+	for _, ns := range namespaces {
+		group := ns.Labels[labelKey]
+		if group == "" {
+			group = UngroupedNamespaces
+		}
+		groups[group] = append(groups[group], ns.Name)
+	}
+	for _, names := range groups {
+		sort.Strings(names)
+	}
+	return groups, nil
+}
+
+// GetAccessibleNamespaces returns the namespaces the client's identity can read, sorted. When it
+// may list namespaces that is all of them. Otherwise each namespace configured in
+// ACCESSIBLE_NAMESPACES is checked with a SelfSubjectRulesReview and kept when any rule there
+// allows reading; RBAC offers no way to discover namespaces that are not listed.
+func (c *Client) GetAccessibleNamespaces(ctx context.Context) ([]string, error) {
+	namespaces, err := c.GetNamespaces(ctx)
+	if err == nil {
+		sort.Strings(namespaces)
+		return namespaces, nil
+	}
+	if !errors.Is(err, ErrForbidden) {
+		return nil, err
+	}
+	if len(c.accessibleNamespaces) == 0 {
+		return nil, fmt.Errorf("%w; set ACCESSIBLE_NAMESPACES to the namespaces to check instead", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	accessible := []string{}
+	for _, namespace := range sortedKeys(c.accessibleNamespaces) {
+		review, err := c.clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+			Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, wrapAPIError(err)
+		}
+		if allowsRead(review.Status.ResourceRules) {
+			accessible = append(accessible, namespace)
+		}
+	}
+	return accessible, nil
+}
+
+// allowsRead reports whether any rule grants get, list or all verbs
+func allowsRead(rules []authorizationv1.ResourceRule) bool {
+	for _, rule := range rules {
+		for _, verb := range rule.Verbs {
+			if verb == "get" || verb == "list" || verb == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestGetNamespacesDetailed(t *testing.T) {
+	client := newTestClient()
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, ns := range []*corev1.Namespace{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "payments",
+				Labels:            map[string]string{"team": "billing"},
+				Annotations:       map[string]string{"owner": "billing@example.com"},
+				CreationTimestamp: metav1.NewTime(created),
+			},
+			Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "old-app"},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+		},
+	} {
+		if _, err := client.clientset.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create namespace %s: %v", ns.Name, err)
+		}
+	}
+
+	namespaces, err := client.GetNamespacesDetailed(context.Background())
+	if err != nil {
+		t.Fatalf("GetNamespacesDetailed returned error: %v", err)
+	}
+	byName := make(map[string]NamespaceInfo)
+	for _, ns := range namespaces {
+		byName[ns.Name] = ns
+	}
+	if len(byName) != 2 {
+		t.Fatalf("expected 2 namespaces, got %+v", namespaces)
+	}
+
+	payments := byName["payments"]
+	if payments.Labels["team"] != "billing" || payments.Annotations["owner"] != "billing@example.com" {
+		t.Errorf("unexpected payments metadata %+v", payments)
+	}
+	if payments.Phase != "Active" || !payments.CreationTimestamp.Equal(created) {
+		t.Errorf("unexpected payments phase or creation time %+v", payments)
+	}
+	if phase := byName["old-app"].Phase; phase != "Terminating" {
+		t.Errorf("expected old-app to be Terminating, got %q", phase)
+	}
+
+	if _, err := (&Client{}).GetNamespacesDetailed(context.Background()); !errors.Is(err, ErrNoClient) {
+		t.Errorf("expected ErrNoClient without a clientset, got %v", err)
+	}
+}
+
+func TestGetNamespacesGrouped(t *testing.T) {
+	client := newTestClient()
+	for name, labels := range map[string]map[string]string{
+		"payments-prod":    {"team": "payments"},
+		"payments-staging": {"team": "payments", "env": "staging"},
+		"search":           {"team": "discovery"},
+		"unlabeled-team":   {"team": ""},
+		"kube-system":      nil,
+		"sandbox":          {"env": "dev"},
+	} {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		if _, err := client.clientset.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create namespace %s: %v", name, err)
+		}
+	}
+
+	groups, err := client.GetNamespacesGrouped(context.Background(), "team")
+	if err != nil {
+		t.Fatalf("GetNamespacesGrouped returned error: %v", err)
+	}
+	expected := map[string][]string{
+		"payments":          {"payments-prod", "payments-staging"},
+		"discovery":         {"search"},
+		UngroupedNamespaces: {"kube-system", "sandbox", "unlabeled-team"},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("groups = %v, want %v", groups, expected)
+	}
+
+	if _, err := client.GetNamespacesGrouped(context.Background(), "not a key"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument for an invalid label key, got %v", err)
+	}
+}
+
+func TestGetAccessibleNamespacesListAllowed(t *testing.T) {
+	client := newTestClient()
+	for _, name := range []string{"team-b", "team-a"} {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if _, err := client.clientset.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create namespace %s: %v", name, err)
+		}
+	}
+
+	namespaces, err := client.GetAccessibleNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("GetAccessibleNamespaces returned error: %v", err)
+	}
+	if strings.Join(namespaces, ",") != "team-a,team-b" {
+		t.Errorf("expected every namespace, got %v", namespaces)
+	}
+}
+
+func TestGetAccessibleNamespacesForbiddenList(t *testing.T) {
+	t.Setenv("ACCESSIBLE_NAMESPACES", "team-a, team-b, team-c")
+	client := newTestClient()
+	clientset := client.clientset.(*fake.Clientset)
+	clientset.PrependReactor("list", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", errors.New("cluster-wide list denied"))
+	})
+
+	// The ServiceAccount may read team-a and team-c, and only create reviews in team-b
+	rules := map[string][]authorizationv1.ResourceRule{
+		"team-a": {{Verbs: []string{"get", "list", "watch"}, Resources: []string{"pods"}}},
+		"team-b": {{Verbs: []string{"create"}, Resources: []string{"selfsubjectrulesreviews"}}},
+		"team-c": {{Verbs: []string{"*"}, Resources: []string{"*"}}},
+	}
+	clientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview)
+		review.Status.ResourceRules = rules[review.Spec.Namespace]
+		return true, review, nil
+	})
+
+	namespaces, err := client.GetAccessibleNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("GetAccessibleNamespaces returned error: %v", err)
+	}
+	if strings.Join(namespaces, ",") != "team-a,team-c" {
+		t.Errorf("expected the readable subset team-a,team-c, got %v", namespaces)
+	}
+}
+
+func TestGetAccessibleNamespacesForbiddenWithoutCandidates(t *testing.T) {
+	client := newTestClient()
+	client.clientset.(*fake.Clientset).PrependReactor("list", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", errors.New("denied"))
+	})
+
+	if _, err := client.GetAccessibleNamespaces(context.Background()); !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrForbidden without ACCESSIBLE_NAMESPACES, got %v", err)
+	}
+}
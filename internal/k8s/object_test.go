@@ -0,0 +1,309 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestObjectTimestampRepresentationsAgree(t *testing.T) {
+	created := time.Now().Add(-(3*time.Hour + 20*time.Minute)).Truncate(time.Second)
+	pod := newTestObject("v1", "Pod", "default", "web-1", nil)
+	pod.SetCreationTimestamp(metav1.NewTime(created))
+	client := newTestClient(pod)
+
+	listed, err := client.GetResourceObjects(context.Background(), "default", "pods")
+	if err != nil || len(listed) != 1 {
+		t.Fatalf("GetResourceObjects returned %d objects, error: %v", len(listed), err)
+	}
+	detail, err := client.GetResourceObject(context.Background(), "default", "pods", "web-1")
+	if err != nil {
+		t.Fatalf("GetResourceObject returned error: %v", err)
+	}
+
+	for name, object := range map[string]ObjectInfo{"listing": listed[0], "detail": *detail} {
+		if !object.CreationTimestamp.Equal(created) {
+			t.Errorf("%s: expected creationTimestamp %s, got %s", name, created, object.CreationTimestamp)
+		}
+		if object.CreationTimestampUnix != created.Unix() {
+			t.Errorf("%s: expected creationTimestampUnix %d, got %d", name, created.Unix(), object.CreationTimestampUnix)
+		}
+		if object.Age != "3h20m" {
+			t.Errorf("%s: expected age 3h20m, got %q", name, object.Age)
+		}
+
+		// The JSON timestamp stays RFC3339
+		var encoded struct {
+			CreationTimestamp string `json:"creationTimestamp"`
+		}
+		data, _ := json.Marshal(object)
+		if err := json.Unmarshal(data, &encoded); err != nil {
+			t.Fatalf("%s: decoding JSON: %v", name, err)
+		}
+		parsed, err := time.Parse(time.RFC3339, encoded.CreationTimestamp)
+		if err != nil || parsed.Unix() != object.CreationTimestampUnix {
+			t.Errorf("%s: expected RFC3339 creationTimestamp matching the unix time, got %q (%v)", name, encoded.CreationTimestamp, err)
+		}
+	}
+}
+
+func TestRawObjectManagedFields(t *testing.T) {
+	deployment := newTestObject("apps/v1", "Deployment", "default", "web", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"creationTimestamp": nil, "labels": map[string]interface{}{"app": "web"}},
+			},
+		},
+	})
+	deployment.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply}})
+	client := newTestClient(deployment)
+
+	stripped, err := client.GetRawResourceObject(context.Background(), "default", "deployments", "web")
+	if err != nil {
+		t.Fatalf("GetRawResourceObject returned error: %v", err)
+	}
+	if _, found := stripped["metadata"].(map[string]interface{})["managedFields"]; found {
+		t.Error("expected managedFields to be stripped by default")
+	}
+	template := stripped["spec"].(map[string]interface{})["template"].(map[string]interface{})["metadata"].(map[string]interface{})
+	if _, found := template["creationTimestamp"]; found {
+		t.Error("expected the null creationTimestamp of the pod template to be stripped")
+	}
+	if template["labels"] == nil {
+		t.Error("expected the rest of the pod template metadata to be kept")
+	}
+
+	kept, err := client.GetRawResourceObjectWithOptions(context.Background(), "default", "deployments", "web", ObjectOptions{ManagedFields: true})
+	if err != nil {
+		t.Fatalf("GetRawResourceObjectWithOptions returned error: %v", err)
+	}
+	managedFields, found := kept["metadata"].(map[string]interface{})["managedFields"].([]interface{})
+	if !found || len(managedFields) != 1 {
+		t.Errorf("expected managedFields to be kept when requested, got %v", kept["metadata"])
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{150 * time.Second, "2m30s"},
+		{12 * time.Minute, "12m"},
+		{5*time.Hour + 30*time.Minute, "5h30m"},
+		{20 * time.Hour, "20h"},
+		{3*24*time.Hour + 4*time.Hour, "3d4h"},
+		{45 * 24 * time.Hour, "45d"},
+		{400 * 24 * time.Hour, "400d"},
+		{3 * 365 * 24 * time.Hour, "3y"},
+		{(2*365 + 70) * 24 * time.Hour, "2y70d"},
+	}
+	for _, tt := range tests {
+		// Leave some headroom so the time passing during the test cannot tip the rounding
+		created := time.Now().Add(-tt.age - 100*time.Millisecond)
+		if got := formatAge(created); got != tt.want {
+			t.Errorf("formatAge(now-%s) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+
+	if got := formatAge(time.Time{}); got != "<unknown>" {
+		t.Errorf("formatAge(zero) = %q, want <unknown>", got)
+	}
+}
+
+func TestGetResourceObjectsAllNamespaces(t *testing.T) {
+	client := newTestClient(
+		newTestObject("apps/v1", "Deployment", "frontend", "web", nil),
+		newTestObject("apps/v1", "Deployment", "backend", "api", nil),
+		newTestObject("apps/v1", "Deployment", "backend", "worker", nil),
+		newTestObject("apps/v1", "Deployment", "monitoring", "grafana", nil),
+		newTestObject("v1", "ConfigMap", "frontend", "settings", nil),
+	)
+
+	objects, err := client.GetResourceObjectsAllNamespaces(context.Background(), "deployments")
+	if err != nil {
+		t.Fatalf("GetResourceObjectsAllNamespaces returned error: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, object := range objects {
+		got[object.Namespace+"/"+object.Name] = true
+	}
+	want := []string{"frontend/web", "backend/api", "backend/worker", "monitoring/grafana"}
+	if len(objects) != len(want) {
+		t.Errorf("expected %d deployments, got %d: %v", len(want), len(objects), got)
+	}
+	for _, key := range want {
+		if !got[key] {
+			t.Errorf("expected %s in the cluster-wide listing, got %v", key, got)
+		}
+	}
+
+	if _, err := client.GetResourceObjectsAllNamespaces(context.Background(), "nodes"); err == nil {
+		t.Error("expected an error for a cluster-scoped resource")
+	}
+}
+
+func TestMaxObjectsUnderThreshold(t *testing.T) {
+	t.Setenv("MAX_OBJECTS", "5")
+	t.Setenv("LIST_CHUNK_SIZE", "2")
+
+	var objects []runtime.Object
+	for i := 0; i < 5; i++ {
+		objects = append(objects, newTestObject("v1", "ConfigMap", "default", fmt.Sprintf("cm-%d", i), nil))
+	}
+	client, _ := newPagedTestClient(objects...)
+
+	listed, err := client.GetResourceObjects(context.Background(), "default", "configmaps")
+	if err != nil {
+		t.Fatalf("GetResourceObjects returned error: %v", err)
+	}
+	if len(listed) != 5 {
+		t.Errorf("expected all 5 objects, got %d", len(listed))
+	}
+}
+
+func TestMaxObjectsGuard(t *testing.T) {
+	t.Setenv("MAX_OBJECTS", "3")
+	t.Setenv("LIST_CHUNK_SIZE", "2")
+
+	var objects []runtime.Object
+	for i := 0; i < 9; i++ {
+		objects = append(objects, newTestObject("v1", "ConfigMap", "default", fmt.Sprintf("cm-%d", i), nil))
+	}
+	client, paged := newPagedTestClient(objects...)
+
+	_, err := client.GetResourceObjects(context.Background(), "default", "configmaps")
+	var tooMany *TooManyObjectsError
+	if !errors.As(err, &tooMany) {
+		t.Fatalf("expected TooManyObjectsError, got %v", err)
+	}
+	if tooMany.Count != 9 || tooMany.Limit != 3 || tooMany.Resource != "configmaps" {
+		t.Errorf("expected 9 configmaps over a limit of 3, got %+v", tooMany)
+	}
+	if !strings.Contains(err.Error(), "9 objects") {
+		t.Errorf("expected the count in the message, got %q", err.Error())
+	}
+
+	// Listing stopped after the page that crossed the limit, the rest was counted from metadata
+	calls := paged.recordedListOptions()
+	if len(calls) != 5 {
+		t.Errorf("expected 2 object pages and 3 metadata pages, got %d List calls", len(calls))
+	}
+
+	// Paginated listings are not refused, but a page holds no more than MAX_OBJECTS
+	list, err := client.GetResourceObjectList(context.Background(), "default", "configmaps", ListOptions{Limit: 2})
+	if err != nil || len(list.Items) != 2 {
+		t.Errorf("expected a page of 2 objects, got %v (error: %v)", list, err)
+	}
+	list, err = client.GetResourceObjectList(context.Background(), "default", "configmaps", ListOptions{Limit: 100000000})
+	if err != nil {
+		t.Fatalf("oversized limit: GetResourceObjectList returned error: %v", err)
+	}
+	if len(list.Items) != 3 || list.Continue == "" {
+		t.Errorf("oversized limit: expected a page of 3 objects and a continue token, got %d objects, continue %q",
+			len(list.Items), list.Continue)
+	}
+	calls = paged.recordedListOptions()
+	if limit := calls[len(calls)-1].Limit; limit != 3 {
+		t.Errorf("oversized limit: expected the API server to be asked for 3 objects, got %d", limit)
+	}
+}
+
+func newTestSecret() *unstructured.Unstructured {
+	secret := newTestObject("v1", "Secret", "default", "db", map[string]interface{}{
+		"data":       map[string]interface{}{"password": "aHVudGVyMg=="},
+		"stringData": map[string]interface{}{"user": "admin"},
+	})
+	secret.SetAnnotations(map[string]string{
+		lastAppliedAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`,
+		"team":                "payments",
+	})
+	return secret
+}
+
+func TestSecretValuesRedactedByDefault(t *testing.T) {
+	client := newTestClient(newTestSecret())
+
+	raw, err := client.GetRawResourceObject(context.Background(), "default", "secrets", "db")
+	if err != nil {
+		t.Fatalf("GetRawResourceObject returned error: %v", err)
+	}
+	if got := raw["data"].(map[string]interface{})["password"]; got != "**** (7 bytes)" {
+		t.Errorf("data.password = %v, want the redacted decoded length", got)
+	}
+	if got := raw["stringData"].(map[string]interface{})["user"]; got != "**** (5 bytes)" {
+		t.Errorf("stringData.user = %v, want the redacted length", got)
+	}
+
+	object, err := client.GetResourceObject(context.Background(), "default", "secrets", "db")
+	if err != nil {
+		t.Fatalf("GetResourceObject returned error: %v", err)
+	}
+	if got := object.Annotations[lastAppliedAnnotation]; got != "****" {
+		t.Errorf("last-applied annotation = %q, want it redacted", got)
+	}
+	if object.Annotations["team"] != "payments" {
+		t.Errorf("expected other annotations to be kept, got %v", object.Annotations)
+	}
+}
+
+func TestSecretAnnotationRedactedInListings(t *testing.T) {
+	client := newTestClient(newTestSecret())
+	ctx := context.Background()
+
+	objects, err := client.GetResourceObjects(ctx, "default", "secrets")
+	if err != nil || len(objects) != 1 {
+		t.Fatalf("GetResourceObjects returned %v, error: %v", objects, err)
+	}
+	metadata, err := client.GetResourceObjectsMetadata(ctx, "default", "secrets", ListOptions{})
+	if err != nil || len(metadata) != 1 {
+		t.Fatalf("GetResourceObjectsMetadata returned %v, error: %v", metadata, err)
+	}
+	matches, err := client.SearchObjectsByName(ctx, "default", "db")
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("SearchObjectsByName returned %v, error: %v", matches, err)
+	}
+
+	for source, annotations := range map[string]map[string]string{
+		"listing":  objects[0].Annotations,
+		"metadata": metadata[0].Annotations,
+		"search":   matches[0].Annotations,
+	} {
+		if got := annotations[lastAppliedAnnotation]; got != "****" {
+			t.Errorf("%s: last-applied annotation = %q, want it redacted", source, got)
+		}
+		if annotations["team"] != "payments" {
+			t.Errorf("%s: expected other annotations to be kept, got %v", source, annotations)
+		}
+	}
+}
+
+func TestSecretValuesRevealed(t *testing.T) {
+	client := newTestClient(newTestSecret())
+
+	raw, err := client.GetRawResourceObjectWithOptions(context.Background(), "default", "secrets", "db", ObjectOptions{RevealSecrets: true})
+	if err != nil {
+		t.Fatalf("GetRawResourceObjectWithOptions returned error: %v", err)
+	}
+	if got := raw["data"].(map[string]interface{})["password"]; got != "aHVudGVyMg==" {
+		t.Errorf("data.password = %v, want the original value", got)
+	}
+
+	object, err := client.GetResourceObjectWithOptions(context.Background(), "default", "secrets", "db", ObjectOptions{RevealSecrets: true})
+	if err != nil {
+		t.Fatalf("GetResourceObjectWithOptions returned error: %v", err)
+	}
+	if got := object.Annotations[lastAppliedAnnotation]; got == "****" {
+		t.Error("expected the last-applied annotation to be revealed")
+	}
+}
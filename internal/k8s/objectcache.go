@@ -0,0 +1,110 @@
+package k8s
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// defaultObjectCacheTTL is how long a fetched object is served from the object cache
+const defaultObjectCacheTTL = 30 * time.Second
+
+// objectCache keeps recently fetched objects so navigating back and forth in the UI does not read
+// the same object again. It holds at most capacity objects, evicting the least recently used, and
+// serves each for ttl. A nil cache is disabled: it never hits and ignores stores.
+type objectCache struct {
+	capacity int
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // key -> element of order holding an *objectCacheEntry
+	order   *list.List               // most recently used first
+}
+
+type objectCacheEntry struct {
+	key      string
+	object   *unstructured.Unstructured
+	cachedAt time.Time
+}
+
+// newObjectCache returns a cache of capacity objects, or nil when capacity is 0 (OBJECT_CACHE_SIZE unset)
+func newObjectCache(capacity int, ttl time.Duration) *objectCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &objectCache{capacity: capacity, ttl: ttl, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// objectCacheKey identifies an object by namespace, resource FullName, the version it was read at
+// and name
+func objectCacheKey(namespace, resource, version, name string) string {
+	return namespace + "/" + resource + "/" + version + "/" + name
+}
+
+// get returns a copy of the cached object, which callers may modify, or false on a miss or when the
+// entry has expired
+func (c *objectCache) get(key string) (*unstructured.Unstructured, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	entry := element.Value.(*objectCacheEntry)
+	if time.Since(entry.cachedAt) >= c.ttl {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.object.DeepCopy(), true
+}
+
+// put stores a copy of object, evicting the least recently used object when the cache is full
+func (c *objectCache) put(key string, object *unstructured.Unstructured) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &objectCacheEntry{key: key, object: object.DeepCopy(), cachedAt: time.Now()}
+	if element, exists := c.entries[key]; exists {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*objectCacheEntry).key)
+	}
+}
+
+// clear drops every object whose key starts with prefix, all objects for "", and returns how many
+// were dropped
+func (c *objectCache) clear(prefix string) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cleared := 0
+	for key, element := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.order.Remove(element)
+			delete(c.entries, key)
+			cleared++
+		}
+	}
+	return cleared
+}
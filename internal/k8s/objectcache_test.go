@@ -0,0 +1,127 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// countGets returns the number of get requests the dynamic client has served
+func countGets(client *Client) int {
+	gets := 0
+	for _, action := range client.dynamicClient.(*dynamicfake.FakeDynamicClient).Actions() {
+		if action.GetVerb() == "get" {
+			gets++
+		}
+	}
+	return gets
+}
+
+func TestObjectCacheHit(t *testing.T) {
+	t.Setenv("OBJECT_CACHE_SIZE", "10")
+	client := newTestClient(newTestObject("v1", "Secret", "default", "token", map[string]interface{}{
+		"data": map[string]interface{}{"password": "c2VjcmV0"},
+	}))
+	ctx := context.Background()
+
+	raw, err := client.GetRawResourceObject(ctx, "default", "secrets", "token")
+	if err != nil {
+		t.Fatalf("GetRawResourceObject returned error: %v", err)
+	}
+	// The redacted copy handed out must not leak into the cache
+	revealed, err := client.GetRawResourceObjectWithOptions(ctx, "default", "secrets", "token", ObjectOptions{RevealSecrets: true})
+	if err != nil {
+		t.Fatalf("GetRawResourceObjectWithOptions returned error: %v", err)
+	}
+	if _, err := client.GetResourceObject(ctx, "default", "secrets", "token"); err != nil {
+		t.Fatalf("GetResourceObject returned error: %v", err)
+	}
+
+	if gets := countGets(client); gets != 1 {
+		t.Errorf("three reads of the same object made %d get requests, expected 1", gets)
+	}
+	if value, _, _ := unstructured.NestedString(revealed, "data", "password"); value != "c2VjcmV0" {
+		t.Errorf("revealed password = %q from the cache, expected the stored value", value)
+	}
+	if value, _, _ := unstructured.NestedString(raw, "data", "password"); value == "c2VjcmV0" {
+		t.Error("the first read was not redacted")
+	}
+
+	// Clearing the caches forces a new read
+	if cleared := client.ClearCache(); cleared < 1 {
+		t.Errorf("ClearCache() = %d, expected the cached object to be counted", cleared)
+	}
+	if _, err := client.GetRawResourceObject(ctx, "default", "secrets", "token"); err != nil {
+		t.Fatalf("GetRawResourceObject returned error: %v", err)
+	}
+	if gets := countGets(client); gets != 2 {
+		t.Errorf("read after ClearCache made %d get requests in total, expected 2", gets)
+	}
+}
+
+func TestObjectCacheDisabled(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "ConfigMap", "default", "settings", nil))
+	if client.objectCache != nil {
+		t.Fatal("object cache enabled without OBJECT_CACHE_SIZE")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRawResourceObject(context.Background(), "default", "configmaps", "settings"); err != nil {
+			t.Fatalf("GetRawResourceObject returned error: %v", err)
+		}
+	}
+	if gets := countGets(client); gets != 2 {
+		t.Errorf("two reads made %d get requests, expected 2", gets)
+	}
+}
+
+func TestObjectCacheTTL(t *testing.T) {
+	cache := newObjectCache(10, 20*time.Millisecond)
+	cache.put("default/configmaps/settings", newTestObject("v1", "ConfigMap", "default", "settings", nil))
+
+	if _, found := cache.get("default/configmaps/settings"); !found {
+		t.Fatal("fresh object not served from the cache")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, found := cache.get("default/configmaps/settings"); found {
+		t.Error("expired object served from the cache")
+	}
+	if len(cache.entries) != 0 || cache.order.Len() != 0 {
+		t.Errorf("expired object still held: %d entries", len(cache.entries))
+	}
+}
+
+func TestObjectCacheEviction(t *testing.T) {
+	cache := newObjectCache(2, time.Minute)
+	cache.put("default/configmaps/a", newTestObject("v1", "ConfigMap", "default", "a", nil))
+	cache.put("default/configmaps/b", newTestObject("v1", "ConfigMap", "default", "b", nil))
+
+	// Reading a makes b the least recently used, so adding c evicts b
+	cache.get("default/configmaps/a")
+	cache.put("default/configmaps/c", newTestObject("v1", "ConfigMap", "default", "c", nil))
+
+	for key, expected := range map[string]bool{
+		"default/configmaps/a": true,
+		"default/configmaps/b": false,
+		"default/configmaps/c": true,
+	} {
+		if _, found := cache.get(key); found != expected {
+			t.Errorf("get(%s) found = %v, expected %v", key, found, expected)
+		}
+	}
+}
+
+func TestObjectCacheClearNamespace(t *testing.T) {
+	cache := newObjectCache(10, time.Minute)
+	cache.put("team-a/configmaps/settings", newTestObject("v1", "ConfigMap", "team-a", "settings", nil))
+	cache.put("team-ab/configmaps/settings", newTestObject("v1", "ConfigMap", "team-ab", "settings", nil))
+
+	if cleared := cache.clear("team-a/"); cleared != 1 {
+		t.Errorf("clear(team-a/) = %d, expected 1", cleared)
+	}
+	if _, found := cache.get("team-ab/configmaps/settings"); !found {
+		t.Error("clearing team-a dropped an object of team-ab")
+	}
+}
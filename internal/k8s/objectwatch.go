@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// WatchEvent is a change to an object of a watched resource
+type WatchEvent struct {
+	Type   string      `json:"type"` // ADDED, MODIFIED, DELETED or ERROR
+	Object *ObjectInfo `json:"object,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// WatchResourceObjects streams the changes to the objects of a resource in a namespace until ctx is
+// done or the API server ends the watch; the channel is closed either way. The watch starts with an
+// ADDED event for every existing object. A watch failure is delivered as a final ERROR event.
+func (c *Client) WatchResourceObjects(ctx context.Context, namespace, resourceIdentifier string) (<-chan WatchEvent, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	resource, err := c.findResource(resourceIdentifier, true)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := c.resourceClient(namespace, *resource).Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	events := make(chan WatchEvent)
+	go func() {
+		defer close(events)
+		defer w.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.ResultChan():
+				if !ok {
+					return
+				}
+
+				watchEvent, last := toWatchEvent(event)
+				if watchEvent == nil {
+					continue
+				}
+				select {
+				case events <- *watchEvent:
+				case <-ctx.Done():
+					return
+				}
+				if last {
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// toWatchEvent converts a watch event, returning nil for events that carry no object change such as
+// bookmarks. last reports whether the event ends the watch.
+func toWatchEvent(event watch.Event) (watchEvent *WatchEvent, last bool) {
+	switch event.Type {
+	case watch.Added, watch.Modified, watch.Deleted:
+		item, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			return nil, false
+		}
+		object := toObjectInfo(item)
+		return &WatchEvent{Type: string(event.Type), Object: &object}, false
+	case watch.Error:
+		return &WatchEvent{Type: string(watch.Error), Error: apierrors.FromObject(event.Object).Error()}, true
+	default:
+		return nil, false
+	}
+}
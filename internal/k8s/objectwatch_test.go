@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// nextWatchEvent reads one event, failing the test if none arrives in time
+func nextWatchEvent(t *testing.T, events <-chan WatchEvent) (WatchEvent, bool) {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		return event, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a watch event")
+		return WatchEvent{}, false
+	}
+}
+
+func newWatchTestClient() (*Client, *watch.FakeWatcher) {
+	client := newTestClient()
+	watcher := watch.NewFake()
+	client.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependWatchReactor("pods",
+		func(action k8stesting.Action) (bool, watch.Interface, error) {
+			return true, watcher, nil
+		})
+	return client, watcher
+}
+
+func TestWatchResourceObjects(t *testing.T) {
+	client, watcher := newWatchTestClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.WatchResourceObjects(ctx, "default", "pods")
+	if err != nil {
+		t.Fatalf("WatchResourceObjects returned error: %v", err)
+	}
+
+	pod := newTestObject("v1", "Pod", "default", "web", nil)
+	go func() {
+		watcher.Add(pod)
+		watcher.Modify(pod)
+		watcher.Delete(pod)
+	}()
+
+	for _, want := range []string{"ADDED", "MODIFIED", "DELETED"} {
+		event, ok := nextWatchEvent(t, events)
+		if !ok {
+			t.Fatalf("channel closed before the %s event", want)
+		}
+		if event.Type != want || event.Object == nil || event.Object.Name != "web" {
+			t.Errorf("expected %s of web, got %+v", want, event)
+		}
+	}
+
+	// Cancelling the context, as a disconnecting client does, ends the watch
+	cancel()
+	if _, ok := nextWatchEvent(t, events); ok {
+		t.Error("expected the channel to close after cancel")
+	}
+	if !watcher.IsStopped() {
+		t.Error("expected the underlying watch to be stopped")
+	}
+}
+
+func TestWatchResourceObjectsError(t *testing.T) {
+	client, watcher := newWatchTestClient()
+
+	events, err := client.WatchResourceObjects(context.Background(), "default", "pods")
+	if err != nil {
+		t.Fatalf("WatchResourceObjects returned error: %v", err)
+	}
+
+	go watcher.Error(&apierrors.NewResourceExpired("too old resource version").ErrStatus)
+
+	event, ok := nextWatchEvent(t, events)
+	if !ok || event.Type != "ERROR" || event.Error == "" {
+		t.Errorf("expected an ERROR event, got %+v", event)
+	}
+	if _, ok := nextWatchEvent(t, events); ok {
+		t.Error("expected the channel to close after an ERROR event")
+	}
+}
+
+func TestWatchResourceObjectsUnknownResource(t *testing.T) {
+	client := newTestClient()
+	if _, err := client.WatchResourceObjects(context.Background(), "default", "gadgets"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxOwnerDepth bounds how far GetOwnerChain walks up, in case owner references form a cycle the
+// UID check does not catch
+const maxOwnerDepth = 10
+
+// GetOwnerChain resolves the owners of an object, nearest first: for a Pod that is its ReplicaSet,
+// then the Deployment owning that ReplicaSet. At each step the controller reference is followed,
+// or the first owner when none is marked as controller. The walk stops at an object without
+// owners, an owner whose kind is not served, or an owner that no longer exists.
+func (c *Client) GetOwnerChain(ctx context.Context, namespace, resourceIdentifier, name string) ([]ObjectInfo, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	resource, err := c.findResource(resourceIdentifier, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	item, err := c.resourceClient(namespace, *resource).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	chain := []ObjectInfo{}
+	seen := map[string]bool{string(item.GetUID()): true}
+	current := toObjectInfo(item)
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		ref, ok := controllerRef(current.OwnerReferences)
+		if !ok {
+			return chain, nil
+		}
+		if seen[ref.UID] {
+			slog.WarnContext(ctx, "Owner references form a cycle", "namespace", namespace, "name", name, "ownerKind", ref.Kind, "ownerName", ref.Name)
+			return chain, nil
+		}
+		seen[ref.UID] = true
+
+		owner, err := c.resourceForKind(ref.APIVersion, ref.Kind)
+		if err != nil {
+			slog.WarnContext(ctx, "Cannot resolve owner", "ownerKind", ref.Kind, "ownerName", ref.Name, "error", err)
+			return chain, nil
+		}
+
+		// Owner references never cross namespaces: a namespaced owner lives in the namespace of
+		// the object it owns, a cluster-scoped owner has no namespace
+		ownerNamespace := ""
+		if owner.Namespaced {
+			ownerNamespace = current.Namespace
+		}
+
+		item, err := c.resourceClient(ownerNamespace, *owner).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// The owner was deleted and garbage collection has not caught up yet
+				return chain, nil
+			}
+			return nil, wrapAPIError(err)
+		}
+
+		current = toObjectInfo(item)
+		chain = append(chain, current)
+	}
+
+	slog.WarnContext(ctx, "Owner chain is too deep, stopping", "namespace", namespace, "name", name, "maxDepth", maxOwnerDepth)
+	return chain, nil
+}
+
+// controllerRef returns the controller among the owner references, or the first owner when none
+// is marked as controller
+func controllerRef(refs []OwnerRef) (OwnerRef, bool) {
+	for _, ref := range refs {
+		if ref.Controller {
+			return ref, true
+		}
+	}
+	if len(refs) > 0 {
+		return refs[0], true
+	}
+	return OwnerRef{}, false
+}
+
+// resourceForKind looks up the discovered resource serving a kind in the group of apiVersion
+func (c *Client) resourceForKind(apiVersion, kind string) (*ResourceInfo, error) {
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArgument, err)
+	}
+
+	resources, err := c.GetAPIResources()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		if resource.APIGroup == gv.Group && resource.Kind == kind && !resource.Subresource {
+			return &resource, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no resource for kind %s in %s", ErrResourceNotFound, kind, apiVersion)
+}
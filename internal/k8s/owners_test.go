@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// setOwner makes owner the controller of obj
+func setOwner(obj, owner *unstructured.Unstructured) {
+	controller := true
+	obj.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: owner.GetAPIVersion(),
+		Kind:       owner.GetKind(),
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+		Controller: &controller,
+	}})
+}
+
+func newOwnedTestObject(apiVersion, kind, name string) *unstructured.Unstructured {
+	obj := newTestObject(apiVersion, kind, "default", name, nil)
+	obj.SetUID(types.UID("uid-" + name))
+	return obj
+}
+
+func TestGetOwnerChain(t *testing.T) {
+	deployment := newOwnedTestObject("apps/v1", "Deployment", "web")
+	replicaSet := newOwnedTestObject("apps/v1", "ReplicaSet", "web-7d9f")
+	pod := newOwnedTestObject("v1", "Pod", "web-7d9f-x2k4")
+	setOwner(replicaSet, deployment)
+	setOwner(pod, replicaSet)
+	client := newTestClient(deployment, replicaSet, pod)
+
+	chain, err := client.GetOwnerChain(context.Background(), "default", "pods", "web-7d9f-x2k4")
+	if err != nil {
+		t.Fatalf("GetOwnerChain returned error: %v", err)
+	}
+
+	if len(chain) != 2 || chain[0].Kind != "ReplicaSet" || chain[1].Kind != "Deployment" {
+		t.Fatalf("expected ReplicaSet then Deployment, got %+v", chain)
+	}
+	if chain[0].Name != "web-7d9f" || chain[1].Name != "web" {
+		t.Errorf("unexpected owner names %s, %s", chain[0].Name, chain[1].Name)
+	}
+	if len(chain[0].OwnerReferences) != 1 || chain[0].OwnerReferences[0].Name != "web" || !chain[0].OwnerReferences[0].Controller {
+		t.Errorf("expected the ReplicaSet to reference its Deployment, got %+v", chain[0].OwnerReferences)
+	}
+}
+
+func TestGetOwnerChainCycle(t *testing.T) {
+	a := newOwnedTestObject("apps/v1", "ReplicaSet", "a")
+	b := newOwnedTestObject("apps/v1", "ReplicaSet", "b")
+	setOwner(a, b)
+	setOwner(b, a)
+	client := newTestClient(a, b)
+
+	chain, err := client.GetOwnerChain(context.Background(), "default", "replicasets", "a")
+	if err != nil {
+		t.Fatalf("GetOwnerChain returned error: %v", err)
+	}
+	if len(chain) != 1 || chain[0].Name != "b" {
+		t.Errorf("expected the walk to stop before revisiting a, got %+v", chain)
+	}
+}
+
+func TestGetOwnerChainClusterScopedAndMissingOwners(t *testing.T) {
+	node := newTestObject("v1", "Node", "", "node-a", nil)
+	node.SetUID("uid-node-a")
+	mirrorPod := newOwnedTestObject("v1", "Pod", "kube-proxy-node-a")
+	setOwner(mirrorPod, node)
+	orphan := newOwnedTestObject("v1", "Pod", "orphan")
+	setOwner(orphan, newOwnedTestObject("apps/v1", "ReplicaSet", "deleted"))
+	client := newTestClient(node, mirrorPod, orphan)
+
+	chain, err := client.GetOwnerChain(context.Background(), "default", "pods", "kube-proxy-node-a")
+	if err != nil {
+		t.Fatalf("GetOwnerChain returned error: %v", err)
+	}
+	if len(chain) != 1 || chain[0].Kind != "Node" || chain[0].Namespace != "" {
+		t.Errorf("expected the cluster-scoped Node, got %+v", chain)
+	}
+
+	chain, err = client.GetOwnerChain(context.Background(), "default", "pods", "orphan")
+	if err != nil {
+		t.Fatalf("GetOwnerChain returned error: %v", err)
+	}
+	if len(chain) != 0 {
+		t.Errorf("expected an empty chain for a deleted owner, got %+v", chain)
+	}
+}
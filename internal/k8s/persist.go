@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// diskCacheEntry is a cached resource list with the time it was cached
+type diskCacheEntry struct {
+	CachedAt  time.Time      `json:"cachedAt"`
+	Resources []ResourceInfo `json:"resources"`
+}
+
+// diskCache is the on-disk form of the API resource and namespace caches
+type diskCache struct {
+	APIResources *diskCacheEntry           `json:"apiResources,omitempty"`
+	Namespaces   map[string]diskCacheEntry `json:"namespaces,omitempty"`
+}
+
+// cacheFile returns the cache file of this client's cluster and identity in CACHE_DIR. Clusters and
+// impersonated identities get separate files so clients never load counts another one was allowed to see.
+func (c *Client) cacheFile() string {
+	key := ""
+	if c.config != nil {
+		key = c.config.Host
+		if impersonate := c.config.Impersonate; impersonate.UserName != "" {
+			key += "\x00" + impersonate.UserName + "\x00" + strings.Join(impersonate.Groups, ",")
+		}
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.cacheDir, "cache-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// persistCache writes the caches to CACHE_DIR so a restarted server starts warm. Failures are
+// logged and otherwise ignored; the in-memory caches stay authoritative.
+func (c *Client) persistCache() {
+	if c.cacheDir == "" {
+		return
+	}
+
+	// Snapshot and write under persistMu so an older snapshot never overwrites a newer one
+	c.persistMu.Lock()
+	defer c.persistMu.Unlock()
+
+	var cache diskCache
+	c.resourcesMu.RLock()
+	if c.resourcesCache != nil {
+		cache.APIResources = &diskCacheEntry{CachedAt: c.resourcesCacheTime, Resources: c.resourcesCache}
+	}
+	c.resourcesMu.RUnlock()
+
+	c.namespaceMu.RLock()
+	cache.Namespaces = make(map[string]diskCacheEntry, len(c.namespaceCaches))
+	for namespace, resources := range c.namespaceCaches {
+		cache.Namespaces[namespace] = diskCacheEntry{CachedAt: c.namespaceCacheTimes[namespace], Resources: resources}
+	}
+	c.namespaceMu.RUnlock()
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		slog.Warn("Failed to encode cache", "dir", c.cacheDir, "error", err)
+		return
+	}
+
+	if err := os.MkdirAll(c.cacheDir, 0o755); err != nil {
+		slog.Warn("Failed to create cache directory", "dir", c.cacheDir, "error", err)
+		return
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated cache behind
+	path := c.cacheFile()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		slog.Warn("Failed to write cache file", "path", tmp, "error", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		slog.Warn("Failed to replace cache file", "path", path, "error", err)
+	}
+}
+
+// loadCache restores the caches persisted in CACHE_DIR, skipping entries older than the TTL. A
+// missing, unreadable or corrupt cache file leaves the caches empty.
+func (c *Client) loadCache() {
+	if c.cacheDir == "" {
+		return
+	}
+
+	path := c.cacheFile()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("Ignoring unreadable cache file", "path", path, "error", err)
+		}
+		return
+	}
+
+	var cache diskCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		slog.Warn("Ignoring corrupt cache file", "path", path, "error", err)
+		return
+	}
+
+	if entry := cache.APIResources; entry != nil && len(entry.Resources) > 0 && time.Since(entry.CachedAt) < c.cacheTTL {
+		c.resourcesMu.Lock()
+		c.resourcesCache = entry.Resources
+		c.resourcesCacheTime = entry.CachedAt
+		c.resourcesMu.Unlock()
+	}
+
+	loaded := 0
+	c.namespaceMu.Lock()
+	for namespace, entry := range cache.Namespaces {
+		if time.Since(entry.CachedAt) >= c.cacheTTL {
+			continue
+		}
+		c.namespaceCaches[namespace] = entry.Resources
+		c.namespaceCacheTimes[namespace] = entry.CachedAt
+		loaded++
+	}
+	c.namespaceMu.Unlock()
+
+	slog.Info("Loaded cache", "path", path, "namespaces", loaded)
+}
@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCachePersistedAcrossRestarts(t *testing.T) {
+	t.Setenv("CACHE_DIR", t.TempDir())
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web", nil))
+
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+
+	restarted := newTestClient()
+	stats := restarted.GetCacheStats()
+	if !stats.ResourcesCached || stats.ResourceCount != len(mustAPIResources(t, client)) {
+		t.Errorf("expected the API resource cache to be loaded, got %+v", stats)
+	}
+	if _, found := stats.Namespaces["default"]; !found {
+		t.Fatalf("expected the default namespace cache to be loaded, got %+v", stats.Namespaces)
+	}
+
+	// The restarted client answers from the loaded cache even though its fake cluster is empty
+	resources, err := restarted.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	for _, resource := range resources {
+		if resource.FullName == "pods" && resource.Count != 1 {
+			t.Errorf("expected the persisted pod count 1, got %d", resource.Count)
+		}
+	}
+
+	// Clearing the cache clears the persisted copy too
+	client.ClearCache()
+	if stats := newTestClient().GetCacheStats(); stats.ResourcesCached || len(stats.Namespaces) != 0 {
+		t.Errorf("expected an empty cache after ClearCache, got %+v", stats)
+	}
+}
+
+func TestCacheExpiredOnLoad(t *testing.T) {
+	t.Setenv("CACHE_DIR", t.TempDir())
+	client := newTestClient()
+
+	stale := time.Now().Add(-2 * client.cacheTTL)
+	fresh := time.Now().Add(-time.Minute)
+	cache := diskCache{
+		APIResources: &diskCacheEntry{CachedAt: stale, Resources: []ResourceInfo{{Name: "pods", FullName: "pods"}}},
+		Namespaces: map[string]diskCacheEntry{
+			"old": {CachedAt: stale, Resources: []ResourceInfo{{Name: "pods", FullName: "pods", Count: 1}}},
+			"new": {CachedAt: fresh, Resources: []ResourceInfo{{Name: "pods", FullName: "pods", Count: 2}}},
+		},
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		t.Fatalf("encoding cache: %v", err)
+	}
+	if err := os.WriteFile(client.cacheFile(), data, 0o600); err != nil {
+		t.Fatalf("writing cache file: %v", err)
+	}
+
+	stats := newTestClient().GetCacheStats()
+	if stats.ResourcesCached {
+		t.Error("expected the expired API resource cache to be discarded")
+	}
+	if _, found := stats.Namespaces["old"]; found {
+		t.Error("expected the expired namespace cache to be discarded")
+	}
+	if _, found := stats.Namespaces["new"]; !found {
+		t.Error("expected the fresh namespace cache to be loaded")
+	}
+}
+
+func TestCorruptCacheFileIgnored(t *testing.T) {
+	t.Setenv("CACHE_DIR", t.TempDir())
+	if err := os.WriteFile(newTestClient().cacheFile(), []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("writing cache file: %v", err)
+	}
+
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web", nil))
+	if stats := client.GetCacheStats(); stats.ResourcesCached || len(stats.Namespaces) != 0 {
+		t.Errorf("expected empty caches from a corrupt file, got %+v", stats)
+	}
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Errorf("expected counting to work after a corrupt cache file, got %v", err)
+	}
+}
+
+func mustAPIResources(t *testing.T, client *Client) []ResourceInfo {
+	t.Helper()
+	resources, err := client.GetAPIResources()
+	if err != nil {
+		t.Fatalf("GetAPIResources returned error: %v", err)
+	}
+	return resources
+}
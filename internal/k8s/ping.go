@@ -0,0 +1,30 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+)
+
+// Ping checks that the API server is reachable with a cheap version request. It gives up when ctx
+// is done, even if the request itself is still waiting on the network.
+func (c *Client) Ping(ctx context.Context) error {
+	if c.discoveryClient == nil {
+		return fmt.Errorf("%w: no discovery client", ErrNoClient)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.discoveryClient.ServerVersion()
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return wrapAPIError(err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+	}
+}
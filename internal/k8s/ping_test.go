@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/version"
+)
+
+// hangingDiscovery never answers the version request until released
+type hangingDiscovery struct {
+	*testDiscovery
+	release chan struct{}
+}
+
+func (d *hangingDiscovery) ServerVersion() (*version.Info, error) {
+	<-d.release
+	return d.testDiscovery.ServerVersion()
+}
+
+func TestPing(t *testing.T) {
+	client := newTestClient()
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("expected a reachable API server, got %v", err)
+	}
+
+	discovery := client.discoveryClient.(*testDiscovery)
+	discovery.err = errors.New("connection refused")
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("expected Ping to fail when the version request fails")
+	}
+}
+
+func TestPingTimeout(t *testing.T) {
+	client := newTestClient()
+	hanging := &hangingDiscovery{testDiscovery: client.discoveryClient.(*testDiscovery), release: make(chan struct{})}
+	defer close(hanging.release)
+	client.discoveryClient = hanging
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Ping(ctx); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout once the context is done, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// prewarmConcurrency is the number of namespaces scanned at once while prewarming. Each scan counts
+// up to COUNT_CONCURRENCY resource types in parallel, and those counts take GLOBAL_COUNT_LIMIT slots
+// like any other. Scanning one namespace at a time holds the warmer to COUNT_CONCURRENCY slots, a
+// quarter of them with the defaults, and leaves the rest to user requests.
+const prewarmConcurrency = 1
+
+// PrewarmNamespaces counts the resources of every namespace so their first visit is served from the
+// cache. Namespaces whose counts are still fresh are skipped by GetResourcesInNamespace itself. It
+// returns the number of namespaces warmed; namespaces that fail are logged and skipped. When ctx is
+// cancelled no further namespaces are scanned and ctx's error is returned.
+func (c *Client) PrewarmNamespaces(ctx context.Context) (int, error) {
+	namespaces, err := c.GetNamespaces(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	slog.InfoContext(ctx, "🔥 Prewarming namespace caches", "namespaces", len(namespaces))
+
+	warmed, failed := 0, 0
+	for _, result := range c.WarmNamespaces(ctx, namespaces) {
+		if result.Error == "" {
+			warmed++
+		} else {
+			failed++
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		slog.InfoContext(ctx, "Prewarming cancelled", "warmed", warmed, "total", len(namespaces))
+		return warmed, err
+	}
+	slog.InfoContext(ctx, "🔥 Prewarmed namespace caches", "warmed", warmed, "failed", failed, "duration", time.Since(start))
+	return warmed, nil
+}
+
+// WarmResult is the outcome of warming the cache of one namespace
+type WarmResult struct {
+	Namespace string `json:"namespace"`
+	Resources int    `json:"resources"` // resource types counted
+	Objects   int    `json:"objects"`   // objects across those types
+	// Cached is set when the namespace's counts were still fresh and nothing was counted
+	Cached     bool   `json:"cached"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WarmNamespaces counts the resources of namespaces, prewarmConcurrency at a time, and returns a
+// result per namespace in the given order. Counting goes through GetResourcesInNamespace, so fresh
+// counts are reused and a namespace a user request is already counting is counted once. Failures are
+// reported in the results; when ctx is cancelled the namespaces not yet started fail with ctx's error.
+func (c *Client) WarmNamespaces(ctx context.Context, namespaces []string) []WarmResult {
+	results := make([]WarmResult, len(namespaces))
+	for i, namespace := range namespaces {
+		results[i].Namespace = namespace
+	}
+
+	var mu sync.Mutex
+	done := 0
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < prewarmConcurrency && w < len(namespaces); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := &results[i]
+				countedBefore := c.NamespaceCacheTime(result.Namespace)
+				start := time.Now()
+				resources, err := c.GetResourcesInNamespace(ctx, result.Namespace)
+				result.DurationMs = time.Since(start).Milliseconds()
+
+				mu.Lock()
+				done++
+				if err != nil {
+					result.Error = err.Error()
+					if ctx.Err() == nil {
+						slog.WarnContext(ctx, "Failed to warm namespace", "namespace", result.Namespace, "error", err)
+					}
+				} else {
+					result.Resources = len(resources)
+					for _, resource := range resources {
+						result.Objects += resource.Count
+					}
+					result.Cached = !countedBefore.IsZero() && c.NamespaceCacheTime(result.Namespace).Equal(countedBefore)
+					slog.DebugContext(ctx, "Warmed namespace", "namespace", result.Namespace, "resources", len(resources),
+						"progress", done, "total", len(namespaces))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	fed := 0
+feed:
+	for ; fed < len(namespaces); fed++ {
+		select {
+		case indexes <- fed:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i := fed; i < len(namespaces); i++ {
+		results[i].Error = ctx.Err().Error()
+	}
+	return results
+}
+
+// RunPrewarmer prewarms the namespace caches at once and then every interval, or only once when
+// interval is 0, until ctx is done
+func (c *Client) RunPrewarmer(ctx context.Context, interval time.Duration) {
+	for {
+		if _, err := c.PrewarmNamespaces(ctx); err != nil && ctx.Err() == nil {
+			slog.WarnContext(ctx, "Prewarming failed", "error", err)
+		}
+		if interval <= 0 {
+			return
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
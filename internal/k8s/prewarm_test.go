@@ -0,0 +1,149 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newPrewarmTestClient(t *testing.T, namespaces ...string) *Client {
+	t.Helper()
+	client := newTestClient(
+		newTestObject("v1", "Pod", "team-a", "web-1", nil),
+		newTestObject("v1", "Pod", "team-b", "api-1", nil),
+		newTestObject("v1", "Pod", "team-b", "api-2", nil),
+	)
+	for _, name := range namespaces {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if _, err := client.clientset.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("failed to create namespace %s: %v", name, err)
+		}
+	}
+	return client
+}
+
+func TestPrewarmNamespaces(t *testing.T) {
+	client := newPrewarmTestClient(t, "team-a", "team-b", "empty")
+
+	warmed, err := client.PrewarmNamespaces(context.Background())
+	if err != nil {
+		t.Fatalf("PrewarmNamespaces returned error: %v", err)
+	}
+	if warmed != 3 {
+		t.Errorf("expected 3 warmed namespaces, got %d", warmed)
+	}
+
+	for namespace, pods := range map[string]int{"team-a": 1, "team-b": 2, "empty": 0} {
+		resources, _, exists := client.cachedNamespaceResources(namespace)
+		if !exists {
+			t.Errorf("expected %s to be cached", namespace)
+			continue
+		}
+		for _, resource := range resources {
+			if resource.Name == "pods" && resource.Count != pods {
+				t.Errorf("%s: expected %d pods, got %d", namespace, pods, resource.Count)
+			}
+		}
+	}
+}
+
+func TestWarmNamespaces(t *testing.T) {
+	client := newPrewarmTestClient(t)
+	if _, err := client.GetResourcesInNamespace(context.Background(), "team-a"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+
+	results := client.WarmNamespaces(context.Background(), []string{"team-b", "team-a", "empty"})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	for i, expected := range []struct {
+		namespace string
+		objects   int
+		cached    bool
+	}{
+		{"team-b", 2, false},
+		{"team-a", 1, true},
+		{"empty", 0, false},
+	} {
+		result := results[i]
+		if result.Namespace != expected.namespace || result.Objects != expected.objects || result.Cached != expected.cached {
+			t.Errorf("result %d = %+v, expected %s with %d objects, cached %v", i, result, expected.namespace, expected.objects, expected.cached)
+		}
+		if result.Resources == 0 || result.Error != "" {
+			t.Errorf("%s: expected counted resources without error, got %+v", result.Namespace, result)
+		}
+		if client.NamespaceCacheTime(result.Namespace).IsZero() {
+			t.Errorf("expected %s to be cached", result.Namespace)
+		}
+	}
+}
+
+func TestWarmNamespacesEmpty(t *testing.T) {
+	client := newPrewarmTestClient(t)
+
+	results := client.WarmNamespaces(context.Background(), nil)
+	if results == nil || len(results) != 0 {
+		t.Errorf("expected an empty, non-nil result, got %+v", results)
+	}
+}
+
+func TestWarmNamespacesOneAtATime(t *testing.T) {
+	client := newPrewarmTestClient(t)
+	client.countConcurrency = 1
+
+	var mu sync.Mutex
+	peak := int64(0)
+	fakeMetadataClient(client).PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		peak = max(peak, client.countLimiter.inFlight.Load())
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		return false, nil, nil
+	})
+
+	client.WarmNamespaces(context.Background(), []string{"team-a", "team-b", "team-c", "team-d"})
+	if peak != 1 {
+		t.Errorf("expected the warmer to hold 1 count slot at most, peak was %d", peak)
+	}
+}
+
+func TestRunPrewarmerStopsOnCancel(t *testing.T) {
+	client := newPrewarmTestClient(t, "team-a")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		client.RunPrewarmer(ctx, time.Hour)
+		close(done)
+	}()
+
+	// The first pass runs at once
+	deadline := time.Now().Add(5 * time.Second)
+	for client.NamespaceCacheTime("team-a").IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected team-a to be prewarmed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected RunPrewarmer to return after cancellation")
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.PrewarmNamespaces(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancellation error, got %v", err)
+	}
+}
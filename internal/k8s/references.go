@@ -0,0 +1,189 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ObjectReference describes an object referenced from another object's spec
+type ObjectReference struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Source    string `json:"source"` // where in the spec the reference was found
+	Exists    bool   `json:"exists"`
+}
+
+// GetObjectReferences resolves the ConfigMaps, Secrets and PVCs referenced by an object's pod spec
+func (c *Client) GetObjectReferences(ctx context.Context, namespace, resourceIdentifier, objectName string) ([]ObjectReference, error) {
+	rawObject, err := c.GetRawResourceObject(ctx, namespace, resourceIdentifier, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	podSpec := findPodSpec(rawObject)
+	if podSpec == nil {
+		return []ObjectReference{}, nil
+	}
+
+	refs := collectPodSpecReferences(namespace, podSpec)
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	// Check existence once per unique object, a spec often references the same one several times
+	existence := make(map[string]bool)
+	for i := range refs {
+		ref := &refs[i]
+		key := ref.Kind + "/" + ref.Name
+		if exists, checked := existence[key]; checked {
+			ref.Exists = exists
+			continue
+		}
+
+		exists, err := c.referenceExists(ctx, *ref)
+		if err != nil {
+			return nil, err
+		}
+		existence[key] = exists
+		ref.Exists = exists
+	}
+
+	return refs, nil
+}
+
+// referenceGVRs maps the kinds reported by GetObjectReferences to their core/v1 resources
+var referenceGVRs = map[string]schema.GroupVersionResource{
+	"ConfigMap":             {Version: "v1", Resource: "configmaps"},
+	"Secret":                {Version: "v1", Resource: "secrets"},
+	"PersistentVolumeClaim": {Version: "v1", Resource: "persistentvolumeclaims"},
+}
+
+// referenceExists checks whether the referenced object is present in the cluster
+func (c *Client) referenceExists(ctx context.Context, ref ObjectReference) (bool, error) {
+	gvr, ok := referenceGVRs[ref.Kind]
+	if !ok {
+		return false, fmt.Errorf("unsupported reference kind %s", ref.Kind)
+	}
+
+	_, err := c.dynamicClient.Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, wrapAPIError(err)
+	}
+	return true, nil
+}
+
+// findPodSpec locates the pod spec of Pods, pod-template workloads and CronJobs
+func findPodSpec(object map[string]interface{}) map[string]interface{} {
+	spec, _ := object["spec"].(map[string]interface{})
+	if spec == nil {
+		return nil
+	}
+
+	// CronJob: spec.jobTemplate.spec.template.spec
+	if jobTemplate, ok := spec["jobTemplate"].(map[string]interface{}); ok {
+		if jobSpec, ok := jobTemplate["spec"].(map[string]interface{}); ok {
+			spec = jobSpec
+		}
+	}
+
+	// Deployment, StatefulSet, DaemonSet, ReplicaSet, Job: spec.template.spec
+	if template, ok := spec["template"].(map[string]interface{}); ok {
+		if podSpec, ok := template["spec"].(map[string]interface{}); ok {
+			return podSpec
+		}
+		return nil
+	}
+
+	// Pod: spec itself
+	if _, ok := spec["containers"]; ok {
+		return spec
+	}
+
+	return nil
+}
+
+// collectPodSpecReferences walks volumes, envFrom, valueFrom and imagePullSecrets for references
+func collectPodSpecReferences(namespace string, podSpec map[string]interface{}) []ObjectReference {
+	refs := []ObjectReference{}
+	add := func(kind, name, source string) {
+		if name != "" {
+			refs = append(refs, ObjectReference{Kind: kind, Name: name, Namespace: namespace, Source: source})
+		}
+	}
+
+	for _, v := range asSlice(podSpec["volumes"]) {
+		volume, _ := v.(map[string]interface{})
+		volumeName, _ := volume["name"].(string)
+		source := fmt.Sprintf("volumes[%s]", volumeName)
+
+		add("ConfigMap", nestedString(volume, "configMap", "name"), source)
+		add("Secret", nestedString(volume, "secret", "secretName"), source)
+		add("PersistentVolumeClaim", nestedString(volume, "persistentVolumeClaim", "claimName"), source)
+
+		if projected, ok := volume["projected"].(map[string]interface{}); ok {
+			for _, s := range asSlice(projected["sources"]) {
+				projection, _ := s.(map[string]interface{})
+				add("ConfigMap", nestedString(projection, "configMap", "name"), source)
+				add("Secret", nestedString(projection, "secret", "name"), source)
+			}
+		}
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		for _, c := range asSlice(podSpec[field]) {
+			container, _ := c.(map[string]interface{})
+			containerName, _ := container["name"].(string)
+
+			for _, e := range asSlice(container["envFrom"]) {
+				envFrom, _ := e.(map[string]interface{})
+				source := fmt.Sprintf("%s[%s].envFrom", field, containerName)
+				add("ConfigMap", nestedString(envFrom, "configMapRef", "name"), source)
+				add("Secret", nestedString(envFrom, "secretRef", "name"), source)
+			}
+
+			for _, e := range asSlice(container["env"]) {
+				env, _ := e.(map[string]interface{})
+				envName, _ := env["name"].(string)
+				valueFrom, ok := env["valueFrom"].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				source := fmt.Sprintf("%s[%s].env[%s]", field, containerName, envName)
+				add("ConfigMap", nestedString(valueFrom, "configMapKeyRef", "name"), source)
+				add("Secret", nestedString(valueFrom, "secretKeyRef", "name"), source)
+			}
+		}
+	}
+
+	for _, s := range asSlice(podSpec["imagePullSecrets"]) {
+		pullSecret, _ := s.(map[string]interface{})
+		name, _ := pullSecret["name"].(string)
+		add("Secret", name, "imagePullSecrets")
+	}
+
+	return refs
+}
+
+// nestedString returns obj[parent][field] as a string, or "" when absent
+func nestedString(obj map[string]interface{}, parent, field string) string {
+	nested, ok := obj[parent].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := nested[field].(string)
+	return value
+}
+
+// asSlice returns v as a slice, or nil when it is not one
+func asSlice(v interface{}) []interface{} {
+	slice, _ := v.([]interface{})
+	return slice
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetObjectReferences(t *testing.T) {
+	deployment := newTestObject("apps/v1", "Deployment", "default", "web", map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"volumes": []interface{}{
+						map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "web-config"}},
+						map[string]interface{}{"name": "data", "persistentVolumeClaim": map[string]interface{}{"claimName": "web-data"}},
+					},
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name": "app",
+							"envFrom": []interface{}{
+								map[string]interface{}{"configMapRef": map[string]interface{}{"name": "missing-config"}},
+							},
+							"env": []interface{}{
+								map[string]interface{}{
+									"name":      "PASSWORD",
+									"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "web-secret", "key": "password"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	configMap := newTestObject("v1", "ConfigMap", "default", "web-config", nil)
+	secret := newTestObject("v1", "Secret", "default", "web-secret", nil)
+
+	client := newTestClient(deployment, configMap, secret)
+
+	refs, err := client.GetObjectReferences(context.Background(), "default", "deployments.apps", "web")
+	if err != nil {
+		t.Fatalf("GetObjectReferences returned error: %v", err)
+	}
+
+	expected := map[string]bool{
+		"ConfigMap/web-config":           true,
+		"PersistentVolumeClaim/web-data": false,
+		"ConfigMap/missing-config":       false,
+		"Secret/web-secret":              true,
+	}
+	if len(refs) != len(expected) {
+		t.Fatalf("expected %d references, got %d: %+v", len(expected), len(refs), refs)
+	}
+	for _, ref := range refs {
+		key := ref.Kind + "/" + ref.Name
+		exists, ok := expected[key]
+		if !ok {
+			t.Errorf("unexpected reference %s", key)
+			continue
+		}
+		if ref.Exists != exists {
+			t.Errorf("reference %s: expected exists=%t, got %t", key, exists, ref.Exists)
+		}
+		if ref.Namespace != "default" {
+			t.Errorf("reference %s: expected namespace default, got %s", key, ref.Namespace)
+		}
+	}
+}
+
+func TestGetObjectReferencesWithoutPodSpec(t *testing.T) {
+	configMap := newTestObject("v1", "ConfigMap", "default", "web-config", nil)
+	client := newTestClient(configMap)
+
+	refs, err := client.GetObjectReferences(context.Background(), "default", "configmaps", "web-config")
+	if err != nil {
+		t.Fatalf("GetObjectReferences returned error: %v", err)
+	}
+	if len(refs) != 0 {
+		t.Errorf("expected no references, got %+v", refs)
+	}
+}
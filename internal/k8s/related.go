@@ -0,0 +1,221 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Relationship types GetRelatedObjects groups its results by
+const (
+	// RelatedOwns holds the objects owned by the object, transitively: the ReplicaSets of a
+	// Deployment and the Pods of those ReplicaSets
+	RelatedOwns = "owns"
+	// RelatedSelects holds the Pods matched by a Service's selector
+	RelatedSelects = "selects"
+	// RelatedSelectedBy holds the Services whose selector matches the object's Pods
+	RelatedSelectedBy = "selectedBy"
+	// RelatedMounts holds the existing ConfigMaps, Secrets and PVCs referenced by the pod spec
+	RelatedMounts = "mounts"
+)
+
+// GetRelatedObjects resolves the objects related to a Deployment, ReplicaSet, Service or Pod, grouped
+// by relationship type. Every group is present, empty when nothing is related that way. Other kinds
+// are rejected with ErrInvalidArgument.
+func (c *Client) GetRelatedObjects(ctx context.Context, namespace, resourceIdentifier, name string) (map[string][]ObjectInfo, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	resource, err := c.findResource(resourceIdentifier, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	item, err := c.resourceClient(namespace, *resource).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	related := map[string][]ObjectInfo{
+		RelatedOwns:       {},
+		RelatedSelects:    {},
+		RelatedSelectedBy: {},
+		RelatedMounts:     {},
+	}
+
+	switch resource.APIGroup + "/" + resource.Kind {
+	case "apps/Deployment":
+		replicaSets, err := c.listOwned(ctx, namespace, "apps/v1", "ReplicaSet", item)
+		if err != nil {
+			return nil, err
+		}
+		for i := range replicaSets {
+			related[RelatedOwns] = append(related[RelatedOwns], toObjectInfo(&replicaSets[i]))
+			pods, err := c.listOwned(ctx, namespace, "v1", "Pod", &replicaSets[i])
+			if err != nil {
+				return nil, err
+			}
+			related[RelatedOwns] = append(related[RelatedOwns], toObjectInfos(pods)...)
+		}
+	case "apps/ReplicaSet":
+		pods, err := c.listOwned(ctx, namespace, "v1", "Pod", item)
+		if err != nil {
+			return nil, err
+		}
+		related[RelatedOwns] = toObjectInfos(pods)
+	case "/Service":
+		selector, _, _ := unstructured.NestedStringMap(item.Object, "spec", "selector")
+		pods, err := c.listSelected(ctx, namespace, selector)
+		if err != nil {
+			return nil, err
+		}
+		related[RelatedSelects] = toObjectInfos(pods)
+		return related, nil
+	case "/Pod":
+		// A Pod owns nothing; only its Services and mounts are related
+	default:
+		return nil, fmt.Errorf("%w: related objects are not supported for %s", ErrInvalidArgument, resource.FullName)
+	}
+
+	// The Pods of a workload carry its template labels, so Services select them by those
+	podLabels := item.GetLabels()
+	if resource.Kind != "Pod" {
+		podLabels, _, _ = unstructured.NestedStringMap(item.Object, "spec", "template", "metadata", "labels")
+	}
+	if related[RelatedSelectedBy], err = c.listSelectingServices(ctx, namespace, podLabels); err != nil {
+		return nil, err
+	}
+	if related[RelatedMounts], err = c.listMounted(ctx, namespace, item.Object); err != nil {
+		return nil, err
+	}
+	return related, nil
+}
+
+// listOwned lists the objects of a kind in namespace whose owner references point at owner. The
+// owner's selector narrows the list when it has one.
+func (c *Client) listOwned(ctx context.Context, namespace, apiVersion, kind string, owner *unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	resource, err := c.resourceForKind(apiVersion, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	options := metav1.ListOptions{}
+	if matchLabels, found, _ := unstructured.NestedStringMap(owner.Object, "spec", "selector", "matchLabels"); found {
+		options.LabelSelector = labels.SelectorFromSet(matchLabels).String()
+	}
+	list, err := c.listAllPages(ctx, c.resourceClient(namespace, *resource), options, 0)
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	owned := []unstructured.Unstructured{}
+	for _, item := range list.Items {
+		if ownedBy(item.GetOwnerReferences(), owner.GetUID()) {
+			owned = append(owned, item)
+		}
+	}
+	return owned, nil
+}
+
+// ownedBy reports whether one of refs points at the object with uid
+func ownedBy(refs []metav1.OwnerReference, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// listSelected lists the Pods in namespace matching a Service selector. An empty selector selects
+// nothing, as Services without one are backed by manually managed endpoints.
+func (c *Client) listSelected(ctx context.Context, namespace string, selector map[string]string) ([]unstructured.Unstructured, error) {
+	if len(selector) == 0 {
+		return nil, nil
+	}
+	pods, err := c.resourceForKind("v1", "Pod")
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.listAllPages(ctx, c.resourceClient(namespace, *pods), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	}, 0)
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+	return list.Items, nil
+}
+
+// listSelectingServices lists the Services in namespace whose selector matches podLabels
+func (c *Client) listSelectingServices(ctx context.Context, namespace string, podLabels map[string]string) ([]ObjectInfo, error) {
+	services := []ObjectInfo{}
+	if len(podLabels) == 0 {
+		return services, nil
+	}
+	resource, err := c.resourceForKind("v1", "Service")
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.listAllPages(ctx, c.resourceClient(namespace, *resource), metav1.ListOptions{}, 0)
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+	for i := range list.Items {
+		selector, _, _ := unstructured.NestedStringMap(list.Items[i].Object, "spec", "selector")
+		if len(selector) > 0 && labels.SelectorFromSet(selector).Matches(labels.Set(podLabels)) {
+			services = append(services, toObjectInfo(&list.Items[i]))
+		}
+	}
+	return services, nil
+}
+
+// listMounted fetches the ConfigMaps, Secrets and PVCs referenced by the pod spec of object, once
+// each. References to missing objects are left out; GetObjectReferences reports those. Secrets are
+// redacted like every other object response.
+func (c *Client) listMounted(ctx context.Context, namespace string, object map[string]interface{}) ([]ObjectInfo, error) {
+	mounted := []ObjectInfo{}
+	podSpec := findPodSpec(object)
+	if podSpec == nil {
+		return mounted, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, ref := range collectPodSpecReferences(namespace, podSpec) {
+		key := ref.Kind + "/" + ref.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		item, err := c.dynamicClient.Resource(referenceGVRs[ref.Kind]).Namespace(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, wrapAPIError(err)
+		}
+		cleanObject(item, ObjectOptions{})
+		mounted = append(mounted, toObjectInfo(item))
+	}
+	return mounted, nil
+}
+
+// toObjectInfos converts a list of unstructured objects to ObjectInfo
+func toObjectInfos(items []unstructured.Unstructured) []ObjectInfo {
+	objects := make([]ObjectInfo, len(items))
+	for i := range items {
+		objects[i] = toObjectInfo(&items[i])
+	}
+	return objects
+}
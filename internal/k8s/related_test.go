@@ -0,0 +1,145 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// newRelatedTestClient seeds a Deployment with one ReplicaSet and two Pods, a Service selecting
+// them, an unrelated Service and Pod, and the ConfigMap and Secret the pod template mounts
+func newRelatedTestClient() *Client {
+	podSpec := map[string]interface{}{
+		"containers": []interface{}{map[string]interface{}{
+			"name": "app",
+			"envFrom": []interface{}{
+				map[string]interface{}{"secretRef": map[string]interface{}{"name": "web-credentials"}},
+			},
+		}},
+		"volumes": []interface{}{
+			map[string]interface{}{"name": "config", "configMap": map[string]interface{}{"name": "web-config"}},
+			map[string]interface{}{"name": "gone", "configMap": map[string]interface{}{"name": "deleted"}},
+		},
+	}
+	selector := map[string]interface{}{"matchLabels": map[string]interface{}{"app": "web"}}
+	template := map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "web"}},
+		"spec":     podSpec,
+	}
+
+	deployment := newOwnedTestObject("apps/v1", "Deployment", "web")
+	deployment.Object["spec"] = map[string]interface{}{"selector": selector, "template": template}
+	replicaSet := newOwnedTestObject("apps/v1", "ReplicaSet", "web-7d9f")
+	replicaSet.Object["spec"] = map[string]interface{}{"selector": selector, "template": template}
+	replicaSet.SetLabels(map[string]string{"app": "web"})
+	setOwner(replicaSet, deployment)
+
+	var pods []*unstructured.Unstructured
+	for _, name := range []string{"web-7d9f-a", "web-7d9f-b"} {
+		pod := newOwnedTestObject("v1", "Pod", name)
+		pod.SetLabels(map[string]string{"app": "web"})
+		pod.Object["spec"] = podSpec
+		setOwner(pod, replicaSet)
+		pods = append(pods, pod)
+	}
+	other := newOwnedTestObject("v1", "Pod", "db-0")
+	other.SetLabels(map[string]string{"app": "db"})
+
+	service := newTestObject("v1", "Service", "default", "web", map[string]interface{}{
+		"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "web"}},
+	})
+	otherService := newTestObject("v1", "Service", "default", "db", map[string]interface{}{
+		"spec": map[string]interface{}{"selector": map[string]interface{}{"app": "db"}},
+	})
+	configMap := newTestObject("v1", "ConfigMap", "default", "web-config", nil)
+	secret := newTestObject("v1", "Secret", "default", "web-credentials", map[string]interface{}{
+		"data": map[string]interface{}{"token": "c2VjcmV0"},
+	})
+
+	return newTestClient(deployment, replicaSet, pods[0], pods[1], other, service, otherService, configMap, secret)
+}
+
+// relatedNames returns the kind/name of each object, sorted
+func relatedNames(objects []ObjectInfo) []string {
+	names := make([]string, len(objects))
+	for i, object := range objects {
+		names[i] = object.Kind + "/" + object.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestGetRelatedObjects(t *testing.T) {
+	client := newRelatedTestClient()
+
+	tests := []struct {
+		resource, name string
+		want           map[string][]string
+	}{
+		{"deployments", "web", map[string][]string{
+			RelatedOwns:       {"Pod/web-7d9f-a", "Pod/web-7d9f-b", "ReplicaSet/web-7d9f"},
+			RelatedSelects:    {},
+			RelatedSelectedBy: {"Service/web"},
+			RelatedMounts:     {"ConfigMap/web-config", "Secret/web-credentials"},
+		}},
+		{"replicasets", "web-7d9f", map[string][]string{
+			RelatedOwns:       {"Pod/web-7d9f-a", "Pod/web-7d9f-b"},
+			RelatedSelects:    {},
+			RelatedSelectedBy: {"Service/web"},
+			RelatedMounts:     {"ConfigMap/web-config", "Secret/web-credentials"},
+		}},
+		{"services", "web", map[string][]string{
+			RelatedOwns:       {},
+			RelatedSelects:    {"Pod/web-7d9f-a", "Pod/web-7d9f-b"},
+			RelatedSelectedBy: {},
+			RelatedMounts:     {},
+		}},
+		{"pods", "db-0", map[string][]string{
+			RelatedOwns:       {},
+			RelatedSelects:    {},
+			RelatedSelectedBy: {"Service/db"},
+			RelatedMounts:     {},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource+"/"+tt.name, func(t *testing.T) {
+			related, err := client.GetRelatedObjects(context.Background(), "default", tt.resource, tt.name)
+			if err != nil {
+				t.Fatalf("GetRelatedObjects returned error: %v", err)
+			}
+			if len(related) != len(tt.want) {
+				t.Errorf("expected groups %v, got %v", tt.want, related)
+			}
+			for group, want := range tt.want {
+				objects, found := related[group]
+				if !found || objects == nil {
+					t.Errorf("group %s is missing", group)
+					continue
+				}
+				got := relatedNames(objects)
+				if len(got) != len(want) {
+					t.Errorf("%s = %v, want %v", group, got, want)
+					continue
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Errorf("%s = %v, want %v", group, got, want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetRelatedObjectsUnsupportedKind(t *testing.T) {
+	client := newRelatedTestClient()
+
+	_, err := client.GetRelatedObjects(context.Background(), "default", "configmaps", "web-config")
+	if !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument for a ConfigMap, got %v", err)
+	}
+}
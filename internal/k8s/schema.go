@@ -0,0 +1,92 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// openAPIGroupVersionPath returns the key of a group version in the OpenAPI v3 discovery document
+func openAPIGroupVersionPath(resource ResourceInfo) string {
+	if resource.APIGroup == "" {
+		return "api/" + resource.APIVersion
+	}
+	return "apis/" + resource.APIGroup + "/" + resource.APIVersion
+}
+
+// GetResourceSchema returns the OpenAPI v3 schema of a resource's kind, as published by the API
+// server for built-in types and CRDs with a structural schema. References to other definitions
+// (such as ObjectMeta) are left as $ref. Schemas are cached until ClearCache. A resource whose
+// group version publishes no schema for its kind yields ErrResourceNotFound.
+func (c *Client) GetResourceSchema(resourceIdentifier string) (map[string]interface{}, error) {
+	if c.discoveryClient == nil {
+		return nil, fmt.Errorf("%w: no discovery client", ErrNoClient)
+	}
+
+	resource, err := c.findResource(resourceIdentifier, false)
+	if err != nil {
+		return nil, err
+	}
+
+	c.schemaMu.Lock()
+	cached, ok := c.schemaCache[resource.FullName]
+	c.schemaMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	paths, err := c.discoveryClient.OpenAPIV3().Paths()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OpenAPI schemas: %w", wrapAPIError(err))
+	}
+	groupVersion, ok := paths[openAPIGroupVersionPath(*resource)]
+	if !ok {
+		return nil, fmt.Errorf("%w: no OpenAPI schema published for %s/%s", ErrResourceNotFound, resource.APIGroup, resource.APIVersion)
+	}
+	data, err := groupVersion.Schema(runtime.ContentTypeJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch OpenAPI schema of %s: %w", resource.FullName, wrapAPIError(err))
+	}
+
+	schema, err := kindSchema(data, resource.APIGroup, resource.APIVersion, resource.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", resource.FullName, err)
+	}
+
+	c.schemaMu.Lock()
+	if c.schemaCache == nil {
+		c.schemaCache = make(map[string]map[string]interface{})
+	}
+	c.schemaCache[resource.FullName] = schema
+	c.schemaMu.Unlock()
+
+	return schema, nil
+}
+
+// openAPIDocument is the part of an OpenAPI v3 document schemas are looked up in
+type openAPIDocument struct {
+	Components struct {
+		Schemas map[string]map[string]interface{} `json:"schemas"`
+	} `json:"components"`
+}
+
+// kindSchema finds the schema of a kind in an OpenAPI v3 group version document by its
+// x-kubernetes-group-version-kind extension
+func kindSchema(data []byte, group, version, kind string) (map[string]interface{}, error) {
+	var document openAPIDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	for _, schema := range document.Components.Schemas {
+		gvks, _ := schema["x-kubernetes-group-version-kind"].([]interface{})
+		for _, entry := range gvks {
+			gvk, _ := entry.(map[string]interface{})
+			if gvk["group"] == group && gvk["version"] == version && gvk["kind"] == kind {
+				return schema, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: no OpenAPI schema published for kind %s", ErrResourceNotFound, kind)
+}
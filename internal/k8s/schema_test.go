@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/client-go/openapi"
+	"k8s.io/client-go/openapi/openapitest"
+)
+
+// testWidgetSchema is an OpenAPI v3 document of the example.com/v1 group version
+const testWidgetSchema = `{
+  "openapi": "3.0.0",
+  "components": {
+    "schemas": {
+      "com.example.v1.Widget": {
+        "type": "object",
+        "description": "Widget is an example custom resource",
+        "properties": {
+          "spec": {"type": "object", "properties": {"size": {"type": "integer"}}},
+          "metadata": {"$ref": "#/components/schemas/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"}
+        },
+        "x-kubernetes-group-version-kind": [{"group": "example.com", "kind": "Widget", "version": "v1"}]
+      },
+      "com.example.v1.WidgetList": {
+        "type": "object",
+        "x-kubernetes-group-version-kind": [{"group": "example.com", "kind": "WidgetList", "version": "v1"}]
+      },
+      "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {"type": "object"}
+    }
+  }
+}`
+
+// countingGroupVersion counts how often its schema is fetched
+type countingGroupVersion struct {
+	openapitest.FakeGroupVersion
+	fetches int
+}
+
+func (g *countingGroupVersion) Schema(contentType string) ([]byte, error) {
+	g.fetches++
+	return g.FakeGroupVersion.Schema(contentType)
+}
+
+func TestGetResourceSchema(t *testing.T) {
+	client := newTestClient()
+	widgets := &countingGroupVersion{FakeGroupVersion: openapitest.FakeGroupVersion{GVSpec: []byte(testWidgetSchema)}}
+	client.discoveryClient.(*testDiscovery).openAPI = &openapitest.FakeClient{
+		PathsMap: map[string]openapi.GroupVersion{"apis/example.com/v1": widgets},
+	}
+
+	schema, err := client.GetResourceSchema("widgets.example.com")
+	if err != nil {
+		t.Fatalf("GetResourceSchema returned error: %v", err)
+	}
+	if schema["description"] != "Widget is an example custom resource" {
+		t.Errorf("expected the Widget schema, got %v", schema)
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	if _, ok := properties["spec"]; !ok {
+		t.Errorf("expected spec in schema properties, got %v", properties)
+	}
+
+	// Schemas are cached
+	if _, err := client.GetResourceSchema("widgets.example.com"); err != nil {
+		t.Fatalf("GetResourceSchema returned error: %v", err)
+	}
+	if widgets.fetches != 1 {
+		t.Errorf("expected the schema to be fetched once, got %d fetches", widgets.fetches)
+	}
+
+	client.ClearCache()
+	if _, err := client.GetResourceSchema("widgets.example.com"); err != nil {
+		t.Fatalf("GetResourceSchema returned error: %v", err)
+	}
+	if widgets.fetches != 2 {
+		t.Errorf("expected ClearCache to drop cached schemas, got %d fetches", widgets.fetches)
+	}
+}
+
+func TestGetResourceSchemaNotPublished(t *testing.T) {
+	client := newTestClient()
+	client.discoveryClient.(*testDiscovery).openAPI = &openapitest.FakeClient{
+		PathsMap: map[string]openapi.GroupVersion{
+			"apis/example.com/v1": openapitest.FakeGroupVersion{GVSpec: []byte(`{"components": {"schemas": {}}}`)},
+		},
+	}
+
+	// No document for the group version
+	if _, err := client.GetResourceSchema("deployments"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound for a group version without schema, got %v", err)
+	}
+	// A document without the kind
+	if _, err := client.GetResourceSchema("widgets.example.com"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound for a kind without schema, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// SearchObjectsByName finds the objects of namespace whose name contains query, case-insensitively,
+// across every resource type that holds objects. The populated types come from the cached counts of
+// GetResourcesInNamespace; they are listed metadata-only, countConcurrency at a time and each
+// holding a slot of the global count limit. Types that cannot be listed are logged and skipped.
+// Results are sorted by kind and then by name.
+func (c *Client) SearchObjectsByName(ctx context.Context, namespace, query string) ([]ObjectInfo, error) {
+	if c.metadataClient == nil {
+		return nil, fmt.Errorf("%w: no metadata client", ErrNoClient)
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, fmt.Errorf("%w: empty search query", ErrInvalidArgument)
+	}
+
+	resources, err := c.GetResourcesInNamespace(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	var populated []ResourceInfo
+	for _, resource := range resources {
+		if resource.Count > 0 {
+			populated = append(populated, resource)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	matches := []ObjectInfo{}
+
+	queue := make(chan ResourceInfo)
+	var wg sync.WaitGroup
+	for w := 0; w < c.countConcurrency && w < len(populated); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for resource := range queue {
+				found, err := c.searchResource(ctx, namespace, resource, query)
+				if err != nil {
+					slog.WarnContext(ctx, "Skipping resource in search", "namespace", namespace, "resource", resource.FullName, "error", err)
+					continue
+				}
+				mu.Lock()
+				matches = append(matches, found...)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, resource := range populated {
+		queue <- resource
+	}
+	close(queue)
+	wg.Wait()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Kind != matches[j].Kind {
+			return matches[i].Kind < matches[j].Kind
+		}
+		return matches[i].Name < matches[j].Name
+	})
+
+	slog.DebugContext(ctx, "Searched objects by name", "namespace", namespace, "query", query,
+		"resources", len(populated), "matches", len(matches))
+	return matches, nil
+}
+
+// searchResource lists the metadata of one resource type and returns the objects whose lowercased
+// name contains query
+func (c *Client) searchResource(ctx context.Context, namespace string, resource ResourceInfo, query string) ([]ObjectInfo, error) {
+	if err := c.countLimiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.countLimiter.release()
+
+	gvr := schema.GroupVersionResource{Group: resource.APIGroup, Version: resource.APIVersion, Resource: resource.Name}
+	if !resource.Namespaced {
+		namespace = ""
+	}
+	objects, err := c.listMetadataPages(ctx, gvr, namespace, "")
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	apiVersion := gvr.GroupVersion().String()
+	var matches []ObjectInfo
+	for _, object := range objects {
+		if !strings.Contains(strings.ToLower(object.Name), query) {
+			continue
+		}
+		matches = append(matches, ObjectInfo{
+			Name:                  object.Name,
+			Namespace:             object.Namespace,
+			Kind:                  resource.Kind,
+			APIVersion:            apiVersion,
+			CreationTimestamp:     object.CreationTimestamp,
+			CreationTimestampUnix: object.CreationTimestamp.Unix(),
+			Age:                   formatAge(object.CreationTimestamp),
+			Labels:                object.Labels,
+			Annotations:           object.Annotations,
+			OwnerReferences:       object.OwnerReferences,
+		})
+	}
+	return matches, nil
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSearchObjectsByName(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "payments-api-7d9f", nil),
+		newTestObject("v1", "Service", "default", "Payments-API", nil),
+		newTestObject("apps/v1", "Deployment", "default", "payments-api", nil),
+		newTestObject("v1", "ConfigMap", "default", "settings", nil),
+		newTestObject("v1", "Pod", "other", "payments-api-1", nil),
+	)
+
+	matches, err := client.SearchObjectsByName(context.Background(), "default", "payments")
+	if err != nil {
+		t.Fatalf("SearchObjectsByName returned error: %v", err)
+	}
+
+	expected := []struct{ kind, apiVersion, name string }{
+		{"Deployment", "apps/v1", "payments-api"},
+		{"Pod", "v1", "payments-api-7d9f"},
+		{"Service", "v1", "Payments-API"},
+	}
+	if len(matches) != len(expected) {
+		t.Fatalf("expected %d matches, got %+v", len(expected), matches)
+	}
+	for i, e := range expected {
+		m := matches[i]
+		if m.Kind != e.kind || m.APIVersion != e.apiVersion || m.Name != e.name || m.Namespace != "default" {
+			t.Errorf("match %d = %s %s %s/%s, expected %s %s default/%s", i, m.APIVersion, m.Kind, m.Namespace, m.Name, e.apiVersion, e.kind, e.name)
+		}
+	}
+}
+
+func TestSearchObjectsByNameNoMatches(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "ConfigMap", "default", "settings", nil))
+
+	matches, err := client.SearchObjectsByName(context.Background(), "default", "payments")
+	if err != nil {
+		t.Fatalf("SearchObjectsByName returned error: %v", err)
+	}
+	if matches == nil || len(matches) != 0 {
+		t.Errorf("expected an empty, non-nil result, got %+v", matches)
+	}
+}
+
+func TestSearchObjectsByNameEmptyQuery(t *testing.T) {
+	client := newTestClient()
+	if _, err := client.SearchObjectsByName(context.Background(), "default", "  "); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument for an empty query, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package k8s
+
+import (
+	"errors"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// CountState is the progress of counting the resources of a namespace
+type CountState string
+
+const (
+	CountStateUnknown  CountState = "unknown" // never counted since the client started
+	CountStateCounting CountState = "counting"
+	CountStateComplete CountState = "complete"
+	CountStateError    CountState = "error"
+)
+
+// CountStatus is the outcome of counting one resource type, which tells an empty resource apart
+// from one whose count failed and was reported as 0
+type CountStatus string
+
+const (
+	CountStatusOK        CountStatus = "ok"
+	CountStatusForbidden CountStatus = "forbidden"
+	CountStatusTimeout   CountStatus = "timeout"
+	CountStatusError     CountStatus = "error"
+)
+
+// countStatusOf classifies a count error
+func countStatusOf(err error) CountStatus {
+	switch {
+	case err == nil:
+		return CountStatusOK
+	case errors.Is(err, ErrForbidden), apierrors.IsForbidden(err):
+		return CountStatusForbidden
+	case errors.Is(err, ErrTimeout):
+		return CountStatusTimeout
+	default:
+		return CountStatusError
+	}
+}
+
+// NamespaceStatus reports whether the resource counts of a namespace are complete or still being counted
+type NamespaceStatus struct {
+	Namespace   string     `json:"namespace"`
+	State       CountState `json:"state"`
+	StartedAt   time.Time  `json:"startedAt"`
+	CompletedAt time.Time  `json:"completedAt"`
+	Resources   int        `json:"resources"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// GetNamespaceStatus returns the counting status of a namespace
+func (c *Client) GetNamespaceStatus(namespace string) NamespaceStatus {
+	c.namespaceMu.RLock()
+	defer c.namespaceMu.RUnlock()
+
+	status, exists := c.namespaceStatus[namespace]
+	if !exists {
+		return NamespaceStatus{Namespace: namespace, State: CountStateUnknown}
+	}
+	return status
+}
+
+// markCounting records that a count of the namespace has started
+func (c *Client) markCounting(namespace string) {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+
+	c.namespaceStatus[namespace] = NamespaceStatus{
+		Namespace: namespace,
+		State:     CountStateCounting,
+		StartedAt: time.Now(),
+	}
+}
+
+// markCountDone records the outcome of a count started with markCounting
+func (c *Client) markCountDone(namespace string, resources int, err error) {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+
+	status := c.namespaceStatus[namespace]
+	status.Namespace = namespace
+	status.CompletedAt = time.Now()
+	status.Resources = resources
+	status.State = CountStateComplete
+	status.Error = ""
+	if err != nil {
+		status.State = CountStateError
+		status.Error = err.Error()
+	}
+	c.namespaceStatus[namespace] = status
+}
@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNamespaceStatusTransitions(t *testing.T) {
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+
+	if status := client.GetNamespaceStatus("default"); status.State != CountStateUnknown {
+		t.Fatalf("expected unknown before counting, got %s", status.State)
+	}
+
+	// Hold the pods count until the in-progress status has been checked
+	listing := make(chan struct{})
+	release := make(chan struct{})
+	fakeMetadataClient(client).PrependReactor("list", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			close(listing)
+			<-release
+			return false, nil, nil
+		})
+
+	done := make(chan error)
+	go func() {
+		_, err := client.GetResourcesInNamespace(context.Background(), "default")
+		done <- err
+	}()
+
+	<-listing
+	status := client.GetNamespaceStatus("default")
+	if status.State != CountStateCounting {
+		t.Errorf("expected counting while listing, got %s", status.State)
+	}
+	if status.StartedAt.IsZero() {
+		t.Error("expected start time while counting")
+	}
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	status = client.GetNamespaceStatus("default")
+	if status.State != CountStateComplete {
+		t.Errorf("expected complete after counting, got %s", status.State)
+	}
+	if status.Resources == 0 || status.CompletedAt.Before(status.StartedAt) {
+		t.Errorf("expected resources and a completion time after the start, got %+v", status)
+	}
+}
+
+func TestNamespaceStatusError(t *testing.T) {
+	client := newTestClient()
+	client.discoveryClient.(*testDiscovery).err = errors.New("discovery unavailable")
+
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err == nil {
+		t.Fatal("expected discovery error")
+	}
+	status := client.GetNamespaceStatus("default")
+	if status.State != CountStateError || status.Error == "" {
+		t.Errorf("expected error state with message, got %+v", status)
+	}
+}
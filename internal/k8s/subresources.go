@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"log/slog"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// discoverSubresources returns the subresources of the group versions in preferred, which
+// ServerPreferredResources leaves out. They come from one ServerGroupsAndResources call; when that
+// fails they are logged and left out, since they are informational.
+func (c *Client) discoverSubresources(preferred []*metav1.APIResourceList) []*metav1.APIResourceList {
+	known := make(map[string]bool)
+	for _, list := range preferred {
+		for _, resource := range list.APIResources {
+			known[list.GroupVersion+"/"+resource.Name] = true
+		}
+	}
+
+	_, allLists, err := c.discoveryClient.ServerGroupsAndResources()
+	if err != nil && len(allLists) == 0 {
+		slog.Warn("Failed to discover subresources", "error", err)
+		return nil
+	}
+
+	var subresources []*metav1.APIResourceList
+	for _, list := range allLists {
+		var found []metav1.APIResource
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") && !known[list.GroupVersion+"/"+resource.Name] {
+				found = append(found, resource)
+			}
+		}
+		if len(found) > 0 && servesGroupVersion(preferred, list.GroupVersion) {
+			subresources = append(subresources, &metav1.APIResourceList{GroupVersion: list.GroupVersion, APIResources: found})
+		}
+	}
+	return subresources
+}
+
+// servesGroupVersion reports whether lists include groupVersion
+func servesGroupVersion(lists []*metav1.APIResourceList, groupVersion string) bool {
+	for _, list := range lists {
+		if list.GroupVersion == groupVersion {
+			return true
+		}
+	}
+	return false
+}
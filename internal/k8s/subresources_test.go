@@ -0,0 +1,57 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIncludeSubresources(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		if enabled {
+			t.Setenv("INCLUDE_SUBRESOURCES", "true")
+		}
+		client := newTestClient(newTestObject("apps/v1", "Deployment", "default", "web", nil))
+
+		resources, err := client.GetAPIResources()
+		if err != nil {
+			t.Fatalf("GetAPIResources returned error: %v", err)
+		}
+		subresources := make(map[string]ResourceInfo)
+		for _, resource := range resources {
+			if resource.Subresource {
+				subresources[resource.FullName] = resource
+			}
+		}
+
+		if !enabled {
+			if len(subresources) > 0 {
+				t.Errorf("subresources discovered without INCLUDE_SUBRESOURCES: %v", subresources)
+			}
+			continue
+		}
+		scale, found := subresources["deployments/scale.apps"]
+		if !found || scale.Kind != "Scale" || scale.APIGroup != "apps" || scale.APIVersion != "v1" {
+			t.Errorf("expected deployments/scale.apps of kind Scale in apps/v1, got %+v", subresources)
+		}
+		if _, found := subresources["statefulsets/scale.apps"]; !found {
+			t.Errorf("expected statefulsets/scale.apps, got %v", subresources)
+		}
+
+		// Counting and object lookups leave subresources out
+		counted, err := client.GetResourcesInNamespace(context.Background(), "default")
+		if err != nil {
+			t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+		}
+		for _, resource := range counted {
+			if resource.Subresource {
+				t.Errorf("subresource %s was counted", resource.FullName)
+			}
+		}
+		if _, err := client.findResource("deployments/scale", true); err == nil {
+			t.Error("findResource resolved a subresource")
+		}
+		if resource, err := client.resourceForKind("apps/v1", "Deployment"); err != nil || resource.Subresource {
+			t.Errorf("resourceForKind(Deployment) returned %+v, error: %v", resource, err)
+		}
+	}
+}
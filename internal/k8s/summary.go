@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+)
+
+// ResourceCount is a populated resource type in a ResourceSummary
+type ResourceCount struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+	Kind     string `json:"kind"`
+	APIGroup string `json:"apiGroup"`
+	Count    int    `json:"count"`
+}
+
+// ResourceSummary lists the populated resource types of a namespace, most objects first, with the
+// total object count across them
+type ResourceSummary struct {
+	Namespace string          `json:"namespace"`
+	Resources []ResourceCount `json:"resources"`
+	Total     int             `json:"total"`
+}
+
+// GetResourceSummary summarizes the object counts of a namespace. It reads the same cached counts as
+// GetResourcesInNamespace, so it only lists the API server when those counts are missing or expired.
+// ClusterScope summarizes cluster-scoped resources and AllNamespaces sums across namespaces.
+func (c *Client) GetResourceSummary(ctx context.Context, namespace string) (*ResourceSummary, error) {
+	var resources []ResourceInfo
+	var err error
+	if namespace == AllNamespaces {
+		resources, err = c.GetResourceCountsAllNamespaces(ctx)
+	} else {
+		resources, err = c.GetResourcesInNamespace(ctx, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizeResources(resources)
+	summary.Namespace = namespace
+	return summary, nil
+}
+
+// summarizeResources keeps the resources with objects, sorted by descending count and then by full name
+func summarizeResources(resources []ResourceInfo) *ResourceSummary {
+	summary := &ResourceSummary{Resources: []ResourceCount{}}
+	for _, resource := range resources {
+		if resource.Count <= 0 {
+			continue
+		}
+		summary.Resources = append(summary.Resources, ResourceCount{
+			Name:     resource.Name,
+			FullName: resource.FullName,
+			Kind:     resource.Kind,
+			APIGroup: resource.APIGroup,
+			Count:    resource.Count,
+		})
+		summary.Total += resource.Count
+	}
+
+	sort.Slice(summary.Resources, func(i, j int) bool {
+		a, b := summary.Resources[i], summary.Resources[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.FullName < b.FullName
+	})
+	return summary
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSummarizeResourcesExcludesEmpty(t *testing.T) {
+	summary := summarizeResources([]ResourceInfo{
+		{Name: "configmaps", FullName: "configmaps", Count: 2},
+		{Name: "secrets", FullName: "secrets", Count: 0},
+		{Name: "pods", FullName: "pods", Count: 7},
+		{Name: "deployments", FullName: "deployments.apps", Count: 2},
+		{Name: "widgets", FullName: "widgets.example.com", Count: 0},
+	})
+
+	expected := []string{"pods", "configmaps", "deployments.apps"}
+	if len(summary.Resources) != len(expected) {
+		t.Fatalf("expected %d resources, got %+v", len(expected), summary.Resources)
+	}
+	for i, fullName := range expected {
+		if summary.Resources[i].FullName != fullName {
+			t.Errorf("position %d: expected %s, got %s", i, fullName, summary.Resources[i].FullName)
+		}
+	}
+	if summary.Total != 11 {
+		t.Errorf("expected total 11, got %d", summary.Total)
+	}
+}
+
+func TestSummarizeResourcesNonePopulated(t *testing.T) {
+	summary := summarizeResources([]ResourceInfo{{Name: "pods", Count: 0}})
+	if summary.Resources == nil || len(summary.Resources) != 0 || summary.Total != 0 {
+		t.Errorf("expected an empty, non-nil summary, got %+v", summary)
+	}
+}
+
+func TestGetResourceSummaryUsesCachedCounts(t *testing.T) {
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web-1", nil),
+		newTestObject("v1", "Pod", "default", "web-2", nil),
+		newTestObject("v1", "ConfigMap", "default", "settings", nil),
+		newTestObject("v1", "Pod", "other", "db", nil),
+	)
+
+	summary, err := client.GetResourceSummary(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourceSummary returned error: %v", err)
+	}
+	if summary.Namespace != "default" || summary.Total != 3 {
+		t.Errorf("expected 3 objects in default, got %+v", summary)
+	}
+	if len(summary.Resources) != 2 || summary.Resources[0].Name != "pods" || summary.Resources[0].Count != 2 {
+		t.Fatalf("expected pods then configmaps, got %+v", summary.Resources)
+	}
+	for _, resource := range summary.Resources {
+		if resource.Count == 0 {
+			t.Errorf("summary includes empty resource %s", resource.FullName)
+		}
+	}
+
+	// A second summary is served from the namespace cache without listing again
+	fakeMetadataClient(client).ClearActions()
+	if _, err := client.GetResourceSummary(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourceSummary returned error: %v", err)
+	}
+	if actions := fakeMetadataClient(client).Actions(); len(actions) != 0 {
+		t.Errorf("expected cached counts, got %d API calls", len(actions))
+	}
+}
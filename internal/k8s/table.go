@@ -0,0 +1,196 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// tableAccept asks the API server for a server-side printed Table, the format kubectl get uses
+const tableAccept = "application/json;as=Table;g=meta.k8s.io;v=v1"
+
+// TableColumn describes a column of a TableResult
+type TableColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Priority 0 columns are shown by default, higher ones only in wide output
+	Priority int32 `json:"priority"`
+}
+
+// TableRow is one object of a TableResult, with a cell per column
+type TableRow struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Cells     []interface{} `json:"cells"`
+}
+
+// TableResult holds the columns and rows of a resource listing as kubectl would print them
+type TableResult struct {
+	Columns []TableColumn `json:"columns"`
+	Rows    []TableRow    `json:"rows"`
+	// Fallback is set when the server cannot print the resource as a Table and the rows were built
+	// from a plain list, with only Name and Age columns
+	Fallback bool `json:"fallback"`
+
+	// continueToken is the token of the page after the last one read when MAX_OBJECTS stopped listing
+	continueToken string
+}
+
+// GetResourceTable lists the objects of a resource type as a Table, with the columns the API server
+// computes for kubectl get (Ready, Status, Restarts, Age for Pods; printer columns for custom
+// resources). Servers that cannot print the resource, such as some aggregated APIs, get a plain
+// list instead, rendered with Name and Age columns. Listings above MAX_OBJECTS are refused like
+// GetResourceObjectList does.
+func (c *Client) GetResourceTable(ctx context.Context, namespace, resourceIdentifier string) (*TableResult, error) {
+	if c.restClient == nil {
+		return nil, fmt.Errorf("%w: no REST client", ErrNoClient)
+	}
+	// The namespace becomes a segment of the request path, so it must be a plain name
+	if namespace != "" && namespace != ClusterScope && len(validation.IsDNS1123Label(namespace)) > 0 {
+		return nil, fmt.Errorf("%w: invalid namespace %q", ErrInvalidArgument, namespace)
+	}
+
+	targetResource, err := c.findResource(resourceIdentifier, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	result, err := c.listTablePages(ctx, namespace, *targetResource)
+	if apierrors.IsNotAcceptable(err) || apierrors.IsUnsupportedMediaType(err) || (err == nil && result == nil) {
+		slog.DebugContext(ctx, "Server cannot print resource as Table, falling back to a plain list",
+			"namespace", namespace, "resource", targetResource.FullName)
+		return c.tableFromObjects(ctx, namespace, targetResource.FullName)
+	}
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+	if c.maxObjects > 0 && len(result.Rows) > c.maxObjects {
+		// Count the rest from metadata for the error message, like GetResourceObjectList
+		count := len(result.Rows)
+		if result.continueToken != "" {
+			rest, _, err := c.countAllPages(ctx, c.metadataResource(namespace, *targetResource), metav1.ListOptions{Continue: result.continueToken})
+			if err == nil {
+				count += rest
+			}
+		}
+		return nil, &TooManyObjectsError{Resource: targetResource.FullName, Count: count, Limit: c.maxObjects}
+	}
+	return result, nil
+}
+
+// listTablePages requests the Table of a resource page by page and merges the rows. It returns a
+// nil result when the server answered with something other than a Table. Listing stops once more
+// than MAX_OBJECTS rows were read.
+func (c *Client) listTablePages(ctx context.Context, namespace string, resource ResourceInfo) (*TableResult, error) {
+	result := &TableResult{Columns: []TableColumn{}, Rows: []TableRow{}}
+	continueToken := ""
+	for {
+		request := c.restClient.Get().
+			AbsPath(resourcePath(namespace, resource)).
+			SetHeader("Accept", tableAccept).
+			Param("limit", strconv.FormatInt(c.listChunkSize, 10))
+		if continueToken != "" {
+			request = request.Param("continue", continueToken)
+		}
+		raw, err := request.Do(ctx).Raw()
+		if err != nil {
+			return nil, err
+		}
+
+		var table metav1.Table
+		if err := json.Unmarshal(raw, &table); err != nil {
+			return nil, fmt.Errorf("decoding table: %w", err)
+		}
+		if table.Kind != "Table" {
+			return nil, nil
+		}
+
+		if continueToken == "" {
+			for _, column := range table.ColumnDefinitions {
+				result.Columns = append(result.Columns, TableColumn{
+					Name:        column.Name,
+					Type:        column.Type,
+					Format:      column.Format,
+					Description: column.Description,
+					Priority:    column.Priority,
+				})
+			}
+		}
+		for _, row := range table.Rows {
+			result.Rows = append(result.Rows, toTableRow(row))
+		}
+
+		if c.maxObjects > 0 && len(result.Rows) > c.maxObjects {
+			result.continueToken = table.Continue
+			return result, nil
+		}
+		if table.Continue == "" {
+			return result, nil
+		}
+		continueToken = table.Continue
+	}
+}
+
+// toTableRow converts a Table row, reading the name and namespace from the PartialObjectMetadata
+// the server includes by default
+func toTableRow(row metav1.TableRow) TableRow {
+	var object metav1.PartialObjectMetadata
+	if len(row.Object.Raw) > 0 {
+		json.Unmarshal(row.Object.Raw, &object)
+	}
+	cells := row.Cells
+	if cells == nil {
+		cells = []interface{}{}
+	}
+	return TableRow{Name: object.Name, Namespace: object.Namespace, Cells: cells}
+}
+
+// tableFromObjects builds a Name/Age table from a plain listing, as kubectl does for resources
+// without printer columns
+func (c *Client) tableFromObjects(ctx context.Context, namespace, resourceIdentifier string) (*TableResult, error) {
+	list, err := c.GetResourceObjectList(ctx, namespace, resourceIdentifier, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TableResult{
+		Columns: []TableColumn{
+			{Name: "Name", Type: "string", Format: "name", Description: "Name of the object"},
+			{Name: "Age", Type: "string", Description: "Time since the object was created"},
+		},
+		Rows:     make([]TableRow, len(list.Items)),
+		Fallback: true,
+	}
+	for i, object := range list.Items {
+		result.Rows[i] = TableRow{
+			Name:      object.Name,
+			Namespace: object.Namespace,
+			Cells:     []interface{}{object.Name, object.Age},
+		}
+	}
+	return result, nil
+}
+
+// resourcePath returns the API path listing a resource, within namespace when it is namespaced
+func resourcePath(namespace string, resource ResourceInfo) string {
+	prefix := "/apis/" + resource.APIGroup + "/" + resource.APIVersion
+	if resource.APIGroup == "" {
+		prefix = "/api/" + resource.APIVersion
+	}
+	if resource.Namespaced && namespace != "" {
+		return path.Join(prefix, "namespaces", namespace, resource.Name)
+	}
+	return path.Join(prefix, resource.Name)
+}
@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+)
+
+// fakeTableResponse is a Pod Table as the API server prints it for kubectl get
+const fakeTableResponse = `{
+	"kind": "Table",
+	"apiVersion": "meta.k8s.io/v1",
+	"columnDefinitions": [
+		{"name": "Name", "type": "string", "format": "name", "priority": 0},
+		{"name": "Ready", "type": "string", "priority": 0},
+		{"name": "Status", "type": "string", "priority": 0},
+		{"name": "Restarts", "type": "string", "priority": 0},
+		{"name": "Age", "type": "string", "priority": 0},
+		{"name": "IP", "type": "string", "priority": 1}
+	],
+	"rows": [
+		{
+			"cells": ["web-1", "1/1", "Running", "0", "5d", "10.0.0.7"],
+			"object": {"kind": "PartialObjectMetadata", "apiVersion": "meta.k8s.io/v1", "metadata": {"name": "web-1", "namespace": "default"}}
+		}
+	]
+}`
+
+// newTableTestClient returns a test client whose raw requests go to a fake API server that serves a
+// Table for Pods, a plain list for widgets and 406 for ConfigMaps. It records the Accept header
+// and path of every request.
+func newTableTestClient(t *testing.T, objects ...runtime.Object) (*Client, *[]string) {
+	t.Helper()
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path+" "+r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/pods"):
+			w.Write([]byte(fakeTableResponse))
+		case strings.HasSuffix(r.URL.Path, "/widgets"):
+			w.Write([]byte(`{"kind":"WidgetList","apiVersion":"example.com/v1","items":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotAcceptable)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotAcceptable","code":406}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := newTestClient(objects...)
+	restClient, err := newRESTClient(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("newRESTClient returned error: %v", err)
+	}
+	client.restClient = restClient
+	return client, &requests
+}
+
+func TestGetResourceTable(t *testing.T) {
+	client, requests := newTableTestClient(t)
+
+	table, err := client.GetResourceTable(context.Background(), "default", "pods")
+	if err != nil {
+		t.Fatalf("GetResourceTable returned error: %v", err)
+	}
+	if table.Fallback {
+		t.Error("expected a server-printed table, got the fallback")
+	}
+	if len(table.Columns) != 6 || table.Columns[1].Name != "Ready" || table.Columns[5].Priority != 1 {
+		t.Errorf("unexpected columns %+v", table.Columns)
+	}
+	if len(table.Rows) != 1 || table.Rows[0].Name != "web-1" || table.Rows[0].Namespace != "default" {
+		t.Fatalf("unexpected rows %+v", table.Rows)
+	}
+	if cells := table.Rows[0].Cells; len(cells) != 6 || cells[2] != "Running" || cells[3] != "0" {
+		t.Errorf("unexpected cells %v", cells)
+	}
+
+	if len(*requests) != 1 || (*requests)[0] != "/api/v1/namespaces/default/pods "+tableAccept {
+		t.Errorf("expected one Table request for the pods path, got %v", *requests)
+	}
+}
+
+func TestGetResourceTableFallback(t *testing.T) {
+	client, _ := newTableTestClient(t,
+		newTestObject("example.com/v1", "Widget", "default", "sprocket", nil),
+		newTestObject("v1", "ConfigMap", "default", "settings", nil),
+	)
+
+	for _, resource := range []string{"widgets.example.com", "configmaps"} {
+		table, err := client.GetResourceTable(context.Background(), "default", resource)
+		if err != nil {
+			t.Fatalf("%s: GetResourceTable returned error: %v", resource, err)
+		}
+		if !table.Fallback {
+			t.Errorf("%s: expected the plain-list fallback", resource)
+		}
+		if len(table.Columns) != 2 || table.Columns[0].Name != "Name" || table.Columns[1].Name != "Age" {
+			t.Errorf("%s: unexpected fallback columns %+v", resource, table.Columns)
+		}
+		if len(table.Rows) != 1 || len(table.Rows[0].Cells) != 2 {
+			t.Errorf("%s: unexpected fallback rows %+v", resource, table.Rows)
+		}
+	}
+}
+
+func TestGetResourceTableInvalidNamespace(t *testing.T) {
+	client, requests := newTableTestClient(t)
+
+	for _, namespace := range []string{"../../apis", "default/pods", "Not_Valid"} {
+		_, err := client.GetResourceTable(context.Background(), namespace, "pods")
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%q: expected ErrInvalidArgument, got %v", namespace, err)
+		}
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no API requests, got %v", *requests)
+	}
+}
+
+func TestGetResourceTableWithoutRESTClient(t *testing.T) {
+	client := newTestClient()
+
+	if _, err := client.GetResourceTable(context.Background(), "default", "pods"); !errors.Is(err, ErrNoClient) {
+		t.Errorf("expected ErrNoClient, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// defaultGlobalCountLimit is the number of count operations in flight across all scans when
+// GLOBAL_COUNT_LIMIT is not set
+const defaultGlobalCountLimit int64 = 32
+
+// countLimiter bounds the count operations running at once across every namespace scan, total and
+// request of a client. COUNT_CONCURRENCY only bounds the workers of one scan, so without it several
+// users scanning at the same time multiply the load on the API server.
+type countLimiter struct {
+	slots    chan struct{}
+	inFlight atomic.Int64
+}
+
+func newCountLimiter(limit int) *countLimiter {
+	return &countLimiter{slots: make(chan struct{}, limit)}
+}
+
+// acquire waits for a free slot, giving up when ctx is done. A nil limiter does not limit.
+func (l *countLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		l.inFlight.Add(1)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot taken by a successful acquire
+func (l *countLimiter) release() {
+	if l == nil {
+		return
+	}
+	l.inFlight.Add(-1)
+	<-l.slots
+}
+
+// CountsInFlight returns the number of count operations currently running and the limit they share
+// (GLOBAL_COUNT_LIMIT)
+func (c *Client) CountsInFlight() (inFlight, limit int) {
+	if c.countLimiter == nil {
+		return 0, 0
+	}
+	return int(c.countLimiter.inFlight.Load()), cap(c.countLimiter.slots)
+}
+
+// CountConcurrency returns the number of resource types one namespace scan counts in parallel
+// (COUNT_CONCURRENCY)
+func (c *Client) CountConcurrency() int {
+	return c.countConcurrency
+}
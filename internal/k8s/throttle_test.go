@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCountLimiterBoundsConcurrency(t *testing.T) {
+	limiter := newCountLimiter(3)
+
+	var running, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.acquire(context.Background()); err != nil {
+				t.Errorf("acquire returned error: %v", err)
+				return
+			}
+			defer limiter.release()
+
+			now := running.Add(1)
+			for {
+				seen := peak.Load()
+				if now <= seen || peak.CompareAndSwap(seen, now) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > 3 {
+		t.Errorf("%d operations ran at once, limit is 3", got)
+	}
+	if got := limiter.inFlight.Load(); got != 0 {
+		t.Errorf("in-flight count is %d after all operations finished", got)
+	}
+}
+
+func TestCountWaitsForGlobalLimit(t *testing.T) {
+	t.Setenv("GLOBAL_COUNT_LIMIT", "1")
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web", nil))
+	pods, err := client.findResource("pods", true)
+	if err != nil {
+		t.Fatalf("findResource returned error: %v", err)
+	}
+
+	// Another scan holds the only slot
+	if err := client.countLimiter.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire returned error: %v", err)
+	}
+	if inFlight, limit := client.CountsInFlight(); inFlight != 1 || limit != 1 {
+		t.Errorf("CountsInFlight = %d, %d, want 1, 1", inFlight, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, _, err := client.countResourceObjects(ctx, "default", *pods); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the count to wait for a slot until its deadline, got %v", err)
+	}
+
+	client.countLimiter.release()
+	count, _, err := client.countResourceObjects(context.Background(), "default", *pods)
+	if err != nil || count != 1 {
+		t.Errorf("countResourceObjects = %d, %v after the slot was freed", count, err)
+	}
+}
+
+func TestQueuedCountGetsFullTimeout(t *testing.T) {
+	t.Setenv("GLOBAL_COUNT_LIMIT", "1")
+	t.Setenv("COUNT_TIMEOUT", "200ms")
+	t.Setenv("COUNT_TIMEOUT_JITTER", "0")
+	client, paged := newPagedTestClient(newTestObject("v1", "Pod", "default", "web", nil))
+	pods, err := client.findResource("pods", true)
+	if err != nil {
+		t.Fatalf("findResource returned error: %v", err)
+	}
+
+	// Another scan holds the only slot for longer than the count timeout
+	if err := client.countLimiter.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire returned error: %v", err)
+	}
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		client.countLimiter.release()
+	}()
+
+	count, _, err := client.countResourceObjects(context.Background(), "default", *pods)
+	if err != nil || count != 1 {
+		t.Fatalf("countResourceObjects = %d, %v after waiting for a slot, want 1 without error", count, err)
+	}
+
+	// The count deadline started once the slot was held, not while the count was queued
+	paged.mu.Lock()
+	defer paged.mu.Unlock()
+	if len(paged.timeouts) != 1 || paged.timeouts[0] < 150*time.Millisecond {
+		t.Errorf("expected the List call to get about the full 200ms timeout, got %v", paged.timeouts)
+	}
+}
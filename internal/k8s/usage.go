@@ -0,0 +1,169 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ErrMetricsUnavailable is returned when the metrics.k8s.io API is not served, usually because
+// metrics-server is not installed or not ready
+var ErrMetricsUnavailable = errors.New("metrics API unavailable")
+
+// The metrics.k8s.io resources served by metrics-server. They are read with the dynamic client like
+// every other resource, so the metrics API needs no client of its own.
+var (
+	podMetricsGVR  = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+	nodeMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "nodes"}
+)
+
+// PodMetrics is the live resource usage of a Pod or Node as reported by metrics-server. For Pods it
+// is the sum over the containers.
+type PodMetrics struct {
+	CPU           string    `json:"cpu"`    // e.g. "250m"
+	Memory        string    `json:"memory"` // e.g. "128Mi"
+	CPUMillicores int64     `json:"cpuMillicores"`
+	MemoryBytes   int64     `json:"memoryBytes"`
+	Timestamp     time.Time `json:"timestamp"`
+	Window        string    `json:"window"` // the interval the usage was averaged over
+}
+
+// GetPodMetrics returns the usage of the Pods in namespace by Pod name, or across all namespaces
+// for AllNamespaces and "". It returns ErrMetricsUnavailable when metrics-server is absent.
+func (c *Client) GetPodMetrics(ctx context.Context, namespace string) (map[string]PodMetrics, error) {
+	if namespace == AllNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	return c.listUsage(ctx, podMetricsGVR, namespace)
+}
+
+// GetNodeMetrics returns the usage of the Nodes by name. It returns ErrMetricsUnavailable when
+// metrics-server is absent.
+func (c *Client) GetNodeMetrics(ctx context.Context) (map[string]PodMetrics, error) {
+	return c.listUsage(ctx, nodeMetricsGVR, "")
+}
+
+// AddResourceUsage merges live usage into the Pods and Nodes among objects, listing the metrics of
+// namespace once. Objects of other kinds are left alone, and so are all objects when metrics-server
+// is unavailable: usage is an extra that must never fail a listing.
+func (c *Client) AddResourceUsage(ctx context.Context, namespace string, objects []ObjectInfo) {
+	var pods, nodes bool
+	for _, object := range objects {
+		pods = pods || isCoreKind(object, "Pod")
+		nodes = nodes || isCoreKind(object, "Node")
+	}
+
+	var podUsage, nodeUsage map[string]PodMetrics
+	var err error
+	if pods {
+		podUsage, err = c.GetPodMetrics(ctx, namespace)
+	}
+	if nodes && err == nil {
+		nodeUsage, err = c.GetNodeMetrics(ctx)
+	}
+	if err != nil {
+		slog.DebugContext(ctx, "Leaving out resource usage", "namespace", namespace, "error", err)
+		return
+	}
+
+	for i := range objects {
+		object := &objects[i]
+		var usage PodMetrics
+		var found bool
+		switch {
+		case isCoreKind(*object, "Pod"):
+			usage, found = podUsage[object.Namespace+"/"+object.Name]
+		case isCoreKind(*object, "Node"):
+			usage, found = nodeUsage[object.Name]
+		}
+		if found {
+			object.Usage = &usage
+		}
+	}
+}
+
+// isCoreKind reports whether object is of the given core/v1 kind
+func isCoreKind(object ObjectInfo, kind string) bool {
+	return object.Kind == kind && object.APIVersion == "v1"
+}
+
+// listUsage lists PodMetrics or NodeMetrics and sums their usage. Pods are keyed by
+// namespace/name, Nodes by name.
+func (c *Client) listUsage(ctx context.Context, gvr schema.GroupVersionResource, namespace string) (map[string]PodMetrics, error) {
+	if c.dynamicClient == nil {
+		return nil, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	list, err := c.dynamicClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// A missing APIService answers 404, an unready metrics-server 503
+		if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			return nil, fmt.Errorf("%w: %w", ErrMetricsUnavailable, err)
+		}
+		return nil, wrapAPIError(err)
+	}
+
+	usage := make(map[string]PodMetrics, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		key := item.GetName()
+		if item.GetNamespace() != "" {
+			key = item.GetNamespace() + "/" + key
+		}
+		usage[key] = sumUsage(item)
+	}
+	return usage, nil
+}
+
+// sumUsage adds up the usage of a NodeMetrics object or of the containers of a PodMetrics object
+func sumUsage(item *unstructured.Unstructured) PodMetrics {
+	var usages []interface{}
+	if containers, found, _ := unstructured.NestedSlice(item.Object, "containers"); found {
+		for _, container := range containers {
+			if container, ok := container.(map[string]interface{}); ok {
+				usages = append(usages, container["usage"])
+			}
+		}
+	} else {
+		usages = append(usages, item.Object["usage"])
+	}
+
+	cpu := resource.NewMilliQuantity(0, resource.DecimalSI)
+	memory := resource.NewQuantity(0, resource.BinarySI)
+	for _, u := range usages {
+		values, _ := u.(map[string]interface{})
+		if raw, ok := values["cpu"].(string); ok {
+			if quantity, err := resource.ParseQuantity(raw); err == nil {
+				cpu.Add(quantity)
+			}
+		}
+		if raw, ok := values["memory"].(string); ok {
+			if quantity, err := resource.ParseQuantity(raw); err == nil {
+				memory.Add(quantity)
+			}
+		}
+	}
+
+	metrics := PodMetrics{
+		CPU:           cpu.String(),
+		Memory:        memory.String(),
+		CPUMillicores: cpu.MilliValue(),
+		MemoryBytes:   memory.Value(),
+	}
+	metrics.Window, _, _ = unstructured.NestedString(item.Object, "window")
+	if raw, _, _ := unstructured.NestedString(item.Object, "timestamp"); raw != "" {
+		metrics.Timestamp, _ = time.Parse(time.RFC3339, raw)
+	}
+	return metrics
+}
@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newPodMetrics(namespace, name string, containers ...map[string]interface{}) *unstructured.Unstructured {
+	var list []interface{}
+	for _, container := range containers {
+		list = append(list, container)
+	}
+	return newTestObject("metrics.k8s.io/v1beta1", "PodMetrics", namespace, name, map[string]interface{}{
+		"timestamp":  "2024-05-01T12:00:00Z",
+		"window":     "15s",
+		"containers": list,
+	})
+}
+
+func containerUsage(name, cpu, memory string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "usage": map[string]interface{}{"cpu": cpu, "memory": memory}}
+}
+
+// newUsageTestClient returns a test client whose dynamic client also serves the metrics.k8s.io
+// resources of metrics-server. PodMetrics and NodeMetrics are filed under their resource explicitly,
+// as the fake would guess "podmetrics" and "nodemetrics" from the kinds.
+func newUsageTestClient(t *testing.T, metrics ...*unstructured.Unstructured) *Client {
+	t.Helper()
+	client := newTestClient()
+	listKinds := testListKinds()
+	listKinds[podMetricsGVR] = "PodMetricsList"
+	listKinds[nodeMetricsGVR] = "NodeMetricsList"
+	fakeDynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	for _, object := range metrics {
+		gvr := podMetricsGVR
+		if object.GetKind() == "NodeMetrics" {
+			gvr = nodeMetricsGVR
+		}
+		if err := fakeDynamic.Tracker().Create(gvr, object, object.GetNamespace()); err != nil {
+			t.Fatalf("seeding %s: %v", object.GetName(), err)
+		}
+	}
+	client.dynamicClient = fakeDynamic
+	return client
+}
+
+func TestGetPodMetrics(t *testing.T) {
+	client := newUsageTestClient(t,
+		newPodMetrics("default", "web", containerUsage("app", "250m", "100Mi"), containerUsage("proxy", "50m", "28Mi")),
+		newPodMetrics("other", "db", containerUsage("db", "1", "1Gi")),
+	)
+
+	usage, err := client.GetPodMetrics(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetPodMetrics returned error: %v", err)
+	}
+	if len(usage) != 1 {
+		t.Fatalf("expected the metrics of one pod, got %v", usage)
+	}
+	web := usage["default/web"]
+	if web.CPUMillicores != 300 || web.CPU != "300m" {
+		t.Errorf("cpu = %s (%dm), want the sum of the containers, 300m", web.CPU, web.CPUMillicores)
+	}
+	if web.MemoryBytes != 128<<20 || web.Memory != "128Mi" {
+		t.Errorf("memory = %s (%d bytes), want 128Mi", web.Memory, web.MemoryBytes)
+	}
+	if web.Window != "15s" || web.Timestamp.IsZero() {
+		t.Errorf("unexpected window %q or timestamp %v", web.Window, web.Timestamp)
+	}
+}
+
+func TestAddResourceUsage(t *testing.T) {
+	client := newUsageTestClient(t,
+		newPodMetrics("default", "web", containerUsage("app", "250m", "64Mi")),
+		newTestObject("metrics.k8s.io/v1beta1", "NodeMetrics", "", "node-a", map[string]interface{}{
+			"usage": map[string]interface{}{"cpu": "1500m", "memory": "2Gi"},
+		}),
+	)
+
+	objects := []ObjectInfo{
+		{Name: "web", Namespace: "default", Kind: "Pod", APIVersion: "v1"},
+		{Name: "pending", Namespace: "default", Kind: "Pod", APIVersion: "v1"},
+		{Name: "node-a", Kind: "Node", APIVersion: "v1"},
+		{Name: "settings", Namespace: "default", Kind: "ConfigMap", APIVersion: "v1"},
+	}
+	client.AddResourceUsage(context.Background(), "default", objects)
+
+	if objects[0].Usage == nil || objects[0].Usage.CPUMillicores != 250 {
+		t.Errorf("expected usage for the running pod, got %+v", objects[0].Usage)
+	}
+	if objects[1].Usage != nil {
+		t.Errorf("expected no usage for a pod without metrics, got %+v", objects[1].Usage)
+	}
+	if objects[2].Usage == nil || objects[2].Usage.CPUMillicores != 1500 || objects[2].Usage.MemoryBytes != 2<<30 {
+		t.Errorf("expected node usage, got %+v", objects[2].Usage)
+	}
+	if objects[3].Usage != nil {
+		t.Errorf("expected no usage for a ConfigMap, got %+v", objects[3].Usage)
+	}
+}
+
+func TestResourceUsageWithoutMetricsServer(t *testing.T) {
+	client := newUsageTestClient(t)
+	fakeDynamic := client.dynamicClient.(*dynamicfake.FakeDynamicClient)
+	fakeDynamic.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Group != "metrics.k8s.io" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"}, "")
+	})
+
+	if _, err := client.GetPodMetrics(context.Background(), "default"); !errors.Is(err, ErrMetricsUnavailable) {
+		t.Errorf("expected ErrMetricsUnavailable, got %v", err)
+	}
+
+	objects := []ObjectInfo{{Name: "web", Namespace: "default", Kind: "Pod", APIVersion: "v1"}}
+	client.AddResourceUsage(context.Background(), "default", objects)
+	if objects[0].Usage != nil {
+		t.Errorf("expected usage to be omitted, got %+v", objects[0].Usage)
+	}
+}
@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// discoverAllVersions discovers the resources of every served version of each API group, along with
+// the preferred version of each group by group name. Partial failures are returned like
+// ServerPreferredResources returns them, with whatever was discovered.
+func (c *Client) discoverAllVersions() ([]*metav1.APIResourceList, map[string]string, error) {
+	groups, resourceLists, err := c.discoveryClient.ServerGroupsAndResources()
+
+	preferredVersions := make(map[string]string, len(groups))
+	for _, group := range groups {
+		preferredVersions[group.Name] = group.PreferredVersion.Version
+	}
+	return resourceLists, preferredVersions, err
+}
+
+// mergeVersions folds the entries of a resource served in several versions of its group into one, at
+// the position of the first. The merged entry uses the group's preferred version, or the first version
+// discovered when the kind is not served in the preferred one, and lists every version in Versions.
+func mergeVersions(resources []ResourceInfo, preferredVersions map[string]string) []ResourceInfo {
+	merged := make([]ResourceInfo, 0, len(resources))
+	index := make(map[string]int) // group/kind/name -> position in merged
+	for _, resource := range resources {
+		// Subresources share kinds such as Scale, so the name tells them apart
+		key := resource.APIGroup + "/" + resource.Kind + "/" + resource.Name
+		i, seen := index[key]
+		if !seen {
+			resource.Versions = []string{resource.APIVersion}
+			index[key] = len(merged)
+			merged = append(merged, resource)
+			continue
+		}
+
+		existing := &merged[i]
+		existing.Versions = append(existing.Versions, resource.APIVersion)
+		if resource.APIVersion == preferredVersions[resource.APIGroup] {
+			existing.APIVersion = resource.APIVersion
+		}
+	}
+
+	// List the version objects are read at first
+	for i := range merged {
+		versions := merged[i].Versions
+		for j, version := range versions {
+			if version == merged[i].APIVersion {
+				copy(versions[1:j+1], versions[:j])
+				versions[0] = version
+				break
+			}
+		}
+	}
+	return merged
+}
+
+// selectVersion returns resource at the version named by an identifier of the form name.version.group,
+// or name.version for the core group, when that version is served
+func selectVersion(resource ResourceInfo, identifier string) (ResourceInfo, bool) {
+	for _, version := range resource.Versions {
+		versioned := resource.Name + "." + version
+		if resource.APIGroup != "" {
+			versioned += "." + resource.APIGroup
+		}
+		if versioned == identifier {
+			resource.APIVersion = version
+			return resource, true
+		}
+	}
+	return resource, false
+}
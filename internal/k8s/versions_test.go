@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+// newAllVersionsTestClient returns a test client whose discovery serves networking.k8s.io in v1,
+// the preferred version, and in v1beta1, which alone serves ingressclassparams
+func newAllVersionsTestClient(t *testing.T, objects ...runtime.Object) *Client {
+	t.Helper()
+	t.Setenv("ALL_VERSIONS", "true")
+
+	networking := []*metav1.APIResourceList{
+		{
+			GroupVersion: "networking.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", Kind: "Ingress", ShortNames: []string{"ing"}, Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+		{
+			GroupVersion: "networking.k8s.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "ingresses", Kind: "Ingress", ShortNames: []string{"ing"}, Namespaced: true, Verbs: []string{"list", "get"}},
+				{Name: "ingressclassparams", Kind: "IngressClassParams", Namespaced: true, Verbs: []string{"list", "get"}},
+			},
+		},
+	}
+	listKinds := testListKinds()
+	for _, list := range networking {
+		for _, resource := range list.APIResources {
+			gv, _ := schema.ParseGroupVersion(list.GroupVersion)
+			listKinds[gv.WithResource(resource.Name)] = resource.Kind + "List"
+		}
+	}
+
+	client := newTestClient()
+	client.discoveryClient.(*testDiscovery).Resources = append(append([]*metav1.APIResourceList{}, testAPIResources...), networking...)
+	client.dynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+	return client
+}
+
+func TestAllVersionsDiscovery(t *testing.T) {
+	client := newAllVersionsTestClient(t)
+
+	resources, err := client.GetAPIResources()
+	if err != nil {
+		t.Fatalf("GetAPIResources returned error: %v", err)
+	}
+
+	found := make(map[string]ResourceInfo)
+	for _, resource := range resources {
+		if _, duplicate := found[resource.FullName]; duplicate {
+			t.Errorf("%s discovered twice", resource.FullName)
+		}
+		found[resource.FullName] = resource
+	}
+
+	ingresses := found["ingresses.networking.k8s.io"]
+	if ingresses.APIVersion != "v1" || !reflect.DeepEqual(ingresses.Versions, []string{"v1", "v1beta1"}) {
+		t.Errorf("ingresses: expected v1 of [v1 v1beta1], got %s of %v", ingresses.APIVersion, ingresses.Versions)
+	}
+	// A kind missing from the preferred version is still discovered, at the version serving it
+	params := found["ingressclassparams.networking.k8s.io"]
+	if params.APIVersion != "v1beta1" || !reflect.DeepEqual(params.Versions, []string{"v1beta1"}) {
+		t.Errorf("ingressclassparams: expected v1beta1 only, got %s of %v", params.APIVersion, params.Versions)
+	}
+	if pods := found["pods"]; !reflect.DeepEqual(pods.Versions, []string{"v1"}) {
+		t.Errorf("pods: expected versions [v1], got %v", pods.Versions)
+	}
+}
+
+func TestAllVersionsSelectVersion(t *testing.T) {
+	client := newAllVersionsTestClient(t,
+		newTestObject("networking.k8s.io/v1", "Ingress", "default", "web", nil),
+		newTestObject("networking.k8s.io/v1beta1", "Ingress", "default", "legacy", nil),
+	)
+
+	names := func(identifier string) []string {
+		t.Helper()
+		objects, err := client.GetResourceObjects(context.Background(), "default", identifier)
+		if err != nil {
+			t.Fatalf("GetResourceObjects(%s) returned error: %v", identifier, err)
+		}
+		var names []string
+		for _, object := range objects {
+			names = append(names, object.Name)
+		}
+		return names
+	}
+
+	if listed := names("ingresses.networking.k8s.io"); !reflect.DeepEqual(listed, []string{"web"}) {
+		t.Errorf("preferred version listed %v, expected [web]", listed)
+	}
+	if listed := names("ingresses.v1beta1.networking.k8s.io"); !reflect.DeepEqual(listed, []string{"legacy"}) {
+		t.Errorf("v1beta1 listed %v, expected [legacy]", listed)
+	}
+
+	object, err := client.GetResourceObject(context.Background(), "default", "ingresses.v1beta1.networking.k8s.io", "legacy")
+	if err != nil || object.APIVersion != "networking.k8s.io/v1beta1" {
+		t.Errorf("GetResourceObject at v1beta1 returned %+v, error: %v", object, err)
+	}
+
+	if _, err := client.findResource("ingresses.v2.networking.k8s.io", true); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected ErrResourceNotFound for a version that is not served, got %v", err)
+	}
+}
+
+func TestMergeVersionsPreferredDiscoveredLater(t *testing.T) {
+	merged := mergeVersions([]ResourceInfo{
+		{Name: "flowschemas", FullName: "flowschemas.flowcontrol.apiserver.k8s.io", Kind: "FlowSchema", APIGroup: "flowcontrol.apiserver.k8s.io", APIVersion: "v1beta2"},
+		{Name: "pods", FullName: "pods", Kind: "Pod", APIVersion: "v1"},
+		{Name: "flowschemas", FullName: "flowschemas.flowcontrol.apiserver.k8s.io", Kind: "FlowSchema", APIGroup: "flowcontrol.apiserver.k8s.io", APIVersion: "v1beta3"},
+	}, map[string]string{"flowcontrol.apiserver.k8s.io": "v1beta3", "": "v1"})
+
+	if len(merged) != 2 || merged[0].Kind != "FlowSchema" || merged[1].Kind != "Pod" {
+		t.Fatalf("expected FlowSchema then Pod, got %+v", merged)
+	}
+	if merged[0].APIVersion != "v1beta3" || !reflect.DeepEqual(merged[0].Versions, []string{"v1beta3", "v1beta2"}) {
+		t.Errorf("expected v1beta3 first of [v1beta3 v1beta2], got %s of %v", merged[0].APIVersion, merged[0].Versions)
+	}
+}
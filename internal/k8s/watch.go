@@ -0,0 +1,193 @@
+package k8s
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/metadata"
+)
+
+// defaultMaxWatchedNamespaces bounds how many namespaces are watched at once. Every watched namespace
+// holds a metadata watch per resource type open against the API server.
+const defaultMaxWatchedNamespaces int64 = 20
+
+// namespaceWatch is the set of metadata watches keeping the cached counts of a namespace current
+type namespaceWatch struct {
+	cancel context.CancelFunc
+	// element is the namespace's entry in Client.watchOrder
+	element *list.Element
+}
+
+// isWatched reports whether the cached counts of a namespace are kept current by watches, in which
+// case they do not expire with the TTL. It marks the namespace as recently used, so the namespaces
+// still being read are the last to lose their watches.
+func (c *Client) isWatched(namespace string) bool {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+
+	nsWatch, watched := c.namespaceWatches[namespace]
+	if watched {
+		c.watchOrder.MoveToFront(nsWatch.element)
+	}
+	return watched
+}
+
+// startNamespaceWatches watches every counted resource of a namespace from the resourceVersion it
+// was counted at, replacing earlier watches of the namespace. It does nothing unless WATCH_CACHE is
+// enabled. If any watch cannot be started or ends, all watches of the namespace are stopped and its
+// cache falls back to TTL expiry. At most WATCH_CACHE_MAX_NAMESPACES namespaces are watched, any
+// number when it is 0: the watches of the least recently used namespace are stopped to make room,
+// and its cache falls back to TTL expiry as well.
+func (c *Client) startNamespaceWatches(namespace string, resources []ResourceInfo) {
+	if !c.watchCache || c.metadataClient == nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	nsWatch := &namespaceWatch{cancel: cancel}
+
+	c.namespaceMu.Lock()
+	c.unwatchLocked(namespace)
+	for c.maxWatchedNamespaces > 0 && len(c.namespaceWatches) >= c.maxWatchedNamespaces {
+		evicted := c.watchOrder.Back().Value.(string)
+		slog.Debug("Too many watched namespaces, using TTL expiry", "namespace", evicted, "max", c.maxWatchedNamespaces)
+		c.unwatchLocked(evicted)
+	}
+	nsWatch.element = c.watchOrder.PushFront(namespace)
+	c.namespaceWatches[namespace] = nsWatch
+	c.namespaceMu.Unlock()
+
+	watched := 0
+	for _, resource := range resources {
+		// Resources that could not be counted cannot be watched either
+		if resource.resourceVersion == "" {
+			continue
+		}
+
+		w, err := c.metadataResource(namespace, resource).Watch(ctx, metav1.ListOptions{
+			ResourceVersion:     resource.resourceVersion,
+			AllowWatchBookmarks: true,
+		})
+		if err != nil {
+			slog.Warn("Failed to watch, using TTL expiry", "namespace", namespace, "resource", resource.FullName, "error", err)
+			c.stopNamespaceWatches(namespace, nsWatch)
+			return
+		}
+		go c.followWatch(ctx, namespace, resource.FullName, nsWatch, w)
+		watched++
+	}
+
+	slog.Debug("Watching resources to keep counts current", "namespace", namespace, "count", watched)
+}
+
+// followWatch applies the add and delete events of one resource to the cached counts
+func (c *Client) followWatch(ctx context.Context, namespace, fullName string, nsWatch *namespaceWatch, w watch.Interface) {
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				slog.WarnContext(ctx, "Watch ended, using TTL expiry", "namespace", namespace, "resource", fullName)
+				c.stopNamespaceWatches(namespace, nsWatch)
+				return
+			}
+			switch event.Type {
+			case watch.Added:
+				c.adjustCachedCount(namespace, fullName, 1)
+			case watch.Deleted:
+				c.adjustCachedCount(namespace, fullName, -1)
+			case watch.Error:
+				slog.WarnContext(ctx, "Watch failed, using TTL expiry", "namespace", namespace, "resource", fullName,
+					"error", apiStatusMessage(event.Object))
+				c.stopNamespaceWatches(namespace, nsWatch)
+				return
+			}
+		}
+	}
+}
+
+// stopNamespaceWatches stops the watches of a namespace unless they have already been replaced
+func (c *Client) stopNamespaceWatches(namespace string, nsWatch *namespaceWatch) {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+
+	nsWatch.cancel()
+	if c.namespaceWatches[namespace] == nsWatch {
+		c.unwatchLocked(namespace)
+	}
+}
+
+// StopWatches stops the watches of every namespace, whose caches fall back to TTL expiry. It is
+// called on shutdown so no watch outlives the server.
+func (c *Client) StopWatches() {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+
+	for namespace := range c.namespaceWatches {
+		c.unwatchLocked(namespace)
+	}
+}
+
+// unwatchLocked stops the watches of a namespace, if any. The caller holds namespaceMu.
+func (c *Client) unwatchLocked(namespace string) {
+	nsWatch, watched := c.namespaceWatches[namespace]
+	if !watched {
+		return
+	}
+	nsWatch.cancel()
+	c.watchOrder.Remove(nsWatch.element)
+	delete(c.namespaceWatches, namespace)
+}
+
+// adjustCachedCount adds delta to the cached count of a resource. The cached slice is replaced rather
+// than modified because earlier callers may still be reading it.
+func (c *Client) adjustCachedCount(namespace, fullName string, delta int) {
+	c.namespaceMu.Lock()
+	defer c.namespaceMu.Unlock()
+
+	cached, exists := c.namespaceCaches[namespace]
+	if !exists {
+		return
+	}
+
+	updated := make([]ResourceInfo, len(cached))
+	copy(updated, cached)
+	for i := range updated {
+		if updated[i].FullName == fullName {
+			updated[i].Count += delta
+			if updated[i].Count < 0 {
+				updated[i].Count = 0
+			}
+			break
+		}
+	}
+	c.namespaceCaches[namespace] = updated
+}
+
+// metadataResource returns the metadata client for a resource, scoped to the namespace unless the
+// resource is cluster-scoped
+func (c *Client) metadataResource(namespace string, resource ResourceInfo) metadata.ResourceInterface {
+	gvr := schema.GroupVersionResource{
+		Group:    resource.APIGroup,
+		Version:  resource.APIVersion,
+		Resource: resource.Name,
+	}
+	if !resource.Namespaced {
+		return c.metadataClient.Resource(gvr)
+	}
+	return c.metadataClient.Resource(gvr).Namespace(namespace)
+}
+
+// apiStatusMessage extracts the message of the Status object carried by watch error events
+func apiStatusMessage(obj interface{}) string {
+	if status, ok := obj.(*metav1.Status); ok {
+		return status.Message
+	}
+	return "unknown error"
+}
@@ -0,0 +1,233 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// cachedCount returns the cached count of a resource in a namespace
+func cachedCount(client *Client, namespace, fullName string) int {
+	resources, _, _ := client.cachedNamespaceResources(namespace)
+	for _, resource := range resources {
+		if resource.FullName == fullName {
+			return resource.Count
+		}
+	}
+	return -1
+}
+
+// waitForCachedCount polls until the cached count reaches expected, failing the test after a second
+func waitForCachedCount(t *testing.T, client *Client, namespace, fullName string, expected int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for cachedCount(client, namespace, fullName) != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected cached %s count %d, got %d", fullName, expected, cachedCount(client, namespace, fullName))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchKeepsCachedCountsAccurate(t *testing.T) {
+	t.Setenv("WATCH_CACHE", "true")
+	client, _ := newPagedTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+	defer client.ClearCache()
+
+	// Serve a controllable watcher for pods and idle watchers for everything else
+	var mu sync.Mutex
+	podWatcher := watch.NewFake()
+	var podWatchRV string
+	fakeMetadataClient(client).PrependWatchReactor("*",
+		func(action k8stesting.Action) (bool, watch.Interface, error) {
+			if action.GetResource().Resource == "pods" {
+				mu.Lock()
+				podWatchRV = action.(k8stesting.WatchAction).GetWatchRestrictions().ResourceVersion
+				mu.Unlock()
+				return true, podWatcher, nil
+			}
+			return true, watch.NewFake(), nil
+		})
+
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	if !client.isWatched("default") {
+		t.Fatal("expected namespace to be watched")
+	}
+	mu.Lock()
+	if podWatchRV != "100" {
+		t.Errorf("expected the pods watch to start at the counted resourceVersion 100, got %q", podWatchRV)
+	}
+	mu.Unlock()
+
+	pod := func(name string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	podWatcher.Add(pod("web-2"))
+	podWatcher.Add(pod("web-3"))
+	waitForCachedCount(t, client, "default", "pods", 3)
+	podWatcher.Delete(pod("web-1"))
+	podWatcher.Modify(pod("web-2"))
+	waitForCachedCount(t, client, "default", "pods", 2)
+
+	// Watched counts do not expire with the TTL
+	client.namespaceMu.Lock()
+	client.namespaceCacheTimes["default"] = time.Now().Add(-2 * client.cacheTTL)
+	client.namespaceMu.Unlock()
+	resources, err := client.GetResourcesInNamespace(context.Background(), "default")
+	if err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	for _, resource := range resources {
+		if resource.Name == "pods" && resource.Count != 2 {
+			t.Errorf("expected the watched count 2 instead of a recount, got %d", resource.Count)
+		}
+	}
+
+	// When the watch ends the namespace falls back to TTL expiry
+	podWatcher.Stop()
+	deadline := time.Now().Add(time.Second)
+	for client.isWatched("default") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected watches to stop after the pods watch ended")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWatchCacheDisabledByDefault(t *testing.T) {
+	t.Setenv("WATCH_CACHE", "")
+	client, _ := newPagedTestClient(newTestObject("v1", "Pod", "default", "web-1", nil))
+
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+	if client.isWatched("default") {
+		t.Error("expected no watches unless WATCH_CACHE is enabled")
+	}
+}
+
+func TestWatchedNamespacesCapped(t *testing.T) {
+	t.Setenv("WATCH_CACHE", "true")
+	t.Setenv("WATCH_CACHE_MAX_NAMESPACES", "2")
+	client, _ := newPagedTestClient(
+		newTestObject("v1", "Pod", "team-a", "web-1", nil),
+		newTestObject("v1", "Pod", "team-b", "web-1", nil),
+		newTestObject("v1", "Pod", "team-c", "web-1", nil),
+	)
+	defer client.StopWatches()
+
+	// Hand out a fake watcher per namespace and resource so the test can see which were stopped
+	var mu sync.Mutex
+	watchers := make(map[string][]*watch.FakeWatcher)
+	fakeMetadataClient(client).PrependWatchReactor("*",
+		func(action k8stesting.Action) (bool, watch.Interface, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			watcher := watch.NewFake()
+			watchers[action.GetNamespace()] = append(watchers[action.GetNamespace()], watcher)
+			return true, watcher, nil
+		})
+	stopped := func(namespace string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, watcher := range watchers[namespace] {
+			if !watcher.IsStopped() {
+				return false
+			}
+		}
+		return len(watchers[namespace]) > 0
+	}
+
+	ctx := context.Background()
+	for _, namespace := range []string{"team-a", "team-b"} {
+		if _, err := client.GetResourcesInNamespace(ctx, namespace); err != nil {
+			t.Fatalf("GetResourcesInNamespace(%s) returned error: %v", namespace, err)
+		}
+	}
+	// Reading team-a makes team-b the least recently used
+	if !client.isWatched("team-a") {
+		t.Fatal("expected team-a to be watched")
+	}
+	if _, err := client.GetResourcesInNamespace(ctx, "team-c"); err != nil {
+		t.Fatalf("GetResourcesInNamespace(team-c) returned error: %v", err)
+	}
+
+	if client.isWatched("team-b") {
+		t.Error("expected team-b to be evicted")
+	}
+	if !client.isWatched("team-a") || !client.isWatched("team-c") {
+		t.Error("expected team-a and team-c to stay watched")
+	}
+	deadline := time.Now().Add(time.Second)
+	for !stopped("team-b") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the watches of the evicted namespace to stop")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if stopped("team-a") || stopped("team-c") {
+		t.Error("expected the watches of the remaining namespaces to keep running")
+	}
+
+	// The evicted namespace expires with the TTL again
+	client.namespaceMu.Lock()
+	expired := time.Now().Add(-2 * client.cacheTTL)
+	client.namespaceCacheTimes["team-b"] = expired
+	client.namespaceMu.Unlock()
+	if _, err := client.GetResourcesInNamespace(ctx, "team-b"); err != nil {
+		t.Fatalf("GetResourcesInNamespace(team-b) returned error: %v", err)
+	}
+	if !client.NamespaceCacheTime("team-b").After(expired) {
+		t.Error("expected the evicted namespace to be recounted after the TTL")
+	}
+
+	// Stopping all watches on shutdown leaves none running
+	client.StopWatches()
+	deadline = time.Now().Add(time.Second)
+	for _, namespace := range []string{"team-a", "team-b", "team-c"} {
+		for !stopped(namespace) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the watches of %s to stop", namespace)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+}
+
+func TestWatchedNamespacesUncapped(t *testing.T) {
+	t.Setenv("WATCH_CACHE", "true")
+	t.Setenv("WATCH_CACHE_MAX_NAMESPACES", "0")
+	var objects []runtime.Object
+	for i := 0; i < int(defaultMaxWatchedNamespaces)+1; i++ {
+		objects = append(objects, newTestObject("v1", "Pod", fmt.Sprintf("team-%d", i), "web", nil))
+	}
+	client, _ := newPagedTestClient(objects...)
+	defer client.StopWatches()
+	fakeMetadataClient(client).PrependWatchReactor("*",
+		func(action k8stesting.Action) (bool, watch.Interface, error) {
+			return true, watch.NewFake(), nil
+		})
+
+	if client.maxWatchedNamespaces != 0 {
+		t.Fatalf("WATCH_CACHE_MAX_NAMESPACES=0: got %d, want no cap", client.maxWatchedNamespaces)
+	}
+	for i := range objects {
+		if _, err := client.GetResourcesInNamespace(context.Background(), fmt.Sprintf("team-%d", i)); err != nil {
+			t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+		}
+	}
+	for i := range objects {
+		if namespace := fmt.Sprintf("team-%d", i); !client.isWatched(namespace) {
+			t.Errorf("expected %s to stay watched without a cap", namespace)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// checkWritable refuses writes unless ALLOW_WRITES is enabled and the resource is in WRITABLE_RESOURCES
+func (c *Client) checkWritable(resource *ResourceInfo) error {
+	if !c.allowWrites {
+		return ErrWritesDisabled
+	}
+	if !c.writableResources[resource.FullName] {
+		return fmt.Errorf("%w: %s is not in WRITABLE_RESOURCES", ErrForbidden, resource.FullName)
+	}
+	return nil
+}
+
+// DeleteCollection deletes all objects of a resource type in a namespace that match labelSelector
+// and returns the number of objects the deletion was attempted for. An empty selector is refused
+// so a single call cannot wipe out a whole resource type.
+func (c *Client) DeleteCollection(ctx context.Context, namespace, resourceIdentifier, labelSelector string) (int, error) {
+	if c.dynamicClient == nil {
+		return 0, fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	if labelSelector == "" {
+		return 0, fmt.Errorf("%w: labelSelector is required", ErrInvalidArgument)
+	}
+	if _, err := labels.Parse(labelSelector); err != nil {
+		return 0, fmt.Errorf("%w: labelSelector: %w", ErrInvalidArgument, err)
+	}
+
+	targetResource, err := c.findResource(resourceIdentifier, true)
+	if err != nil {
+		return 0, err
+	}
+	if err := c.checkWritable(targetResource); err != nil {
+		return 0, err
+	}
+
+	gvr := schema.GroupVersionResource{
+		Group:    targetResource.APIGroup,
+		Version:  targetResource.APIVersion,
+		Resource: targetResource.Name,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	resourceClient := c.dynamicClient.Resource(gvr).Namespace(namespace)
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+
+	matching, _, err := c.countAllPages(ctx, c.metadataResource(namespace, *targetResource), listOptions)
+	if err != nil {
+		return 0, wrapAPIError(err)
+	}
+	if matching == 0 {
+		return 0, nil
+	}
+
+	if err := resourceClient.DeleteCollection(ctx, metav1.DeleteOptions{}, listOptions); err != nil {
+		return 0, wrapAPIError(err)
+	}
+
+	// Counts for this namespace are now stale
+	c.ClearNamespaceCache(namespace)
+
+	return matching, nil
+}
+
+// DeleteResourceObject deletes a single object. Like DeleteCollection it requires ALLOW_WRITES and a
+// resource in WRITABLE_RESOURCES, and it drops the cached counts and objects of the namespace.
+func (c *Client) DeleteResourceObject(ctx context.Context, namespace, resourceIdentifier, name string) error {
+	if c.dynamicClient == nil {
+		return fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+
+	targetResource, err := c.findResource(resourceIdentifier, true)
+	if err != nil {
+		return err
+	}
+	if err := c.checkWritable(targetResource); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	if err := c.resourceClient(namespace, *targetResource).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return wrapAPIError(err)
+	}
+
+	// Counts for this namespace are now stale
+	c.ClearNamespaceCache(namespace)
+
+	return nil
+}
+
+// ScaleResource sets the replicas of a workload through its scale subresource. Resources without
+// one, as reported by discovery, are refused with ErrInvalidArgument. Like the other writes it
+// requires ALLOW_WRITES and a resource in WRITABLE_RESOURCES.
+func (c *Client) ScaleResource(ctx context.Context, namespace, resourceIdentifier, name string, replicas int32) error {
+	if c.dynamicClient == nil || c.discoveryClient == nil {
+		return fmt.Errorf("%w: no dynamic client", ErrNoClient)
+	}
+	if replicas < 0 {
+		return fmt.Errorf("%w: replicas must not be negative, got %d", ErrInvalidArgument, replicas)
+	}
+
+	targetResource, err := c.findResource(resourceIdentifier, true)
+	if err != nil {
+		return err
+	}
+	if err := c.checkWritable(targetResource); err != nil {
+		return err
+	}
+
+	scalable, err := c.hasScaleSubresource(*targetResource)
+	if err != nil {
+		return err
+	}
+	if !scalable {
+		return fmt.Errorf("%w: %s has no scale subresource", ErrInvalidArgument, targetResource.FullName)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.listTimeout)
+	defer cancel()
+
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	if _, err := c.resourceClient(namespace, *targetResource).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "scale"); err != nil {
+		return wrapAPIError(err)
+	}
+
+	// The object changed, so drop its cached copy along with the namespace counts
+	c.ClearNamespaceCache(namespace)
+
+	return nil
+}
+
+// hasScaleSubresource reports whether discovery lists a scale subresource for resource. Preferred
+// resources leave subresources out, so this reads the resource's group version.
+func (c *Client) hasScaleSubresource(resource ResourceInfo) (bool, error) {
+	groupVersion := schema.GroupVersion{Group: resource.APIGroup, Version: resource.APIVersion}.String()
+	list, err := c.discoveryClient.ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return false, fmt.Errorf("discovering subresources of %s: %w", groupVersion, wrapAPIError(err))
+	}
+	for _, apiResource := range list.APIResources {
+		if apiResource.Name == resource.Name+"/scale" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
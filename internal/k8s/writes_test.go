@@ -0,0 +1,177 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestDeleteCollectionWritesDisabled(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "")
+	client := newTestClient(newTestObject("v1", "Pod", "default", "failed-1", nil))
+
+	_, err := client.DeleteCollection(context.Background(), "default", "pods", "app=web")
+	if !errors.Is(err, ErrWritesDisabled) || !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrWritesDisabled wrapping ErrForbidden, got %v", err)
+	}
+}
+
+func TestDeleteCollectionResourceNotWritable(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "true")
+	t.Setenv("WRITABLE_RESOURCES", "")
+	client := newTestClient()
+
+	_, err := client.DeleteCollection(context.Background(), "default", "configmaps", "app=web")
+	if !errors.Is(err, ErrForbidden) || errors.Is(err, ErrWritesDisabled) {
+		t.Errorf("expected ErrForbidden for a resource outside the allow-list, got %v", err)
+	}
+}
+
+func TestDeleteCollectionInvalidSelector(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "true")
+	client := newTestClient()
+
+	for _, selector := range []string{"", "app in (web"} {
+		if _, err := client.DeleteCollection(context.Background(), "default", "pods", selector); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("selector %q: expected ErrInvalidArgument, got %v", selector, err)
+		}
+	}
+}
+
+func TestDeleteCollection(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "true")
+	failed1 := newTestObject("v1", "Pod", "default", "failed-1", nil)
+	failed1.SetLabels(map[string]string{"status": "failed"})
+	failed2 := newTestObject("v1", "Pod", "default", "failed-2", nil)
+	failed2.SetLabels(map[string]string{"status": "failed"})
+	client := newTestClient(failed1, failed2, newTestObject("v1", "Pod", "default", "running", nil))
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+
+	// The fake object tracker does not implement delete-collection, so record the call instead
+	var deleteAction k8stesting.DeleteCollectionAction
+	client.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("delete-collection", "pods",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			deleteAction = action.(k8stesting.DeleteCollectionAction)
+			return true, nil, nil
+		})
+
+	deleted, err := client.DeleteCollection(context.Background(), "default", "pods", "status=failed")
+	if err != nil {
+		t.Fatalf("DeleteCollection returned error: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("expected 2 matching pods, got %d", deleted)
+	}
+	if deleteAction == nil {
+		t.Fatal("expected a delete-collection call")
+	}
+	if deleteAction.GetNamespace() != "default" {
+		t.Errorf("expected namespace default, got %s", deleteAction.GetNamespace())
+	}
+	if selector := deleteAction.GetListRestrictions().Labels.String(); selector != "status=failed" {
+		t.Errorf("expected label selector status=failed, got %s", selector)
+	}
+	if _, _, exists := client.cachedNamespaceResources("default"); exists {
+		t.Error("expected namespace cache to be invalidated after deleting")
+	}
+}
+
+func TestDeleteResourceObjectWritesDisabled(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "")
+	client := newTestClient(newTestObject("v1", "Pod", "default", "web", nil))
+
+	err := client.DeleteResourceObject(context.Background(), "default", "pods", "web")
+	if !errors.Is(err, ErrWritesDisabled) || !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected ErrWritesDisabled wrapping ErrForbidden, got %v", err)
+	}
+	if _, err := client.GetResourceObject(context.Background(), "default", "pods", "web"); err != nil {
+		t.Errorf("the pod is gone although writes are disabled: %v", err)
+	}
+}
+
+func TestDeleteResourceObject(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "true")
+	client := newTestClient(
+		newTestObject("v1", "Pod", "default", "web", nil),
+		newTestObject("v1", "Pod", "default", "db", nil),
+	)
+	if _, err := client.GetResourcesInNamespace(context.Background(), "default"); err != nil {
+		t.Fatalf("GetResourcesInNamespace returned error: %v", err)
+	}
+
+	if err := client.DeleteResourceObject(context.Background(), "default", "pods", "web"); err != nil {
+		t.Fatalf("DeleteResourceObject returned error: %v", err)
+	}
+	if _, err := client.GetResourceObject(context.Background(), "default", "pods", "web"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("expected the deleted pod to be gone, got %v", err)
+	}
+	if _, err := client.GetResourceObject(context.Background(), "default", "pods", "db"); err != nil {
+		t.Errorf("expected the other pod to remain, got %v", err)
+	}
+	if _, _, exists := client.cachedNamespaceResources("default"); exists {
+		t.Error("expected namespace cache to be invalidated after deleting")
+	}
+
+	if err := client.DeleteResourceObject(context.Background(), "default", "pods", "web"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("deleting a missing pod: expected ErrResourceNotFound, got %v", err)
+	}
+}
+
+func TestScaleResource(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "true")
+	t.Setenv("WRITABLE_RESOURCES", "deployments.apps,statefulsets.apps")
+	client := newTestClient(newTestObject("apps/v1", "Deployment", "default", "web", nil))
+
+	// The fake object tracker does not implement subresources, so record the patch instead
+	var patchAction k8stesting.PatchAction
+	client.dynamicClient.(*dynamicfake.FakeDynamicClient).PrependReactor("patch", "deployments",
+		func(action k8stesting.Action) (bool, runtime.Object, error) {
+			patchAction = action.(k8stesting.PatchAction)
+			return true, newTestObject("autoscaling/v1", "Scale", "default", "web", nil), nil
+		})
+
+	if err := client.ScaleResource(context.Background(), "default", "deploy", "web", 3); err != nil {
+		t.Fatalf("ScaleResource returned error: %v", err)
+	}
+	if patchAction == nil {
+		t.Fatal("expected a patch call")
+	}
+	if patchAction.GetSubresource() != "scale" || patchAction.GetName() != "web" || patchAction.GetNamespace() != "default" {
+		t.Errorf("expected a patch of default/web/scale, got %s/%s/%s", patchAction.GetNamespace(), patchAction.GetName(), patchAction.GetSubresource())
+	}
+	if patch := string(patchAction.GetPatch()); patch != `{"spec":{"replicas":3}}` {
+		t.Errorf("unexpected patch %s", patch)
+	}
+
+	if err := client.ScaleResource(context.Background(), "default", "deploy", "web", -1); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("negative replicas: expected ErrInvalidArgument, got %v", err)
+	}
+}
+
+func TestScaleResourceNotScalable(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "true")
+	t.Setenv("WRITABLE_RESOURCES", "replicasets.apps,configmaps")
+	client := newTestClient(newTestObject("apps/v1", "ReplicaSet", "default", "web-7d9f", nil))
+
+	for _, resource := range []string{"replicasets.apps", "configmaps"} {
+		err := client.ScaleResource(context.Background(), "default", resource, "web-7d9f", 3)
+		if !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%s: expected ErrInvalidArgument without a scale subresource, got %v", resource, err)
+		}
+	}
+}
+
+func TestScaleResourceWritesDisabled(t *testing.T) {
+	t.Setenv("ALLOW_WRITES", "")
+	client := newTestClient(newTestObject("apps/v1", "Deployment", "default", "web", nil))
+
+	if err := client.ScaleResource(context.Background(), "default", "deployments", "web", 3); !errors.Is(err, ErrWritesDisabled) {
+		t.Errorf("expected ErrWritesDisabled, got %v", err)
+	}
+}
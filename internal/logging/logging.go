@@ -0,0 +1,97 @@
+// Package logging configures the process-wide slog logger. LOG_FORMAT=json switches to JSON lines
+// for log collectors; the default is human-friendly text. LOG_LEVEL sets the minimum level and
+// defaults to debug when DEBUG is enabled, info otherwise.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Setup installs the logger configured by LOG_FORMAT, LOG_LEVEL and DEBUG as the slog default.
+// Output of the standard log package goes through the same handler.
+func Setup() {
+	level := ParseLevel(os.Getenv("LOG_LEVEL"), debugEnv())
+	slog.SetDefault(slog.New(NewHandler(os.Stderr, os.Getenv("LOG_FORMAT"), level)))
+}
+
+// NewHandler returns a JSON handler for format "json" and a text handler otherwise. Records logged
+// with a context carrying a request ID get it as the requestId attribute.
+func NewHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	options := &slog.HandlerOptions{Level: level}
+	if strings.EqualFold(format, "json") {
+		return requestIDHandler{slog.NewJSONHandler(w, options)}
+	}
+	return requestIDHandler{slog.NewTextHandler(w, options)}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request it belongs to
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" when there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDHandler adds the request ID of the logging context to every record, so the lines of
+// concurrent requests can be told apart
+type requestIDHandler struct {
+	slog.Handler
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("requestId", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{h.Handler.WithGroup(name)}
+}
+
+// ParseLevel parses a LOG_LEVEL value (debug, info, warn or error). An empty value means debug when
+// debug is set and info otherwise; an unknown value falls back to info.
+func ParseLevel(raw string, debug bool) slog.Level {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "":
+		if debug {
+			return slog.LevelDebug
+		}
+		return slog.LevelInfo
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		slog.Warn("Invalid LOG_LEVEL, using info", "value", raw)
+		return slog.LevelInfo
+	}
+}
+
+// DebugEnabled reports whether debug logs are emitted, for callers that do extra work to build them
+func DebugEnabled(ctx context.Context) bool {
+	return slog.Default().Enabled(ctx, slog.LevelDebug)
+}
+
+// debugEnv reports whether DEBUG is enabled
+func debugEnv() bool {
+	debug := strings.ToLower(os.Getenv("DEBUG"))
+	return debug == "true" || debug == "1" || debug == "yes"
+}
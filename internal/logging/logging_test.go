@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONHandlerFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, "json", slog.LevelInfo))
+
+	logger.Info("Found resources", "namespace", "default", "resource", "pods", "count", 3, "duration", 1500*time.Millisecond)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output is not a JSON line: %v\n%s", err, buf.String())
+	}
+	want := map[string]interface{}{
+		"level":     "INFO",
+		"msg":       "Found resources",
+		"namespace": "default",
+		"resource":  "pods",
+		"count":     float64(3),
+		"duration":  float64(1500 * time.Millisecond),
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["time"]; !ok {
+		t.Errorf("entry has no time field: %v", entry)
+	}
+}
+
+func TestHandlerLevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, "json", slog.LevelWarn))
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2 (warn and error):\n%s", len(lines), buf.String())
+	}
+	for i, level := range []string{"WARN", "ERROR"} {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if entry["level"] != level {
+			t.Errorf("line %d level = %v, want %s", i, entry["level"], level)
+		}
+	}
+}
+
+func TestTextHandlerIsDefault(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewHandler(&buf, "", slog.LevelInfo)).Info("Loading resources", "namespace", "default")
+
+	if out := buf.String(); !strings.Contains(out, `msg="Loading resources" namespace=default`) {
+		t.Errorf("text output = %q", out)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		raw   string
+		debug bool
+		want  slog.Level
+	}{
+		{"", false, slog.LevelInfo},
+		{"", true, slog.LevelDebug},
+		{"debug", false, slog.LevelDebug},
+		{"INFO", true, slog.LevelInfo},
+		{"warn", false, slog.LevelWarn},
+		{"warning", false, slog.LevelWarn},
+		{"error", false, slog.LevelError},
+		{"verbose", false, slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := ParseLevel(tt.raw, tt.debug); got != tt.want {
+			t.Errorf("ParseLevel(%q, %t) = %v, want %v", tt.raw, tt.debug, got, tt.want)
+		}
+	}
+}
+
+func TestRequestIDAttribute(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, "json", slog.LevelInfo)).With("component", "test")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-42"), "Loading resources")
+	logger.InfoContext(context.Background(), "Background work")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	for i, want := range []interface{}{"req-42", nil} {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if entry["requestId"] != want {
+			t.Errorf("line %d requestId = %v, want %v", i, entry["requestId"], want)
+		}
+		if entry["component"] != "test" {
+			t.Errorf("line %d lost the logger attributes: %v", i, entry)
+		}
+	}
+}
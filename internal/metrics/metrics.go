@@ -0,0 +1,61 @@
+// Package metrics defines the Prometheus metrics of the explorer. Labels are limited to route
+// templates, methods, status codes and cache names so cardinality stays bounded regardless of how
+// many namespaces or objects a cluster has.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Cache names used as the cache label of CacheLookups
+const (
+	CacheAPIResources = "api_resources"
+	CacheNamespace    = "namespace"
+	CacheObject       = "object"
+)
+
+var (
+	// RequestsTotal counts HTTP requests by route template, method and status code
+	RequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "k8s_explorer_http_requests_total",
+		Help: "HTTP requests handled, by route template, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	// RequestDuration observes HTTP request latencies by route template and method
+	RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "k8s_explorer_http_request_duration_seconds",
+		Help:    "HTTP request latencies, by route template and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	// CacheLookups counts cache hits and misses of the API resource, namespace and object caches
+	CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "k8s_explorer_cache_requests_total",
+		Help: "Cache lookups, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	// DiscoveredResourceTypes is the number of resource types found by the last API discovery
+	DiscoveredResourceTypes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "k8s_explorer_discovered_resource_types",
+		Help: "Number of resource types found by the last API discovery.",
+	})
+)
+
+func init() {
+	// Export every cache series from the start so rates work before the first lookup
+	for _, cache := range []string{CacheAPIResources, CacheNamespace, CacheObject} {
+		CacheLookups.WithLabelValues(cache, "hit")
+		CacheLookups.WithLabelValues(cache, "miss")
+	}
+}
+
+// CacheHit records a hit of cache
+func CacheHit(cache string) {
+	CacheLookups.WithLabelValues(cache, "hit").Inc()
+}
+
+// CacheMiss records a miss of cache
+func CacheMiss(cache string) {
+	CacheLookups.WithLabelValues(cache, "miss").Inc()
+}
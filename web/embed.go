@@ -0,0 +1,9 @@
+// Package web holds the UI, embedded in the binary so it runs from any directory
+package web
+
+import "embed"
+
+// FS holds index.html and the assets it loads
+//
+//go:embed index.html simple.html css js
+var FS embed.FS